@@ -26,6 +26,8 @@ const (
 	PromptTriggerNextView        string = "TriggerNextView"
 	PromptTogglePacemakerMode    string = "TogglePacemakerMode"
 	PromptShowLatestBlockInStore string = "ShowLatestBlockInStore"
+	PromptUseRainTree            string = "UseRainTree"
+	PromptUseStdNetwork          string = "UseStdNetwork"
 
 	defaultConfigPath  = "build/config/config1.json"
 	defaultGenesisPath = "build/config/genesis.json"
@@ -37,6 +39,8 @@ var items = []string{
 	PromptTriggerNextView,
 	PromptTogglePacemakerMode,
 	PromptShowLatestBlockInStore,
+	PromptUseRainTree,
+	PromptUseStdNetwork,
 }
 
 // A P2P module is initialized in order to broadcast a message to the local network
@@ -129,6 +133,18 @@ func handleSelect(selection string) {
 			Message: nil,
 		}
 		sendDebugMessage(m)
+	case PromptUseRainTree:
+		m := &debug.DebugMessage{
+			Action:  debug.DebugMessageAction_DEBUG_P2P_USE_RAIN_TREE,
+			Message: nil,
+		}
+		broadcastDebugMessage(m)
+	case PromptUseStdNetwork:
+		m := &debug.DebugMessage{
+			Action:  debug.DebugMessageAction_DEBUG_P2P_USE_STD_NETWORK,
+			Message: nil,
+		}
+		broadcastDebugMessage(m)
 	default:
 		log.Println("Selection not yet implemented...", selection)
 	}
@@ -0,0 +1,83 @@
+package main
+
+// The schema migration tool migrates, rolls back or prints the migration status of the schema of a node, as
+// configured in its config file. Run `go run ./app/db_migrate -help` for usage.
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/pokt-network/pocket/persistence"
+	"github.com/pokt-network/pocket/persistence/types"
+)
+
+const (
+	commandUp       = "up"
+	commandRollback = "rollback"
+	commandStatus   = "status"
+)
+
+func main() {
+	configFilename := flag.String("config", "build/config/config1.json", "Relative or absolute path to the config file of the node.")
+	version := flag.Int64("version", -1, "Version to migrate the schema to with the up command. Defaults to the latest version.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <%s|%s|%s>\n", os.Args[0], commandUp, commandRollback, commandStatus)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := new(persistence.PersistenceModule).InitConfig(*configFilename)
+	if err != nil {
+		log.Fatalf("Failed to read the config file: %s", err)
+	}
+	migrator, err := persistence.NewSchemaMigrator(cfg.(*types.PersistenceConfig))
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %s", err)
+	}
+	defer migrator.Close()
+
+	ctx := context.Background()
+	switch command := flag.Arg(0); command {
+	case commandUp:
+		targetVersion := *version
+		if targetVersion < 0 {
+			targetVersion = types.LatestSchemaVersion()
+		}
+		err = migrator.Migrate(ctx, targetVersion)
+	case commandRollback:
+		err = migrator.Rollback(ctx)
+	case commandStatus:
+		err = printStatus(ctx, migrator)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("Failed to %s the schema: %s", flag.Arg(0), err)
+	}
+}
+
+func printStatus(ctx context.Context, migrator *persistence.SchemaMigrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = "applied at " + status.AppliedAt.String()
+		}
+		if status.Version > types.LatestSchemaVersion() {
+			appliedAt += " (unknown to this version of the node)"
+		}
+		fmt.Printf("%d\t%s\t%s\n", status.Version, appliedAt, status.Description)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package main
+
+// The RainTree simulator shows how a broadcast fans out over a network of synthetic peers so RainTree
+// parameters can be tuned before rolling them out. Run `go run ./app/raintree_simulator -help` for usage.
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pokt-network/pocket/p2p/raintree"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatDOT  = "dot"
+)
+
+func main() {
+	numNodes := flag.Int("num_nodes", 27, "Number of peers in the network.")
+	originator := flag.Int("originator", 0, "Index of the peer broadcasting the message.")
+	failedNodes := flag.String("failed_nodes", "", "Comma separated indices of the peers that are down (e.g. 3,9,12).")
+	failureRate := flag.Float64("failure_rate", 0, "Probability [0, 1) of each peer, other than the originator, being down.")
+	seed := flag.Int64("seed", 1, "Seed used to pick the failed peers when `failure_rate` is set.")
+	format := flag.String("format", formatText, "Output format: text, json or dot.")
+	verbose := flag.Bool("verbose", false, "Include every message sent in the text output.")
+	outputFilename := flag.String("output", "", "File to write the output to. Defaults to stdout.")
+	flag.Parse()
+
+	failed, err := getFailedNodes(*numNodes, *originator, *failedNodes, *failureRate, *seed)
+	if err != nil {
+		log.Fatalf("Invalid failed nodes: %s", err)
+	}
+
+	result, err := raintree.Simulate(raintree.SimulationConfig{
+		NumNodes:    *numNodes,
+		Originator:  *originator,
+		FailedNodes: failed,
+	})
+	if err != nil {
+		log.Fatalf("Failed to simulate the RainTree broadcast: %s", err)
+	}
+
+	out := os.Stdout
+	if *outputFilename != "" {
+		if out, err = os.Create(*outputFilename); err != nil {
+			log.Fatalf("Failed to create the output file: %s", err)
+		}
+		defer out.Close()
+	}
+
+	switch *format {
+	case formatText:
+		err = writeText(out, result, *verbose)
+	case formatJSON:
+		err = writeJSON(out, result)
+	case formatDOT:
+		err = writeDOT(out, result)
+	default:
+		log.Fatalf("Unknown output format: %s", *format)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write the simulation result: %s", err)
+	}
+}
+
+func getFailedNodes(numNodes, originator int, failedNodes string, failureRate float64, seed int64) (map[int]bool, error) {
+	if failureRate < 0 || failureRate >= 1 {
+		return nil, fmt.Errorf("the failure rate must be in [0, 1): %f", failureRate)
+	}
+	failed := make(map[int]bool)
+	for _, s := range strings.Split(failedNodes, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		node, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		if node < 0 || node >= numNodes {
+			return nil, fmt.Errorf("node %d is not in the network of %d nodes", node, numNodes)
+		}
+		failed[node] = true
+	}
+	rng := rand.New(rand.NewSource(seed))
+	for node := 0; node < numNodes; node++ {
+		if node != originator && rng.Float64() < failureRate {
+			failed[node] = true
+		}
+	}
+	return failed, nil
+}
+
+func nodeName(node int) string {
+	return fmt.Sprintf("node_%d", node)
+}
+
+func writeText(w io.Writer, result *raintree.SimulationResult, verbose bool) error {
+	var s strings.Builder
+	fmt.Fprintf(&s, "Nodes: %d, levels: %d, originator: %s\n", result.NumNodes, result.MaxNumLevels, nodeName(result.Originator))
+	fmt.Fprintf(&s, "Failed nodes (%d): %s\n", len(result.FailedNodes), nodeNames(result.FailedNodes))
+	fmt.Fprintf(&s, "Unreached nodes (%d): %s\n", len(result.UnreachedNodes), nodeNames(result.UnreachedNodes))
+	fmt.Fprintf(&s, "Coverage: %.2f%%, redundancy: %.2f messages per reached node\n\n", 100*result.Coverage, result.Redundancy)
+
+	fmt.Fprintf(&s, "%-6s %-16s %-9s %-10s %s\n", "Level", "Addr book length", "Messages", "Delivered", "New nodes")
+	for _, level := range result.Levels {
+		fmt.Fprintf(&s, "%-6d %-16d %-9d %-10d %d\n", level.Level, level.AddrBookLength, level.NumMessages, level.NumDelivered, level.NumNewNodes)
+	}
+
+	if verbose {
+		s.WriteString("\nMessages:\n")
+		for _, msg := range sortedMessages(result) {
+			status := ""
+			if !msg.Delivered {
+				status = " (failed)"
+			}
+			fmt.Fprintf(&s, "  [level %d] %s -> %s%s\n", msg.Level, nodeName(msg.From), nodeName(msg.To), status)
+		}
+	}
+
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+func writeJSON(w io.Writer, result *raintree.SimulationResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// writeDOT writes the broadcast as a Graphviz graph (e.g. `dot -Tsvg -o raintree.svg`) where edges are
+// labelled by level, failed nodes are red and the originator is double circled.
+func writeDOT(w io.Writer, result *raintree.SimulationResult) error {
+	failed := make(map[int]bool, len(result.FailedNodes))
+	for _, node := range result.FailedNodes {
+		failed[node] = true
+	}
+	unreached := make(map[int]bool, len(result.UnreachedNodes))
+	for _, node := range result.UnreachedNodes {
+		unreached[node] = true
+	}
+
+	var s strings.Builder
+	s.WriteString("digraph raintree {\n")
+	for node := 0; node < result.NumNodes; node++ {
+		attrs := []string{"shape=circle"}
+		switch {
+		case node == result.Originator:
+			attrs[0] = "shape=doublecircle"
+		case failed[node]:
+			attrs = append(attrs, "style=filled", "fillcolor=red")
+		case unreached[node]:
+			attrs = append(attrs, "style=filled", "fillcolor=gray")
+		}
+		fmt.Fprintf(&s, "  %q [%s];\n", nodeName(node), strings.Join(attrs, ", "))
+	}
+	for _, msg := range sortedMessages(result) {
+		attrs := fmt.Sprintf("label=\"L%d\"", msg.Level)
+		if !msg.Delivered {
+			attrs += ", style=dashed, color=red"
+		}
+		fmt.Fprintf(&s, "  %q -> %q [%s];\n", nodeName(msg.From), nodeName(msg.To), attrs)
+	}
+	s.WriteString("}\n")
+
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+func nodeNames(nodes []int) string {
+	if len(nodes) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, nodeName(node))
+	}
+	return strings.Join(names, ", ")
+}
+
+// sortedMessages returns the messages sorted from the highest level down, which reads like the RainTree.
+func sortedMessages(result *raintree.SimulationResult) []raintree.SimulatedMessage {
+	msgs := append([]raintree.SimulatedMessage{}, result.Messages...)
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return msgs[i].Level > msgs[j].Level
+	})
+	return msgs
+}
@@ -43,13 +43,13 @@ func (m *ConsensusModule) commitBlock(block *typesCons.Block) error {
 
 func (m *ConsensusModule) storeBlock(block *typesCons.Block, blockProtoBytes []byte) error {
 	store := m.utilityContext.GetPersistenceContext()
+	header := block.BlockHeader
 	// Store in KV Store
-	if err := store.StoreBlock(blockProtoBytes); err != nil {
+	if err := store.StoreBlock(blockProtoBytes, header.Hash, header.QuorumCertificate); err != nil {
 		return err
 	}
 
 	// Store in SQL Store
-	header := block.BlockHeader
 	if err := store.InsertBlock(uint64(header.Height), header.Hash, header.ProposerAddress, header.QuorumCertificate); err != nil {
 		return err
 	}
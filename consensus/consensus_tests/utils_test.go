@@ -334,6 +334,8 @@ func baseP2PMock(t *testing.T, testChannel modules.EventsChannel) *modulesMock.M
 
 	p2pMock.EXPECT().Start().Do(func() {}).AnyTimes()
 	p2pMock.EXPECT().SetBus(gomock.Any()).Do(func(modules.Bus) {}).AnyTimes()
+	// Consensus messages are delivered to the nodes by publishing them to the bus in these tests
+	p2pMock.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	p2pMock.EXPECT().
 		Broadcast(gomock.Any(), gomock.Any()).
 		Do(func(msg *anypb.Any, topic debug.PocketTopic) {
@@ -386,7 +388,7 @@ func baseUtilityContextMock(t *testing.T) *modulesMock.MockUtilityContext {
 	utilityContextMock.EXPECT().GetPersistenceContext().Return(persistenceContextMock).AnyTimes()
 
 	persistenceContextMock.EXPECT().StoreTransaction(gomock.Any()).Return(nil).AnyTimes()
-	persistenceContextMock.EXPECT().StoreBlock(gomock.Any()).Return(nil).AnyTimes()
+	persistenceContextMock.EXPECT().StoreBlock(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	persistenceContextMock.EXPECT().InsertBlock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 	return utilityContextMock
@@ -0,0 +1,71 @@
+package p2p
+
+import (
+	"log"
+
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"github.com/pokt-network/pocket/telemetry"
+)
+
+var _ typesP2P.PeerAwareTransport = &compressingDialer{}
+
+// compressingDialer compresses the data written to a peer with a codec the peer accepts.
+type compressingDialer struct {
+	typesP2P.Transport
+	peer cryptoPocket.Address
+	m    *p2pModule
+}
+
+// wrapDialersWithCompression makes the dialers of the peers in the address book compress the data they write.
+func (m *p2pModule) wrapDialersWithCompression(addrBook typesP2P.AddrBook) {
+	if !m.compressor.IsEnabled() {
+		return
+	}
+	for _, peer := range addrBook {
+		if _, ok := peer.Dialer.(*compressingDialer); ok || peer.Dialer == nil {
+			continue
+		}
+		peer.Dialer = &compressingDialer{
+			Transport: peer.Dialer,
+			peer:      peer.Address,
+			m:         m,
+		}
+	}
+}
+
+func (d *compressingDialer) Write(data []byte) error {
+	frame, codec, err := d.m.compressor.Compress(d.peer, data)
+	if err != nil {
+		return err
+	}
+	if codec != typesP2P.CompressionCodec_NoCompression {
+		d.m.emitCompressionMetrics(len(data), len(frame))
+	}
+	return d.Transport.Write(frame)
+}
+
+func (d *compressingDialer) ReadFrom() ([]byte, string, error) {
+	if t, ok := d.Transport.(typesP2P.PeerAwareTransport); ok {
+		return t.ReadFrom()
+	}
+	data, err := d.Transport.Read()
+	return data, "", err
+}
+
+func (d *compressingDialer) RemoteHost() string {
+	if t, ok := d.Transport.(typesP2P.PeerAwareTransport); ok {
+		return t.RemoteHost()
+	}
+	return ""
+}
+
+func (m *p2pModule) emitCompressionMetrics(uncompressedSize, compressedSize int) {
+	timeSeriesAgent := m.GetBus().GetTelemetryModule().GetTimeSeriesAgent()
+	if _, err := timeSeriesAgent.GaugeAdd(telemetry.P2P_UNCOMPRESSED_BYTES_SENT_TIMESERIES_METRIC_NAME, float64(uncompressedSize)); err != nil {
+		log.Println("[WARN] Error updating the uncompressed bytes metric: ", err)
+	}
+	if _, err := timeSeriesAgent.GaugeAdd(telemetry.P2P_COMPRESSED_BYTES_SENT_TIMESERIES_METRIC_NAME, float64(compressedSize)); err != nil {
+		log.Println("[WARN] Error updating the compressed bytes metric: ", err)
+	}
+}
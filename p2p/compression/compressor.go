@@ -0,0 +1,165 @@
+package compression
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+)
+
+const (
+	defaultMinSizeBytes = 1024 // 1KiB
+
+	// Compressed payloads are framed as [frameMarker][codec][codecs accepted by the sender][payload].
+	// A serialized protobuf never starts with a zero byte (i.e. field number 0 is invalid), so payloads
+	// sent by nodes with compression disabled are left untouched and can still be told apart.
+	frameMarker     = byte(0x00)
+	frameHeaderSize = 3
+)
+
+// Compressor compresses the payloads sent to peers with a codec they accept, and learns the codecs
+// accepted by every peer from the frames received from them. Until a peer's codecs are known, payloads
+// are sent uncompressed (but framed) so the peer learns about the codecs accepted by this node.
+type Compressor struct {
+	codecs         []typesP2P.CompressionCodec
+	acceptedCodecs byte // Bitmask of `codecs`
+	minSizeBytes   uint64
+	maxSizeBytes   uint64
+
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+
+	m          sync.RWMutex
+	peerCodecs map[string]byte // The bitmask of the codecs accepted by each peer, keyed by address
+}
+
+// NewCompressor returns a compressor for the codecs in the config. `maxSizeBytes` bounds the size of
+// decompressed payloads so a small malicious payload cannot exhaust the memory of the node.
+func NewCompressor(cfg *typesP2P.CompressionConfig, maxSizeBytes uint64) (*Compressor, error) {
+	c := &Compressor{
+		minSizeBytes: defaultMinSizeBytes,
+		maxSizeBytes: maxSizeBytes,
+		peerCodecs:   make(map[string]byte),
+	}
+	if minSize := cfg.GetMinSizeBytes(); minSize > 0 {
+		c.minSizeBytes = minSize
+	}
+	for _, codec := range cfg.GetCodecs() {
+		switch codec {
+		case typesP2P.CompressionCodec_ZstdCompression, typesP2P.CompressionCodec_SnappyCompression:
+		default:
+			return nil, fmt.Errorf("unsupported compression codec: %s", codec)
+		}
+		if c.acceptedCodecs&codecBit(codec) != 0 {
+			continue
+		}
+		c.codecs = append(c.codecs, codec)
+		c.acceptedCodecs |= codecBit(codec)
+	}
+
+	var err error
+	// The encoder and decoder are safe for concurrent use when used through EncodeAll/DecodeAll
+	if c.zstdEncoder, err = zstd.NewWriter(nil); err != nil {
+		return nil, err
+	}
+	if c.zstdDecoder, err = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxSizeBytes)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// IsEnabled returns whether any codec is configured. Payloads are neither framed nor compressed otherwise.
+func (c *Compressor) IsEnabled() bool {
+	return len(c.codecs) > 0
+}
+
+// Compress returns the frame to send `data` to `peer` along with the codec it was compressed with.
+func (c *Compressor) Compress(peer cryptoPocket.Address, data []byte) ([]byte, typesP2P.CompressionCodec, error) {
+	if !c.IsEnabled() {
+		return data, typesP2P.CompressionCodec_NoCompression, nil
+	}
+
+	codec := typesP2P.CompressionCodec_NoCompression
+	if uint64(len(data)) >= c.minSizeBytes {
+		codec = c.getCodecForPeer(peer)
+	}
+
+	payload := data
+	switch codec {
+	case typesP2P.CompressionCodec_ZstdCompression:
+		payload = c.zstdEncoder.EncodeAll(data, nil)
+	case typesP2P.CompressionCodec_SnappyCompression:
+		payload = snappy.Encode(nil, data)
+	}
+	// Some payloads (e.g. already compressed or random data) do not compress well
+	if len(payload) >= len(data) {
+		codec, payload = typesP2P.CompressionCodec_NoCompression, data
+	}
+
+	frame := make([]byte, frameHeaderSize, frameHeaderSize+len(payload))
+	frame[0], frame[1], frame[2] = frameMarker, byte(codec), c.acceptedCodecs
+	return append(frame, payload...), codec, nil
+}
+
+// Decompress returns the payload of a frame received from `peer`, recording the codecs the peer accepts.
+// Data that is not framed is returned as is since it was sent by a node with compression disabled.
+func (c *Compressor) Decompress(peer cryptoPocket.Address, frame []byte) ([]byte, error) {
+	if len(frame) == 0 || frame[0] != frameMarker {
+		return frame, nil
+	}
+	if len(frame) < frameHeaderSize {
+		return nil, fmt.Errorf("compressed frame is too short: %d bytes", len(frame))
+	}
+
+	codec, peerCodecs, payload := typesP2P.CompressionCodec(frame[1]), frame[2], frame[frameHeaderSize:]
+	if peer != nil {
+		c.m.Lock()
+		c.peerCodecs[peer.String()] = peerCodecs
+		c.m.Unlock()
+	}
+
+	if codec != typesP2P.CompressionCodec_NoCompression && c.acceptedCodecs&codecBit(codec) == 0 {
+		return nil, fmt.Errorf("received a payload compressed with %s which is not accepted", codec)
+	}
+	switch codec {
+	case typesP2P.CompressionCodec_NoCompression:
+		return payload, nil
+	case typesP2P.CompressionCodec_ZstdCompression:
+		return c.zstdDecoder.DecodeAll(payload, nil)
+	case typesP2P.CompressionCodec_SnappyCompression:
+		decodedLen, err := snappy.DecodedLen(payload)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(decodedLen) > c.maxSizeBytes {
+			return nil, fmt.Errorf("decompressed payload of %d bytes exceeds the max size of %d bytes", decodedLen, c.maxSizeBytes)
+		}
+		return snappy.Decode(nil, payload)
+	default:
+		return nil, fmt.Errorf("unsupported compression codec: %s", codec)
+	}
+}
+
+// getCodecForPeer returns the first codec, in order of preference, accepted by the peer.
+func (c *Compressor) getCodecForPeer(peer cryptoPocket.Address) typesP2P.CompressionCodec {
+	if peer == nil {
+		return typesP2P.CompressionCodec_NoCompression
+	}
+	c.m.RLock()
+	peerCodecs := c.peerCodecs[peer.String()]
+	c.m.RUnlock()
+
+	for _, codec := range c.codecs {
+		if peerCodecs&codecBit(codec) != 0 {
+			return codec
+		}
+	}
+	return typesP2P.CompressionCodec_NoCompression
+}
+
+func codecBit(codec typesP2P.CompressionCodec) byte {
+	return 1 << uint(codec)
+}
@@ -0,0 +1,118 @@
+package compression
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const testMaxSizeBytes = 1024 * 1024
+
+func TestCompressor_Negotiation(t *testing.T) {
+	zstdOnly := newTestCompressor(t, typesP2P.CompressionCodec_ZstdCompression)
+	snappyFirst := newTestCompressor(t, typesP2P.CompressionCodec_SnappyCompression, typesP2P.CompressionCodec_ZstdCompression)
+	zstdOnlyAddr, snappyFirstAddr := newTestAddress(t), newTestAddress(t)
+
+	data := bytes.Repeat([]byte("pocket"), 1000)
+
+	// The codecs of the peer are unknown until it sends something, so the data is not compressed
+	frame, codec, err := snappyFirst.Compress(zstdOnlyAddr, data)
+	require.NoError(t, err)
+	require.Equal(t, typesP2P.CompressionCodec_NoCompression, codec)
+	decompressed, err := zstdOnly.Decompress(snappyFirstAddr, frame)
+	require.NoError(t, err)
+	require.Equal(t, data, decompressed)
+
+	// zstdOnly learned that snappyFirst accepts zstd, which is the only codec they have in common
+	frame, codec, err = zstdOnly.Compress(snappyFirstAddr, data)
+	require.NoError(t, err)
+	require.Equal(t, typesP2P.CompressionCodec_ZstdCompression, codec)
+	require.Less(t, len(frame), len(data))
+	decompressed, err = snappyFirst.Decompress(zstdOnlyAddr, frame)
+	require.NoError(t, err)
+	require.Equal(t, data, decompressed)
+
+	// snappyFirst prefers snappy, but zstdOnly does not accept it
+	frame, codec, err = snappyFirst.Compress(zstdOnlyAddr, data)
+	require.NoError(t, err)
+	require.Equal(t, typesP2P.CompressionCodec_ZstdCompression, codec)
+	decompressed, err = zstdOnly.Decompress(snappyFirstAddr, frame)
+	require.NoError(t, err)
+	require.Equal(t, data, decompressed)
+}
+
+func TestCompressor_SkipsSmallAndIncompressiblePayloads(t *testing.T) {
+	c := newTestCompressor(t, typesP2P.CompressionCodec_SnappyCompression)
+	peer := newTestAddress(t)
+	_, err := c.Decompress(peer, []byte{frameMarker, 0, c.acceptedCodecs})
+	require.NoError(t, err)
+
+	_, codec, err := c.Compress(peer, bytes.Repeat([]byte{1}, defaultMinSizeBytes-1))
+	require.NoError(t, err)
+	require.Equal(t, typesP2P.CompressionCodec_NoCompression, codec)
+
+	random := make([]byte, 4*defaultMinSizeBytes)
+	_, err = rand.Read(random)
+	require.NoError(t, err)
+	_, codec, err = c.Compress(peer, random)
+	require.NoError(t, err)
+	require.Equal(t, typesP2P.CompressionCodec_NoCompression, codec)
+}
+
+func TestCompressor_Disabled(t *testing.T) {
+	c, err := NewCompressor(nil, testMaxSizeBytes)
+	require.NoError(t, err)
+	require.False(t, c.IsEnabled())
+
+	data := []byte("unframed data")
+	frame, _, err := c.Compress(newTestAddress(t), data)
+	require.NoError(t, err)
+	require.Equal(t, data, frame, "data should be sent as is")
+
+	// Unframed data from nodes with compression disabled is also handled by nodes with it enabled
+	enabled := newTestCompressor(t, typesP2P.CompressionCodec_ZstdCompression)
+	decompressed, err := enabled.Decompress(newTestAddress(t), frame)
+	require.NoError(t, err)
+	require.Equal(t, data, decompressed)
+}
+
+func TestCompressor_RejectsInvalidFrames(t *testing.T) {
+	_, err := NewCompressor(&typesP2P.CompressionConfig{Codecs: []typesP2P.CompressionCodec{100}}, testMaxSizeBytes)
+	require.Error(t, err)
+
+	c := newTestCompressor(t, typesP2P.CompressionCodec_ZstdCompression)
+	peer := newTestAddress(t)
+
+	_, err = c.Decompress(peer, []byte{frameMarker, 1})
+	require.Error(t, err, "frame too short")
+
+	_, err = c.Decompress(peer, []byte{frameMarker, byte(typesP2P.CompressionCodec_SnappyCompression), 0, 1, 2})
+	require.Error(t, err, "codec not accepted")
+
+	// Payloads that decompress beyond the max size are rejected
+	sender := newTestCompressor(t, typesP2P.CompressionCodec_ZstdCompression, typesP2P.CompressionCodec_SnappyCompression)
+	_, err = sender.Decompress(peer, []byte{frameMarker, 0, c.acceptedCodecs})
+	require.NoError(t, err)
+	frame, codec, err := sender.Compress(peer, make([]byte, 2*testMaxSizeBytes))
+	require.NoError(t, err)
+	require.Equal(t, typesP2P.CompressionCodec_ZstdCompression, codec)
+	_, err = c.Decompress(peer, frame)
+	require.Error(t, err)
+}
+
+func newTestCompressor(t *testing.T, codecs ...typesP2P.CompressionCodec) *Compressor {
+	c, err := NewCompressor(&typesP2P.CompressionConfig{Codecs: codecs}, testMaxSizeBytes)
+	require.NoError(t, err)
+	require.True(t, c.IsEnabled())
+	return c
+}
+
+func newTestAddress(t *testing.T) cryptoPocket.Address {
+	addr, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+	return addr
+}
@@ -0,0 +1,64 @@
+package p2p
+
+import (
+	"bytes"
+	"sync/atomic"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/pokt-network/pocket/p2p/compression"
+	"github.com/pokt-network/pocket/p2p/simnet"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	"github.com/pokt-network/pocket/shared/modules"
+	modulesMock "github.com/pokt-network/pocket/shared/modules/mocks"
+	"github.com/pokt-network/pocket/telemetry"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCompression_EndToEnd(t *testing.T) {
+	var numCompressedMessages int32
+	telemetryMock := prepareTelemetryMock(t)
+	timeSeriesAgentMock := telemetryMock.GetTimeSeriesAgent().(*modulesMock.MockTimeSeriesAgent)
+	timeSeriesAgentMock.EXPECT().GaugeAdd(telemetry.P2P_UNCOMPRESSED_BYTES_SENT_TIMESERIES_METRIC_NAME, gomock.Any()).AnyTimes()
+	timeSeriesAgentMock.EXPECT().GaugeAdd(telemetry.P2P_COMPRESSED_BYTES_SENT_TIMESERIES_METRIC_NAME, gomock.Any()).Do(func(string, float64) {
+		atomic.AddInt32(&numCompressedMessages, 1)
+	}).AnyTimes()
+
+	hub := simnet.NewHub(simnet.HubConfig{}, clock.New())
+	p2pModules := startSimulatedP2PModules(t, 2, hub, func(consensusMock *modulesMock.MockConsensusModule) modules.Bus {
+		busMock := modulesMock.NewMockBus(gomock.NewController(t))
+		busMock.EXPECT().GetConsensusModule().Return(consensusMock).AnyTimes()
+		busMock.EXPECT().GetTelemetryModule().Return(telemetryMock).AnyTimes()
+		return busMock
+	})
+	for _, p2pMod := range p2pModules {
+		compressor, err := compression.NewCompressor(&typesP2P.CompressionConfig{
+			Codecs: []typesP2P.CompressionCodec{typesP2P.CompressionCodec_ZstdCompression},
+		}, defaultMaxMessageSizeBytes)
+		require.NoError(t, err)
+		p2pMod.compressor = compressor
+		p2pMod.wrapDialersWithCompression(p2pMod.network.GetAddrBook())
+	}
+	requester := p2pModules[validatorId(t, 1)]
+	responder := p2pModules[validatorId(t, 2)]
+
+	largePayload := bytes.Repeat([]byte("block"), 64*1024)
+	require.NoError(t, responder.RegisterRequestHandler(&wrapperspb.BytesValue{}, func(*anypb.Any) (*anypb.Any, error) {
+		return anypb.New(wrapperspb.Bytes(largePayload))
+	}))
+
+	anyReq, err := anypb.New(wrapperspb.Bytes(nil))
+	require.NoError(t, err)
+
+	// The responder learns the codecs accepted by the requester from the request, so the response is compressed
+	anyResp, err := requester.Request(responder.address, anyReq)
+	require.NoError(t, err)
+	resp := &wrapperspb.BytesValue{}
+	require.NoError(t, anypb.UnmarshalTo(anyResp, resp, proto.UnmarshalOptions{}))
+	require.Equal(t, largePayload, resp.Value)
+	require.GreaterOrEqual(t, atomic.LoadInt32(&numCompressedMessages), int32(1))
+}
@@ -0,0 +1,21 @@
+package p2p
+
+import (
+	"log"
+
+	"github.com/pokt-network/pocket/shared/debug"
+)
+
+func (m *p2pModule) HandleDebugMessage(debugMessage *debug.DebugMessage) error {
+	switch debugMessage.Action {
+	case debug.DebugMessageAction_DEBUG_P2P_USE_RAIN_TREE:
+		log.Println("[DEBUG] Sending messages with RainTree")
+		m.setUseRainTree(true)
+	case debug.DebugMessageAction_DEBUG_P2P_USE_STD_NETWORK:
+		log.Println("[DEBUG] Sending messages with stdnetwork")
+		m.setUseRainTree(false)
+	default:
+		log.Printf("Debug message not handled by p2p module: %s \n", debugMessage.Message)
+	}
+	return nil
+}
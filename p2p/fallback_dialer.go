@@ -0,0 +1,207 @@
+package p2p
+
+import (
+	"log"
+	"sync"
+
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"github.com/pokt-network/pocket/shared/modules"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+var _ typesP2P.PeerAwareTransport = &fallbackDialer{}
+
+// fallbackDialer writes to a peer through its service url and, if it cannot be dialed, through the addresses the
+// peer advertises (e.g. an IPv6 address of a node whose service url is IPv4). The last address that could be
+// dialed is tried first.
+type fallbackDialer struct {
+	cfg modules.P2PConfig
+
+	mu      sync.RWMutex
+	urls    []string
+	dialers []typesP2P.Transport
+	current int
+}
+
+// wrapDialersWithFallbacks lets the dialers of the peers in the address book fall back to the addresses the peers
+// advertise once they are known (see `updateAdvertisedAddresses`).
+func (m *p2pModule) wrapDialersWithFallbacks(addrBook typesP2P.AddrBook) {
+	for _, peer := range addrBook {
+		if _, ok := peer.Dialer.(*fallbackDialer); ok || peer.Dialer == nil {
+			continue
+		}
+		peer.Dialer = &fallbackDialer{
+			cfg:     m.p2pConfig,
+			urls:    []string{peer.ServiceUrl},
+			dialers: []typesP2P.Transport{peer.Dialer},
+		}
+	}
+}
+
+// requestAdvertisedAddresses requests the address book of every peer in `addrBook` on startup, one peer at a time,
+// so their dialers can fall back to the addresses they advertise before their service url becomes unreachable.
+func (m *p2pModule) requestAdvertisedAddresses(addrBook typesP2P.AddrBook) {
+	anyReq, err := anypb.New(&typesP2P.AddrBookRequest{})
+	if err != nil {
+		log.Printf("[ERROR] Error creating the address book request: %v\n", err)
+		return
+	}
+	for _, peer := range addrBook {
+		select {
+		case <-m.stopped:
+			return
+		default:
+		}
+		if peer.Address.Equals(m.address) {
+			continue
+		}
+		// The advertised addresses are picked up from the response by `Request`
+		if _, err := m.Request(peer.Address, anyReq); err != nil {
+			log.Printf("[WARN] Error requesting the address book of peer %s: %v\n", peer.Address, err)
+		}
+	}
+}
+
+// updateAdvertisedAddresses adds the addresses `peer` advertises in its response to an address book request to the
+// addresses its dialer falls back to. Only the addresses a peer advertises for itself are trusted, since they are
+// not signed.
+func (m *p2pModule) updateAdvertisedAddresses(peer cryptoPocket.Address, resp *anypb.Any) {
+	if !resp.MessageIs(&typesP2P.AddrBookResponse{}) {
+		return
+	}
+	addrBookResp := &typesP2P.AddrBookResponse{}
+	if err := anypb.UnmarshalTo(resp, addrBookResp, proto.UnmarshalOptions{}); err != nil {
+		log.Printf("[WARN] Error unmarshalling the address book of peer %s: %v\n", peer, err)
+		return
+	}
+
+	var advertisedAddresses []string
+	for _, peerInfo := range addrBookResp.Peers {
+		if peer.Equals(peerInfo.Address) {
+			advertisedAddresses = peerInfo.AdvertisedAddresses
+			break
+		}
+	}
+	if len(advertisedAddresses) == 0 {
+		return
+	}
+
+	for _, networkPeer := range m.getNetwork().GetAddrBook() {
+		if !networkPeer.Address.Equals(peer) {
+			continue
+		}
+		dialer := networkPeer.Dialer
+		if d, ok := dialer.(*compressingDialer); ok {
+			dialer = d.Transport
+		}
+		if d, ok := dialer.(*fallbackDialer); ok {
+			d.addURLs(advertisedAddresses)
+		}
+		return
+	}
+}
+
+// addURLs adds the urls the dialer does not write to yet, skipping the ones a dialer cannot be created for.
+func (d *fallbackDialer) addURLs(urls []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, url := range urls {
+		if d.hasURL(url) {
+			continue
+		}
+		dialer, err := CreateDialer(d.cfg, url)
+		if err != nil {
+			log.Printf("[WARN] Error creating a dialer for advertised address %s: %v\n", url, err)
+			continue
+		}
+		d.urls = append(d.urls, url)
+		d.dialers = append(d.dialers, dialer)
+	}
+}
+
+func (d *fallbackDialer) hasURL(url string) bool {
+	for _, u := range d.urls {
+		if u == url {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *fallbackDialer) IsListener() bool {
+	return false
+}
+
+func (d *fallbackDialer) Read() ([]byte, error) {
+	data, _, err := d.ReadFrom()
+	return data, err
+}
+
+func (d *fallbackDialer) ReadFrom() ([]byte, string, error) {
+	dialer := d.currentDialer()
+	if t, ok := dialer.(typesP2P.PeerAwareTransport); ok {
+		return t.ReadFrom()
+	}
+	data, err := dialer.Read()
+	return data, "", err
+}
+
+func (d *fallbackDialer) RemoteHost() string {
+	if t, ok := d.currentDialer().(typesP2P.PeerAwareTransport); ok {
+		return t.RemoteHost()
+	}
+	return ""
+}
+
+// Write writes the data through the first address of the peer that can be dialed, starting with the last one
+// that could.
+func (d *fallbackDialer) Write(data []byte) error {
+	d.mu.RLock()
+	dialers := d.dialers
+	current := d.current
+	d.mu.RUnlock()
+
+	var err error
+	for i := range dialers {
+		idx := (current + i) % len(dialers)
+		if err = dialers[idx].Write(data); err != nil {
+			continue
+		}
+		if idx != current {
+			log.Printf("Falling back to address %s of the peer after failing to write to the previous one\n", d.url(idx))
+			d.mu.Lock()
+			d.current = idx
+			d.mu.Unlock()
+		}
+		return nil
+	}
+	return err
+}
+
+func (d *fallbackDialer) Close() error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var err error
+	for _, dialer := range d.dialers {
+		if closeErr := dialer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (d *fallbackDialer) currentDialer() typesP2P.Transport {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.dialers[d.current]
+}
+
+func (d *fallbackDialer) url(idx int) string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.urls[idx]
+}
@@ -0,0 +1,130 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/pokt-network/pocket/p2p/stdnetwork"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	modulesMock "github.com/pokt-network/pocket/shared/modules/mocks"
+	"github.com/pokt-network/pocket/shared/test_artifacts"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestFallbackDialer_WritesToAdvertisedAddress(t *testing.T) {
+	cfg := &typesP2P.P2PConfig{ConnectionType: typesP2P.ConnectionType_TCPConnection}
+	listener, err := createTCPListener(&typesP2P.P2PConfig{ListenAddresses: []string{"127.0.0.1:0"}})
+	require.NoError(t, err)
+	defer listener.Close()
+	advertisedAddress := listener.ListenAddrs()[0].String()
+
+	// Nothing listens on the service url of the peer anymore
+	unreachable, err := net.Listen(TCPNetworkLayerProtocol, "127.0.0.1:0")
+	require.NoError(t, err)
+	serviceUrl := unreachable.Addr().String()
+	require.NoError(t, unreachable.Close())
+
+	peer, responder := newFallbackTestPeer(t, cfg, serviceUrl)
+	m := &p2pModule{p2pConfig: cfg, network: stdnetwork.NewNetwork(nil, typesP2P.AddrBook{peer}, nil, nil)}
+	m.wrapDialersWithFallbacks(m.network.GetAddrBook())
+	require.Error(t, peer.Dialer.Write([]byte("unreachable")))
+
+	// The addresses advertised for other peers are ignored since they are not signed
+	other, err := cryptoPocket.GeneratePrivateKey()
+	require.NoError(t, err)
+	m.updateAdvertisedAddresses(responder, newAddrBookResponse(t, other.Address(), advertisedAddress))
+	require.Error(t, peer.Dialer.Write([]byte("unreachable")))
+
+	m.updateAdvertisedAddresses(responder, newAddrBookResponse(t, responder, advertisedAddress))
+	require.NoError(t, peer.Dialer.Write([]byte("fallback")))
+	data, _, err := listener.ReadFrom()
+	require.NoError(t, err)
+	require.Equal(t, "fallback", string(data))
+
+	// The address that could be dialed is tried first from now on
+	require.Equal(t, advertisedAddress, peer.Dialer.(*fallbackDialer).url(peer.Dialer.(*fallbackDialer).current))
+	require.Equal(t, "127.0.0.1", peer.Dialer.(typesP2P.PeerAwareTransport).RemoteHost())
+}
+
+func TestFallbackDialer_AdvertisedAddressesRequestedOnStart(t *testing.T) {
+	configs, genesisState := createConfigs(t, 2)
+	for _, config := range configs {
+		p2pConfig := config.P2P.(*typesP2P.P2PConfig)
+		p2pConfig.IsEmptyConnectionType = false
+		p2pConfig.ConnectionType = typesP2P.ConnectionType_TCPConnection
+		p2pConfig.ListenAddresses = []string{"127.0.0.1:0"}
+	}
+	p2pModules := prepareP2PModules(t, configs)
+	requester, responder := p2pModules[validatorId(t, 1)], p2pModules[validatorId(t, 2)]
+
+	// The service urls in the genesis are the addresses the modules listen on
+	for i, validator := range genesisState.PersistenceGenesisState.GetVals() {
+		listenAddr := p2pModules[validatorId(t, i+1)].listener.(*tcpConn).ListenAddrs()[0].(*net.TCPAddr)
+		validator.(*test_artifacts.MockActor).GenericParam = listenAddr.String()
+	}
+	responderAddr := responder.listener.(*tcpConn).ListenAddrs()[0].(*net.TCPAddr)
+	advertisedAddress := fmt.Sprintf("localhost:%d", responderAddr.Port)
+	responder.p2pConfig.(*typesP2P.P2PConfig).AdvertisedAddresses = []string{responderAddr.String(), advertisedAddress}
+
+	consensusMock := prepareConsensusMock(t, genesisState)
+	telemetryMock := prepareTelemetryMock(t)
+	for _, p2pMod := range p2pModules {
+		busMock := modulesMock.NewMockBus(gomock.NewController(t))
+		busMock.EXPECT().GetConsensusModule().Return(consensusMock).AnyTimes()
+		busMock.EXPECT().GetTelemetryModule().Return(telemetryMock).AnyTimes()
+		p2pMod.SetBus(busMock)
+	}
+	for _, p2pMod := range p2pModules {
+		require.NoError(t, p2pMod.Start())
+		t.Cleanup(func(p2pMod *p2pModule) func() {
+			return func() { require.NoError(t, p2pMod.Stop()) }
+		}(p2pMod))
+	}
+
+	var dialer *fallbackDialer
+	for _, peer := range requester.getNetwork().GetAddrBook() {
+		if !peer.Address.Equals(responder.address) {
+			continue
+		}
+		d := peer.Dialer
+		if c, ok := d.(*compressingDialer); ok {
+			d = c.Transport
+		}
+		dialer = d.(*fallbackDialer)
+	}
+	require.NotNil(t, dialer)
+	require.Eventually(t, func() bool {
+		dialer.mu.RLock()
+		defer dialer.mu.RUnlock()
+		return dialer.hasURL(advertisedAddress)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func newFallbackTestPeer(t *testing.T, cfg *typesP2P.P2PConfig, serviceUrl string) (*typesP2P.NetworkPeer, cryptoPocket.Address) {
+	privateKey, err := cryptoPocket.GeneratePrivateKey()
+	require.NoError(t, err)
+	dialer, err := CreateDialer(cfg, serviceUrl)
+	require.NoError(t, err)
+	return &typesP2P.NetworkPeer{
+		Dialer:     dialer,
+		PublicKey:  privateKey.PublicKey(),
+		Address:    privateKey.Address(),
+		ServiceUrl: serviceUrl,
+	}, privateKey.Address()
+}
+
+func newAddrBookResponse(t *testing.T, peer cryptoPocket.Address, advertisedAddresses ...string) *anypb.Any {
+	resp, err := anypb.New(&typesP2P.AddrBookResponse{Peers: []*typesP2P.PeerInfo{{
+		Address:             peer,
+		ServiceUrl:          advertisedAddresses[0],
+		AdvertisedAddresses: advertisedAddresses,
+	}}})
+	require.NoError(t, err)
+	return resp
+}
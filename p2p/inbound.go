@@ -0,0 +1,205 @@
+package p2p
+
+import (
+	"log"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pokt-network/pocket/p2p/ratelimit"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"github.com/pokt-network/pocket/shared/debug"
+	"github.com/pokt-network/pocket/shared/modules"
+	"github.com/pokt-network/pocket/telemetry"
+)
+
+const (
+	defaultMaxMessageSizeBytes = 16 * 1024 * 1024 // 16MiB
+
+	defaultPerPeerMsgsPerSec = 100
+	defaultPerPeerBurst      = 200
+	defaultGlobalMsgsPerSec  = 1000
+	defaultGlobalBurst       = 2000
+	defaultNumInboundWorkers = 16
+	defaultInboundQueueSize  = 1000
+
+	// The size of the queues holding decoded events until they are routed to subscribers. Consensus events
+	// are kept separately from other events (e.g. tx gossip) so they can be prioritized.
+	// IMPROVE: Make these configurable if the defaults turn out to be insufficient.
+	consensusEventsQueueSize = 1000
+	otherEventsQueueSize     = 1000
+)
+
+// The reasons an inbound message can be dropped, used as a label of the corresponding telemetry event
+const (
+	dropReasonMaxMessageSize  = "max_message_size"
+	dropReasonPeerRateLimit   = "per_peer_rate_limit"
+	dropReasonGlobalRateLimit = "global_rate_limit"
+	dropReasonQueueFull       = "inbound_queue_full"
+	dropReasonBusBackpressure = "bus_backpressure"
+	dropReasonRequestQueue    = "request_queue_full"
+)
+
+type inboundMessage struct {
+	data   []byte
+	sender cryptoPocket.Address // nil if the message cannot be attributed to a peer in the address book
+}
+
+// Inbound messages go through the following pipeline so that no peer can make the node spawn an unbounded
+// number of goroutines, nor starve consensus messages:
+//  1. The listener loop drops messages that are too large, from banned peers or above the rate limits
+//  2. Accepted messages are buffered in a bounded queue and handled by a fixed number of workers
+//  3. Decoded events are routed to subscribers (or the bus) by a single dispatcher which always drains consensus events first
+type inboundLimits struct {
+	maxMessageSize uint64
+	numWorkers     uint32
+
+	perPeerLimiter *ratelimit.KeyedLimiter
+	globalLimiter  *ratelimit.TokenBucket
+
+	inboundQueue    chan *inboundMessage
+	consensusEvents chan *debug.PocketEvent
+	otherEvents     chan *debug.PocketEvent
+}
+
+func newInboundLimits(cfg *typesP2P.P2PConfig, clock clock.Clock) *inboundLimits {
+	limitsCfg := cfg.GetInboundLimitsConfig()
+
+	perPeerRate := limitsCfg.GetPerPeerMsgsPerSec()
+	if perPeerRate <= 0 {
+		perPeerRate = defaultPerPeerMsgsPerSec
+	}
+	globalRate := limitsCfg.GetGlobalMsgsPerSec()
+	if globalRate <= 0 {
+		globalRate = defaultGlobalMsgsPerSec
+	}
+
+	return &inboundLimits{
+		maxMessageSize: getMaxMessageSizeBytes(cfg),
+		numWorkers:     uint32OrDefault(limitsCfg.GetNumWorkers(), defaultNumInboundWorkers),
+
+		perPeerLimiter: ratelimit.NewKeyedLimiter(perPeerRate, uint32OrDefault(limitsCfg.GetPerPeerBurst(), defaultPerPeerBurst), clock),
+		globalLimiter:  ratelimit.NewTokenBucket(globalRate, uint32OrDefault(limitsCfg.GetGlobalBurst(), defaultGlobalBurst), clock),
+
+		inboundQueue:    make(chan *inboundMessage, uint32OrDefault(limitsCfg.GetQueueSize(), defaultInboundQueueSize)),
+		consensusEvents: make(chan *debug.PocketEvent, consensusEventsQueueSize),
+		otherEvents:     make(chan *debug.PocketEvent, otherEventsQueueSize),
+	}
+}
+
+// enqueueInboundMessage applies the inbound limits to data read from the listener and queues it to be
+// handled by one of the workers. It never blocks so the listener loop always keeps up with the network.
+func (m *p2pModule) enqueueInboundMessage(data []byte, remoteHost string) {
+	sender := getPeerAddressFromHost(m.getNetwork().GetAddrBook(), remoteHost)
+	if sender != nil && m.peerScorer.IsBanned(sender) {
+		return
+	}
+
+	if uint64(len(data)) > m.inboundLimits.maxMessageSize {
+		m.emitInboundMessageDropped(dropReasonMaxMessageSize)
+		m.recordPeerEvent(sender, typesP2P.PeerEventInvalidMessage)
+		return
+	}
+
+	// The per peer limit is checked first so a noisy peer does not consume the global budget
+	if remoteHost != "" && !m.inboundLimits.perPeerLimiter.Allow(remoteHost) {
+		m.emitInboundMessageDropped(dropReasonPeerRateLimit)
+		m.recordPeerEvent(sender, typesP2P.PeerEventRateLimited)
+		return
+	}
+
+	if !m.inboundLimits.globalLimiter.Allow() {
+		m.emitInboundMessageDropped(dropReasonGlobalRateLimit)
+		return
+	}
+
+	select {
+	case m.inboundLimits.inboundQueue <- &inboundMessage{data: data, sender: sender}:
+	default:
+		m.emitInboundMessageDropped(dropReasonQueueFull)
+	}
+}
+
+// runInboundWorker handles the queued messages until the module is stopped.
+func (m *p2pModule) runInboundWorker() {
+	for {
+		var msg *inboundMessage
+		select {
+		case <-m.stopped:
+			return
+		case msg = <-m.inboundLimits.inboundQueue:
+		}
+
+		// Decompressing in the workers rather than the listener loop keeps the latter cheap
+		data, err := m.compressor.Decompress(msg.sender, msg.data)
+		if err != nil {
+			log.Println("Error decompressing network message: ", err)
+			m.recordPeerEvent(msg.sender, typesP2P.PeerEventInvalidMessage)
+			continue
+		}
+		m.handleNetworkMessage(data, msg.sender)
+	}
+}
+
+// dispatchEvent queues a decoded event to be routed to its subscribers (or the bus). Consensus events are never dropped,
+// while other events are dropped if the bus cannot keep up.
+func (m *p2pModule) dispatchEvent(event *debug.PocketEvent) {
+	if event.Topic == debug.PocketTopic_CONSENSUS_MESSAGE_TOPIC {
+		select {
+		case m.inboundLimits.consensusEvents <- event:
+		case <-m.stopped:
+		}
+		return
+	}
+	select {
+	case m.inboundLimits.otherEvents <- event:
+	default:
+		m.emitInboundMessageDropped(dropReasonBusBackpressure)
+	}
+}
+
+// runEventDispatcher routes the dispatched events until the module is stopped.
+func (m *p2pModule) runEventDispatcher() {
+	for {
+		// Pending consensus events always take precedence over other events
+		select {
+		case event := <-m.inboundLimits.consensusEvents:
+			m.routeEvent(event)
+			continue
+		default:
+		}
+
+		select {
+		case <-m.stopped:
+			return
+		case event := <-m.inboundLimits.consensusEvents:
+			m.routeEvent(event)
+		case event := <-m.inboundLimits.otherEvents:
+			m.routeEvent(event)
+		}
+	}
+}
+
+func (m *p2pModule) emitInboundMessageDropped(reason string) {
+	m.GetBus().
+		GetTelemetryModule().
+		GetEventMetricsAgent().
+		EmitEvent(
+			telemetry.P2P_EVENT_METRICS_NAMESPACE,
+			telemetry.P2P_INBOUND_MESSAGE_DROPPED_EVENT_METRIC_NAME,
+			telemetry.P2P_INBOUND_MESSAGE_DROPPED_EVENT_METRIC_REASON_LABEL, reason,
+		)
+}
+
+func getMaxMessageSizeBytes(cfg modules.P2PConfig) uint64 {
+	if maxSize := cfg.GetMaxMessageSizeBytes(); maxSize > 0 {
+		return maxSize
+	}
+	return defaultMaxMessageSizeBytes
+}
+
+func uint32OrDefault(value, defaultValue uint32) uint32 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
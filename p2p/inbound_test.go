@@ -0,0 +1,113 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/pokt-network/pocket/p2p/scoring"
+	"github.com/pokt-network/pocket/p2p/stdnetwork"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	"github.com/pokt-network/pocket/shared/debug"
+	modulesMock "github.com/pokt-network/pocket/shared/modules/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboundLimits_DropsMessages(t *testing.T) {
+	cfg := &typesP2P.P2PConfig{
+		MaxMessageSizeBytes: 10,
+		InboundLimitsConfig: &typesP2P.InboundLimitsConfig{
+			PerPeerMsgsPerSec: 1,
+			PerPeerBurst:      1,
+			QueueSize:         2,
+		},
+	}
+	m := prepareInboundTestModule(t, cfg)
+
+	// Messages above the max size are dropped
+	m.enqueueInboundMessage(make([]byte, 11), "")
+	require.Len(t, m.inboundLimits.inboundQueue, 0)
+
+	// A single peer cannot exceed its rate limit
+	m.enqueueInboundMessage([]byte("data"), "1.2.3.4")
+	m.enqueueInboundMessage([]byte("data"), "1.2.3.4")
+	require.Len(t, m.inboundLimits.inboundQueue, 1)
+
+	// Messages are dropped once the queue is full
+	m.enqueueInboundMessage([]byte("data"), "")
+	m.enqueueInboundMessage([]byte("data"), "")
+	require.Len(t, m.inboundLimits.inboundQueue, 2)
+}
+
+func TestInboundLimits_ConsensusEventsArePrioritized(t *testing.T) {
+	m := prepareInboundTestModule(t, &typesP2P.P2PConfig{})
+
+	publishedTopics := make(chan debug.PocketTopic, 4)
+	busMock := m.GetBus().(*modulesMock.MockBus)
+	busMock.EXPECT().PublishEventToBus(gomock.Any()).Do(func(e *debug.PocketEvent) {
+		publishedTopics <- e.Topic
+	}).Times(4)
+
+	for i := 0; i < 3; i++ {
+		m.dispatchEvent(&debug.PocketEvent{Topic: debug.PocketTopic_DEBUG_TOPIC})
+	}
+	m.dispatchEvent(&debug.PocketEvent{Topic: debug.PocketTopic_CONSENSUS_MESSAGE_TOPIC})
+	go m.runEventDispatcher()
+
+	expectedTopics := []debug.PocketTopic{
+		debug.PocketTopic_CONSENSUS_MESSAGE_TOPIC,
+		debug.PocketTopic_DEBUG_TOPIC,
+		debug.PocketTopic_DEBUG_TOPIC,
+		debug.PocketTopic_DEBUG_TOPIC,
+	}
+	for _, expectedTopic := range expectedTopics {
+		select {
+		case topic := <-publishedTopics:
+			require.Equal(t, expectedTopic, topic)
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for event to be published")
+		}
+	}
+}
+
+func TestInboundLimits_WorkersExitWhenStopped(t *testing.T) {
+	m := prepareInboundTestModule(t, &typesP2P.P2PConfig{})
+	m.GetBus().(*modulesMock.MockBus).EXPECT().PublishEventToBus(gomock.Any()).AnyTimes()
+
+	exited := make(chan struct{}, 3)
+	go func() { m.runInboundWorker(); exited <- struct{}{} }()
+	go func() { m.runEventDispatcher(); exited <- struct{}{} }()
+	// a consensus event does not block its handler once the dispatcher is gone
+	go func() {
+		for i := 0; i <= cap(m.inboundLimits.consensusEvents); i++ {
+			m.dispatchEvent(&debug.PocketEvent{Topic: debug.PocketTopic_CONSENSUS_MESSAGE_TOPIC})
+		}
+		exited <- struct{}{}
+	}()
+	close(m.stopped)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-exited:
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for the inbound goroutines to exit")
+		}
+	}
+}
+
+func prepareInboundTestModule(t *testing.T, cfg *typesP2P.P2PConfig) *p2pModule {
+	ctrl := gomock.NewController(t)
+	busMock := modulesMock.NewMockBus(ctrl)
+	busMock.EXPECT().GetTelemetryModule().Return(prepareTelemetryMock(t)).AnyTimes()
+
+	clockMock := clock.NewMock()
+	m := &p2pModule{
+		network:       stdnetwork.NewNetwork(nil, typesP2P.AddrBook{}, nil, nil),
+		peerScorer:    scoring.NewPeerScorer(nil, clockMock),
+		inboundLimits: newInboundLimits(cfg, clockMock),
+		stopped:       make(chan struct{}),
+	}
+	m.SetBus(busMock)
+	return m
+}
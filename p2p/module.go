@@ -6,10 +6,17 @@ package p2p
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"sync"
+	"time"
 
+	"github.com/benbjohnson/clock"
+	"github.com/pokt-network/pocket/p2p/compression"
 	"github.com/pokt-network/pocket/p2p/raintree"
+	"github.com/pokt-network/pocket/p2p/scoring"
 	"github.com/pokt-network/pocket/p2p/stdnetwork"
 	typesP2P "github.com/pokt-network/pocket/p2p/types"
 	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
@@ -30,10 +37,32 @@ type p2pModule struct {
 	bus       modules.Bus
 	p2pConfig modules.P2PConfig // TODO (olshansky): to remove this since it'll be available via the bus
 
-	listener typesP2P.Transport
-	address  cryptoPocket.Address
+	listener   typesP2P.Transport
+	address    cryptoPocket.Address
+	privateKey cryptoPocket.PrivateKey
 
-	network typesP2P.Network
+	networkMu       sync.RWMutex
+	network         typesP2P.Network // The network messages are sent with; see `setUseRainTree`
+	rainTreeNetwork typesP2P.Network
+	stdNetwork      typesP2P.Network
+
+	peerScorer    typesP2P.PeerScorer
+	inboundLimits *inboundLimits
+	compressor    *compression.Compressor
+
+	stopped  chan struct{} // Closed when the module is stopped so the listener loop can exit
+	stopOnce sync.Once
+
+	topicsMu              sync.RWMutex
+	subscriptions         map[debug.PocketTopic][]modules.P2PMessageHandler
+	propagationStrategies map[debug.PocketTopic]modules.PropagationStrategy
+
+	requestsMu        sync.RWMutex
+	requestTimeout    time.Duration
+	requestMaxRetries uint32
+	requestHandlers   map[string]modules.P2PRequestHandler // Keyed by the type url of the request data
+	pendingRequests   map[uint64]*pendingRequest
+	requestQueue      chan *inboundRequest // The requests of peers waiting for a request worker
 }
 
 // TECHDEBT(drewsky): Discuss how to best expose/access `Address` throughout the codebase.
@@ -61,13 +90,35 @@ func Create(configPath, genesisPath string, useRandomPK bool) (m modules.P2PModu
 	if err != nil {
 		return nil, err
 	}
-	m = &p2pModule{
+	compressor, err := compression.NewCompressor(cfg.GetCompressionConfig(), getMaxMessageSizeBytes(cfg))
+	if err != nil {
+		return nil, err
+	}
+	mod := &p2pModule{
 		p2pConfig: cfg,
 
-		listener: l,
-		address:  privateKey.Address(),
+		listener:      l,
+		address:       privateKey.Address(),
+		privateKey:    privateKey,
+		peerScorer:    scoring.NewPeerScorer(cfg.GetPeerScoringConfig(), clock.New()),
+		inboundLimits: newInboundLimits(cfg, clock.New()),
+		compressor:    compressor,
+
+		stopped: make(chan struct{}),
+
+		subscriptions:         make(map[debug.PocketTopic][]modules.P2PMessageHandler),
+		propagationStrategies: make(map[debug.PocketTopic]modules.PropagationStrategy),
+
+		requestTimeout:    getRequestTimeout(cfg.GetRequestResponseConfig()),
+		requestMaxRetries: getRequestMaxRetries(cfg.GetRequestResponseConfig()),
+		requestHandlers:   make(map[string]modules.P2PRequestHandler),
+		pendingRequests:   make(map[uint64]*pendingRequest),
+		requestQueue:      make(chan *inboundRequest, requestQueueSize),
 	}
-	return m, nil
+	if err := mod.initRequestResponse(); err != nil {
+		return nil, err
+	}
+	return mod, nil
 }
 
 func (m *p2pModule) InitConfig(pathToConfigJSON string) (config modules.IConfig, err error) {
@@ -110,6 +161,9 @@ func (m *p2pModule) GetModuleName() string {
 
 func (m *p2pModule) Start() error {
 	log.Println("Starting network module")
+	if advertisedAddresses := m.p2pConfig.GetAdvertisedAddresses(); len(advertisedAddresses) > 0 {
+		log.Printf("Advertising the network module at %v\n", advertisedAddresses)
+	}
 
 	m.GetBus().
 		GetTelemetryModule().
@@ -118,28 +172,60 @@ func (m *p2pModule) Start() error {
 			telemetry.P2P_NODE_STARTED_TIMESERIES_METRIC_NAME,
 			telemetry.P2P_NODE_STARTED_TIMESERIES_METRIC_DESCRIPTION,
 		)
+	if m.compressor.IsEnabled() {
+		timeSeriesAgent := m.GetBus().GetTelemetryModule().GetTimeSeriesAgent()
+		timeSeriesAgent.GaugeRegister(
+			telemetry.P2P_UNCOMPRESSED_BYTES_SENT_TIMESERIES_METRIC_NAME,
+			telemetry.P2P_UNCOMPRESSED_BYTES_SENT_TIMESERIES_METRIC_DESCRIPTION,
+		)
+		timeSeriesAgent.GaugeRegister(
+			telemetry.P2P_COMPRESSED_BYTES_SENT_TIMESERIES_METRIC_NAME,
+			telemetry.P2P_COMPRESSED_BYTES_SENT_TIMESERIES_METRIC_DESCRIPTION,
+		)
+	}
 
 	addrBook, err := ValidatorMapToAddrBook(m.p2pConfig, m.bus.GetConsensusModule().ValidatorMap())
 	if err != nil {
 		return err
 	}
+	m.wrapDialersWithFallbacks(addrBook)
+	m.wrapDialersWithCompression(addrBook)
+
+	// Both networks share the peers of the address book, so their dialers only need to be set up once
+	m.stdNetwork = stdnetwork.NewNetwork(m.address, addrBook, m.privateKey, getStdNetworkConfig(m.p2pConfig))
+	m.rainTreeNetwork = raintree.NewRainTreeNetwork(m.address, addrBook, m.privateKey)
+	for _, network := range []typesP2P.Network{m.stdNetwork, m.rainTreeNetwork} {
+		network.SetBus(m.GetBus())
+		network.SetPeerScorer(m.peerScorer)
+	}
+	m.setUseRainTree(m.p2pConfig.GetUseRainTree())
 
-	if m.p2pConfig.GetUseRainTree() {
-		m.network = raintree.NewRainTreeNetwork(m.address, addrBook)
-	} else {
-		m.network = stdnetwork.NewNetwork(addrBook)
+	for i := uint32(0); i < m.inboundLimits.numWorkers; i++ {
+		go m.runInboundWorker()
+	}
+	go m.runEventDispatcher()
+	for i := 0; i < numRequestWorkers; i++ {
+		go m.runRequestWorker()
 	}
-	m.network.SetBus(m.GetBus())
 	go func() {
 		for {
-			data, err := m.listener.Read()
+			data, remoteHost, err := m.readFromListener()
 			if err != nil {
+				select {
+				case <-m.stopped:
+					return
+				default:
+				}
 				log.Println("Error reading data from connection: ", err)
 				continue
 			}
-			go m.handleNetworkMessage(data)
+			m.enqueueInboundMessage(data, remoteHost)
 		}
 	}()
+	// The empty connection does not reach any peer, so there are no advertised addresses to fall back to
+	if getConnectionType(m.p2pConfig) != typesP2P.ConnectionType_EmptyConnection {
+		go m.requestAdvertisedAddresses(addrBook)
+	}
 
 	m.GetBus().
 		GetTelemetryModule().
@@ -150,11 +236,14 @@ func (m *p2pModule) Start() error {
 }
 
 func (m *p2pModule) Stop() error {
-	log.Println("Stopping network module")
-	if err := m.listener.Close(); err != nil {
-		return err
-	}
-	return nil
+	var err error
+	// Stopping the module more than once is a no-op
+	m.stopOnce.Do(func() {
+		log.Println("Stopping network module")
+		close(m.stopped)
+		err = m.listener.Close()
+	})
+	return err
 }
 
 func (m *p2pModule) Broadcast(msg *anypb.Any, topic debug.PocketTopic) error {
@@ -168,7 +257,7 @@ func (m *p2pModule) Broadcast(msg *anypb.Any, topic debug.PocketTopic) error {
 	}
 	log.Println("broadcasting message to network")
 
-	return m.network.NetworkBroadcast(data)
+	return m.getNetwork().NetworkBroadcast(data)
 }
 
 func (m *p2pModule) Send(addr cryptoPocket.Address, msg *anypb.Any, topic debug.PocketTopic) error {
@@ -181,13 +270,29 @@ func (m *p2pModule) Send(addr cryptoPocket.Address, msg *anypb.Any, topic debug.
 		return err
 	}
 
-	return m.network.NetworkSend(data, addr)
+	return m.getNetwork().NetworkSend(data, addr)
+}
+
+// readFromListener reads the next message from the listener along with the host that sent it. The host
+// is empty if the listener is not able to tell where the data came from.
+func (m *p2pModule) readFromListener() ([]byte, string, error) {
+	listener, ok := m.listener.(typesP2P.PeerAwareTransport)
+	if !ok {
+		data, err := m.listener.Read()
+		return data, "", err
+	}
+	return listener.ReadFrom()
 }
 
-func (m *p2pModule) handleNetworkMessage(networkMsgData []byte) {
-	appMsgData, err := m.network.HandleNetworkData(networkMsgData)
+func (m *p2pModule) handleNetworkMessage(networkMsgData []byte, sender cryptoPocket.Address) {
+	appMsgData, origin, err := m.handleNetworkData(networkMsgData)
 	if err != nil {
 		log.Println("Error handling raw data: ", err)
+		if errors.Is(err, typesP2P.ErrInvalidSignature) {
+			m.recordPeerEvent(sender, typesP2P.PeerEventInvalidSignature)
+		} else {
+			m.recordPeerEvent(sender, typesP2P.PeerEventInvalidMessage)
+		}
 		return
 	}
 
@@ -201,6 +306,23 @@ func (m *p2pModule) handleNetworkMessage(networkMsgData []byte) {
 	networkMessage := debug.PocketEvent{}
 	if err := proto.Unmarshal(appMsgData, &networkMessage); err != nil {
 		log.Println("Error decoding network message: ", err)
+		m.recordPeerEvent(sender, typesP2P.PeerEventInvalidMessage)
+		return
+	}
+	m.recordPeerEvent(sender, typesP2P.PeerEventUsefulMessage)
+
+	switch networkMessage.Topic {
+	// Responses are handed over to their pending request right away rather than through the event dispatcher,
+	// which may be busy running a handler waiting for them (i.e. a handler calling `Request`)
+	case debug.PocketTopic_P2P_RESPONSE_TOPIC:
+		if err := m.handleResponse(networkMessage.Data, origin); err != nil {
+			log.Println("Error handling response: ", err)
+		}
+		return
+	// Requests are handled by the request workers so slow handlers (e.g. reading from the database) do not hold up
+	// the event dispatcher, and consensus events with it
+	case debug.PocketTopic_P2P_REQUEST_TOPIC:
+		m.enqueueRequest(networkMessage.Data, origin)
 		return
 	}
 
@@ -209,5 +331,61 @@ func (m *p2pModule) handleNetworkMessage(networkMsgData []byte) {
 		Data:  networkMessage.Data,
 	}
 
-	m.GetBus().PublishEventToBus(&event)
+	m.dispatchEvent(&event)
+}
+
+// handleNetworkData hands inbound data to the network it was sent with, regardless of the one messages are sent
+// with, so messages are not lost while nodes switch networks at runtime. Both networks only accept messages signed
+// by their origin.
+func (m *p2pModule) handleNetworkData(data []byte) ([]byte, cryptoPocket.Address, error) {
+	if m.stdNetwork == nil || m.rainTreeNetwork == nil {
+		return m.getNetwork().HandleNetworkData(data)
+	}
+	networkType, err := typesP2P.GetNetworkType(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch networkType {
+	case typesP2P.NetworkType_StdNetwork:
+		return m.stdNetwork.HandleNetworkData(data)
+	case typesP2P.NetworkType_RainTreeNetwork:
+		return m.rainTreeNetwork.HandleNetworkData(data)
+	default:
+		return nil, nil, fmt.Errorf("unknown network: %s", networkType)
+	}
+}
+
+func (m *p2pModule) getNetwork() typesP2P.Network {
+	m.networkMu.RLock()
+	defer m.networkMu.RUnlock()
+	return m.network
+}
+
+// setUseRainTree switches the network messages are sent with, which can be used to compare RainTree
+// and stdnetwork on a running network.
+func (m *p2pModule) setUseRainTree(useRainTree bool) {
+	m.networkMu.Lock()
+	defer m.networkMu.Unlock()
+	if useRainTree {
+		m.network = m.rainTreeNetwork
+	} else {
+		m.network = m.stdNetwork
+	}
+}
+
+// recordPeerEvent is a noop if the sender of the message is unknown
+func (m *p2pModule) recordPeerEvent(sender cryptoPocket.Address, event typesP2P.PeerEvent) {
+	if sender != nil {
+		m.peerScorer.RecordEvent(sender, event)
+	}
+}
+
+// TECHDEBT: `modules.P2PConfig` cannot expose the `StdNetworkConfig` since it lives in the p2p module.
+func getStdNetworkConfig(cfg modules.P2PConfig) *typesP2P.StdNetworkConfig {
+	if c, ok := cfg.(interface {
+		GetStdNetworkConfig() *typesP2P.StdNetworkConfig
+	}); ok {
+		return c.GetStdNetworkConfig()
+	}
+	return nil
 }
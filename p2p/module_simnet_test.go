@@ -0,0 +1,115 @@
+package p2p
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pokt-network/pocket/p2p/simnet"
+	"github.com/pokt-network/pocket/shared/debug"
+	"github.com/pokt-network/pocket/shared/modules"
+	modulesMock "github.com/pokt-network/pocket/shared/modules/mocks"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Unlike the RainTree unit tests, which mock the transport of every node, these tests run the nodes
+// end-to-end over a simulated network to make sure a broadcast reaches every node despite latency.
+func TestRainTreeSimulatedNetworkNineNodes(t *testing.T) {
+	testRainTreeSimulatedNetwork(t, 9, simnet.HubConfig{
+		Latency: 5 * time.Millisecond,
+		Jitter:  10 * time.Millisecond,
+	})
+}
+
+func TestRainTreeSimulatedNetworkTwentySevenNodes(t *testing.T) {
+	testRainTreeSimulatedNetwork(t, 27, simnet.HubConfig{
+		Latency: 5 * time.Millisecond,
+		Jitter:  10 * time.Millisecond,
+	})
+}
+
+func testRainTreeSimulatedNetwork(t *testing.T, numValidators int, hubCfg simnet.HubConfig) {
+	var messageHandledWaitGroup sync.WaitGroup
+	messageHandledWaitGroup.Add(numValidators - 1) // -1 because the originator node implicitly handles the message
+
+	telemetryMock := prepareTelemetryMock(t)
+	p2pModules := startSimulatedP2PModules(t, numValidators, simnet.NewHub(hubCfg, clock.New()), func(consensusMock *modulesMock.MockConsensusModule) modules.Bus {
+		return prepareBusMock(t, &messageHandledWaitGroup, consensusMock, telemetryMock)
+	})
+
+	require.NoError(t, p2pModules[validatorId(t, 1)].Broadcast(&anypb.Any{}, debug.PocketTopic_DEBUG_TOPIC))
+	waitForMessageHandled(t, &messageHandledWaitGroup)
+}
+
+// Nodes handle the messages of both networks, so a node switching to stdnetwork at runtime still reaches the
+// nodes sending with RainTree. Every node handles the message once even though stdnetwork relays it.
+func TestStdNetworkSimulatedNetworkSwitchedAtRuntime(t *testing.T) {
+	numValidators := 9
+	var messageHandledWaitGroup sync.WaitGroup
+	messageHandledWaitGroup.Add(numValidators - 1) // -1 because the originator node implicitly handles the message
+
+	telemetryMock := prepareTelemetryMock(t)
+	hub := simnet.NewHub(simnet.HubConfig{Latency: 5 * time.Millisecond}, clock.New())
+	p2pModules := startSimulatedP2PModules(t, numValidators, hub, func(consensusMock *modulesMock.MockConsensusModule) modules.Bus {
+		return prepareBusMock(t, &messageHandledWaitGroup, consensusMock, telemetryMock)
+	})
+
+	originator := p2pModules[validatorId(t, 1)]
+	require.NoError(t, originator.HandleDebugMessage(&debug.DebugMessage{
+		Action: debug.DebugMessageAction_DEBUG_P2P_USE_STD_NETWORK,
+	}))
+	require.Equal(t, originator.stdNetwork, originator.getNetwork())
+
+	require.NoError(t, originator.Broadcast(&anypb.Any{}, debug.PocketTopic_DEBUG_TOPIC))
+	waitForMessageHandled(t, &messageHandledWaitGroup)
+}
+
+// The modules are stopped again when the test completes, which must not panic.
+func TestSimulatedNetworkStopTwice(t *testing.T) {
+	telemetryMock := prepareTelemetryMock(t)
+	hub := simnet.NewHub(simnet.HubConfig{}, clock.New())
+	p2pModules := startSimulatedP2PModules(t, 1, hub, func(consensusMock *modulesMock.MockConsensusModule) modules.Bus {
+		return prepareBusMock(t, &sync.WaitGroup{}, consensusMock, telemetryMock)
+	})
+
+	require.NoError(t, p2pModules[validatorId(t, 1)].Stop())
+}
+
+func waitForMessageHandled(t *testing.T, messageHandledWaitGroup *sync.WaitGroup) {
+	done := make(chan struct{})
+	go func() {
+		messageHandledWaitGroup.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Timeout waiting for message to be handled")
+	}
+}
+
+// startSimulatedP2PModules starts `numValidators` p2p modules connected to each other through `hub`.
+// The modules are stopped when the test completes.
+func startSimulatedP2PModules(t *testing.T, numValidators int, hub *simnet.Hub, prepareBus func(*modulesMock.MockConsensusModule) modules.Bus) map[string]*p2pModule {
+	configs, genesisState := createConfigs(t, numValidators)
+	consensusMock := prepareConsensusMock(t, genesisState)
+
+	p2pModules := prepareP2PModules(t, configs)
+	for validatorId, p2pMod := range p2pModules {
+		listener, err := hub.NewListener(validatorId)
+		require.NoError(t, err)
+		p2pMod.listener = listener
+		p2pMod.SetBus(prepareBus(consensusMock))
+		require.NoError(t, p2pMod.Start())
+		for _, peer := range p2pMod.network.GetAddrBook() {
+			peer.Dialer = hub.NewDialer(validatorId, peer.ServiceUrl)
+		}
+		t.Cleanup(func(p2pMod modules.P2PModule) func() {
+			return func() { require.NoError(t, p2pMod.Stop()) }
+		}(p2pMod))
+	}
+	return p2pModules
+}
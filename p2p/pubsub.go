@@ -0,0 +1,105 @@
+package p2p
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	"github.com/pokt-network/pocket/shared/debug"
+	"github.com/pokt-network/pocket/shared/modules"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// The number of peers a message published on a topic using `PropagationGossip` is sent to.
+// IMPROVE: Gossiped messages are not relayed any further; add a TTL so they can reach nodes beyond our peers.
+const gossipFanout = 8
+
+func (m *p2pModule) Publish(msg *anypb.Any, topic debug.PocketTopic) error {
+	switch strategy := m.getPropagationStrategy(topic); strategy {
+	case modules.PropagationBroadcast:
+		return m.Broadcast(msg, topic)
+	case modules.PropagationGossip:
+		return m.gossip(msg, topic)
+	case modules.PropagationDirect:
+		return fmt.Errorf("messages on topic %s can only be sent directly to a peer", topic)
+	default:
+		return fmt.Errorf("unsupported propagation strategy %d for topic %s", strategy, topic)
+	}
+}
+
+func (m *p2pModule) Subscribe(topic debug.PocketTopic, handler modules.P2PMessageHandler) error {
+	if topic == debug.PocketTopic_UNKNOWN_POCKET_TOPIC {
+		return fmt.Errorf("cannot subscribe to topic %s", topic)
+	}
+	if handler == nil {
+		return fmt.Errorf("cannot subscribe to topic %s with a nil handler", topic)
+	}
+
+	m.topicsMu.Lock()
+	defer m.topicsMu.Unlock()
+	m.subscriptions[topic] = append(m.subscriptions[topic], handler)
+	return nil
+}
+
+func (m *p2pModule) SetPropagationStrategy(topic debug.PocketTopic, strategy modules.PropagationStrategy) {
+	m.topicsMu.Lock()
+	defer m.topicsMu.Unlock()
+	m.propagationStrategies[topic] = strategy
+}
+
+func (m *p2pModule) getPropagationStrategy(topic debug.PocketTopic) modules.PropagationStrategy {
+	m.topicsMu.RLock()
+	defer m.topicsMu.RUnlock()
+	if strategy, ok := m.propagationStrategies[topic]; ok {
+		return strategy
+	}
+	return modules.PropagationBroadcast
+}
+
+// routeEvent hands the event over to the subscribers of its topic, or publishes it to the bus if there are none.
+func (m *p2pModule) routeEvent(event *debug.PocketEvent) {
+	m.topicsMu.RLock()
+	handlers := m.subscriptions[event.Topic]
+	m.topicsMu.RUnlock()
+
+	if len(handlers) == 0 {
+		m.GetBus().PublishEventToBus(event)
+		return
+	}
+	for _, handler := range handlers {
+		if err := handler(event.Data); err != nil {
+			log.Printf("Error handling message on topic %s: %v\n", event.Topic, err)
+		}
+	}
+}
+
+// gossip sends the message to up to `gossipFanout` peers picked at random from the address book.
+func (m *p2pModule) gossip(msg *anypb.Any, topic debug.PocketTopic) error {
+	data, err := proto.Marshal(&debug.PocketEvent{
+		Topic: topic,
+		Data:  msg,
+	})
+	if err != nil {
+		return err
+	}
+
+	peers := make(typesP2P.AddrBook, 0)
+	for _, peer := range m.getNetwork().GetAddrBook() {
+		if !peer.Address.Equals(m.address) {
+			peers = append(peers, peer)
+		}
+	}
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+	if len(peers) > gossipFanout {
+		peers = peers[:gossipFanout]
+	}
+
+	for _, peer := range peers {
+		if err := m.getNetwork().NetworkSend(data, peer.Address); err != nil {
+			log.Println("Error gossiping to peer: ", err)
+		}
+	}
+	return nil
+}
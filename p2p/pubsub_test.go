@@ -0,0 +1,88 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	mocksP2P "github.com/pokt-network/pocket/p2p/types/mocks"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"github.com/pokt-network/pocket/shared/debug"
+	"github.com/pokt-network/pocket/shared/modules"
+	modulesMock "github.com/pokt-network/pocket/shared/modules/mocks"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestPubSub_RoutesToSubscribers(t *testing.T) {
+	m := preparePubSubTestModule(t)
+	busMock := m.GetBus().(*modulesMock.MockBus)
+
+	var consensusMsgs, debugMsgs int
+	require.NoError(t, m.Subscribe(debug.PocketTopic_CONSENSUS_MESSAGE_TOPIC, func(*anypb.Any) error {
+		consensusMsgs++
+		return nil
+	}))
+	require.NoError(t, m.Subscribe(debug.PocketTopic_CONSENSUS_MESSAGE_TOPIC, func(*anypb.Any) error {
+		consensusMsgs++
+		return nil
+	}))
+	require.Error(t, m.Subscribe(debug.PocketTopic_UNKNOWN_POCKET_TOPIC, func(*anypb.Any) error { return nil }))
+	require.Error(t, m.Subscribe(debug.PocketTopic_DEBUG_TOPIC, nil))
+
+	// Subscribed topics bypass the bus
+	m.routeEvent(&debug.PocketEvent{Topic: debug.PocketTopic_CONSENSUS_MESSAGE_TOPIC})
+	require.Equal(t, 2, consensusMsgs)
+
+	// Topics without subscribers fall back to the bus
+	busMock.EXPECT().PublishEventToBus(gomock.Any()).Do(func(e *debug.PocketEvent) {
+		debugMsgs++
+	}).Times(1)
+	m.routeEvent(&debug.PocketEvent{Topic: debug.PocketTopic_DEBUG_TOPIC})
+	require.Equal(t, 1, debugMsgs)
+}
+
+func TestPubSub_PropagationStrategies(t *testing.T) {
+	m := preparePubSubTestModule(t)
+	networkMock := m.network.(*mocksP2P.MockNetwork)
+
+	addrBook := typesP2P.AddrBook{{Address: m.address}}
+	for i := 0; i < gossipFanout*2; i++ {
+		addr, err := cryptoPocket.GenerateAddress()
+		require.NoError(t, err)
+		addrBook = append(addrBook, &typesP2P.NetworkPeer{Address: addr})
+	}
+	networkMock.EXPECT().GetAddrBook().Return(addrBook).AnyTimes()
+
+	// Topics are broadcast by default
+	networkMock.EXPECT().NetworkBroadcast(gomock.Any()).Return(nil).Times(1)
+	require.NoError(t, m.Publish(&anypb.Any{}, debug.PocketTopic_DEBUG_TOPIC))
+
+	m.SetPropagationStrategy(debug.PocketTopic_DEBUG_TOPIC, modules.PropagationGossip)
+	networkMock.EXPECT().NetworkSend(gomock.Any(), gomock.Any()).DoAndReturn(func(_ []byte, addr cryptoPocket.Address) error {
+		require.False(t, addr.Equals(m.address), "gossiped to self")
+		return nil
+	}).Times(gossipFanout)
+	require.NoError(t, m.Publish(&anypb.Any{}, debug.PocketTopic_DEBUG_TOPIC))
+
+	m.SetPropagationStrategy(debug.PocketTopic_DEBUG_TOPIC, modules.PropagationDirect)
+	require.Error(t, m.Publish(&anypb.Any{}, debug.PocketTopic_DEBUG_TOPIC))
+}
+
+func preparePubSubTestModule(t *testing.T) *p2pModule {
+	ctrl := gomock.NewController(t)
+	busMock := modulesMock.NewMockBus(ctrl)
+
+	addr, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+
+	m := &p2pModule{
+		address: addr,
+		network: mocksP2P.NewMockNetwork(ctrl),
+
+		subscriptions:         make(map[debug.PocketTopic][]modules.P2PMessageHandler),
+		propagationStrategies: make(map[debug.PocketTopic]modules.PropagationStrategy),
+	}
+	m.SetBus(busMock)
+	return m
+}
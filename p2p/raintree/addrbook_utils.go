@@ -21,8 +21,19 @@ const (
 // validators at the current height.
 func (n *rainTreeNetwork) getAddrBookLength(level uint32, _height uint64) int {
 	peersManagerStateView := n.peersManager.getNetworkView()
-	shrinkageCoefficient := math.Pow(shrinkagePercentage, float64(peersManagerStateView.maxNumLevels-level))
-	return int(float64(len(peersManagerStateView.addrList)) * (shrinkageCoefficient))
+	return getAddrBookLengthAtLevel(len(peersManagerStateView.addrList), peersManagerStateView.maxNumLevels, level)
+}
+
+// getAddrBookLengthAtLevel returns the length of the portion of the addr book, starting at self, where
+// the targets at `level` are picked from.
+func getAddrBookLengthAtLevel(addrBookLen int, maxNumLevels, level uint32) int {
+	shrinkageCoefficient := math.Pow(shrinkagePercentage, float64(maxNumLevels-level))
+	return int(float64(addrBookLen) * (shrinkageCoefficient))
+}
+
+// getTargetIndex returns the index of a target relative to self (i.e. 0 is self)
+func getTargetIndex(targetPercentage float64, addrBookLen int) int {
+	return int(targetPercentage * float64(addrBookLen))
 }
 
 // getTargetsAtLevel returns the targets for a given level
@@ -38,10 +49,11 @@ func (n *rainTreeNetwork) getTargetsAtLevel(level uint32) []target {
 }
 
 func (n *rainTreeNetwork) getTarget(targetPercentage float64, addrBookLen int, level uint32) target {
-	i := int(targetPercentage * float64(addrBookLen))
-
 	peersManagerStateView := n.peersManager.getNetworkView()
 
+	i := getTargetIndex(targetPercentage, addrBookLen)
+	i = n.deprioritizeBannedTarget(peersManagerStateView, i, addrBookLen)
+
 	target := target{
 		serviceUrl:             peersManagerStateView.addrBookMap[peersManagerStateView.addrList[i]].ServiceUrl,
 		percentage:             targetPercentage,
@@ -66,6 +78,22 @@ func (n *rainTreeNetwork) getTarget(targetPercentage float64, addrBookLen int, l
 	return target
 }
 
+// deprioritizeBannedTarget returns the index of the first peer, starting at `i`, that is not banned
+// so the subtree of a banned target is still (partially) covered by its closest neighbour. The original
+// index is returned if `i` references self or if all the candidates at this level are banned.
+func (n *rainTreeNetwork) deprioritizeBannedTarget(view networkView, i, addrBookLen int) int {
+	if n.peerScorer == nil || i == 0 {
+		return i
+	}
+	for j := i; j < addrBookLen && j < len(view.addrList); j++ {
+		peer, ok := view.addrBookMap[view.addrList[j]]
+		if !ok || !n.peerScorer.IsBanned(peer.Address) {
+			return j
+		}
+	}
+	return i
+}
+
 // Only used for debug logging to understand what RainTree is doing under the hood
 func (n *rainTreeNetwork) debugMsgTargetString(target1, target2 target) string {
 	s := strings.Builder{}
@@ -0,0 +1,80 @@
+package raintree
+
+import (
+	"fmt"
+
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"google.golang.org/protobuf/proto"
+)
+
+// newSignedMessage creates a RainTree message originating from this node with a signature over its
+// payload so every hop can authenticate the origin and detect tampering before propagating it.
+func (n *rainTreeNetwork) newSignedMessage(data []byte, level uint32) (*typesP2P.RainTreeMessage, error) {
+	if n.privateKey == nil {
+		return nil, fmt.Errorf("cannot sign RainTree message without a private key")
+	}
+
+	msg := &typesP2P.RainTreeMessage{
+		Level:  level,
+		Data:   data,
+		Nonce:  getNonce(),
+		Origin: n.selfAddr,
+	}
+	signableBz, err := getSignableBytes(msg)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Signature, err = n.privateKey.Sign(signableBz); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// verifyMessage checks that the message was signed by its origin, which must be in the address book.
+func (n *rainTreeNetwork) verifyMessage(msg *typesP2P.RainTreeMessage) error {
+	origin := cryptoPocket.Address(msg.Origin)
+	peer, ok := n.peersManager.getNetworkView().addrBookMap[origin.String()]
+	if !ok || peer.PublicKey == nil {
+		return fmt.Errorf("%w: unknown origin %s", typesP2P.ErrInvalidSignature, origin)
+	}
+
+	signableBz, err := getSignableBytes(msg)
+	if err != nil {
+		return err
+	}
+	if !peer.PublicKey.Verify(signableBz, msg.Signature) {
+		return fmt.Errorf("%w: origin %s", typesP2P.ErrInvalidSignature, origin)
+	}
+	return nil
+}
+
+// getSignableBytes returns the bytes signed by the origin of a message. The level is excluded because
+// it is decremented by every hop during propagation.
+func getSignableBytes(msg *typesP2P.RainTreeMessage) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(&typesP2P.RainTreeMessage{
+		Data:   msg.Data,
+		Nonce:  msg.Nonce,
+		Origin: msg.Origin,
+	})
+}
+
+// copyMessageAtLevel returns a copy of the message, preserving its origin and signature, at a different level.
+func copyMessageAtLevel(msg *typesP2P.RainTreeMessage, level uint32) *typesP2P.RainTreeMessage {
+	return &typesP2P.RainTreeMessage{
+		Level:     level,
+		Data:      msg.Data,
+		Nonce:     msg.Nonce,
+		Origin:    msg.Origin,
+		Signature: msg.Signature,
+	}
+}
+
+// marshalMessage serializes the message in the envelope written to peers.
+func marshalMessage(msg *typesP2P.RainTreeMessage) ([]byte, error) {
+	msgBz, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return typesP2P.WrapNetworkMessage(typesP2P.NetworkType_RainTreeNetwork, msgBz)
+}
@@ -0,0 +1,54 @@
+package raintree
+
+import (
+	"errors"
+	"testing"
+
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRainTreeMessage_SignAndVerify(t *testing.T) {
+	originPrivKey, err := cryptoPocket.GeneratePrivateKey()
+	require.NoError(t, err)
+	relayPrivKey, err := cryptoPocket.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	addrBook := typesP2P.AddrBook{
+		{Address: originPrivKey.Address(), PublicKey: originPrivKey.PublicKey()},
+		{Address: relayPrivKey.Address(), PublicKey: relayPrivKey.PublicKey()},
+	}
+	origin := NewRainTreeNetwork(originPrivKey.Address(), addrBook, originPrivKey).(*rainTreeNetwork)
+	relay := NewRainTreeNetwork(relayPrivKey.Address(), addrBook, relayPrivKey).(*rainTreeNetwork)
+
+	msg, err := origin.newSignedMessage([]byte("data"), 2)
+	require.NoError(t, err)
+	require.NoError(t, relay.verifyMessage(msg))
+
+	// The level changes at every hop so it is not covered by the signature
+	require.NoError(t, relay.verifyMessage(copyMessageAtLevel(msg, 1)))
+
+	tamperedData := copyMessageAtLevel(msg, 2)
+	tamperedData.Data = []byte("tampered")
+	require.True(t, errors.Is(relay.verifyMessage(tamperedData), typesP2P.ErrInvalidSignature))
+
+	forgedOrigin := copyMessageAtLevel(msg, 2)
+	forgedOrigin.Origin = relayPrivKey.Address()
+	require.True(t, errors.Is(relay.verifyMessage(forgedOrigin), typesP2P.ErrInvalidSignature))
+
+	unknownAddr, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+	unknownOrigin := copyMessageAtLevel(msg, 2)
+	unknownOrigin.Origin = unknownAddr
+	require.True(t, errors.Is(relay.verifyMessage(unknownOrigin), typesP2P.ErrInvalidSignature))
+}
+
+func TestRainTreeMessage_SigningRequiresPrivateKey(t *testing.T) {
+	addr, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+
+	network := NewRainTreeNetwork(addr, typesP2P.AddrBook{{Address: addr}}, nil).(*rainTreeNetwork)
+	_, err = network.newSignedMessage([]byte("data"), 0)
+	require.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"sync"
 	"time"
 
 	typesP2P "github.com/pokt-network/pocket/p2p/types"
@@ -20,15 +21,20 @@ var _ modules.IntegratableModule = &rainTreeNetwork{}
 type rainTreeNetwork struct {
 	bus modules.Bus
 
-	selfAddr cryptoPocket.Address
+	selfAddr   cryptoPocket.Address
+	privateKey cryptoPocket.PrivateKey // Used to sign the messages originating from this node
 
 	peersManager *peersManager
 
+	// Optional; used to avoid sending to or selecting misbehaving peers as targets
+	peerScorer typesP2P.PeerScorer
+
 	// TECHDEBT(drewsky): What should we use for de-duping messages within P2P?
-	mempool map[uint64]struct{} // TODO (drewsky) replace map implementation (can grow unbounded)
+	mempool   map[uint64]struct{} // TODO (drewsky) replace map implementation (can grow unbounded)
+	mempoolMu sync.Mutex          // Inbound messages are handled concurrently
 }
 
-func NewRainTreeNetwork(addr cryptoPocket.Address, addrBook typesP2P.AddrBook) typesP2P.Network {
+func NewRainTreeNetwork(addr cryptoPocket.Address, addrBook typesP2P.AddrBook, privateKey cryptoPocket.PrivateKey) typesP2P.Network {
 	pm, err := newPeersManager(addr, addrBook)
 	if err != nil {
 		log.Println("[ERROR] Error initializing rainTreeNetwork peersManager: ", err)
@@ -36,6 +42,7 @@ func NewRainTreeNetwork(addr cryptoPocket.Address, addrBook typesP2P.AddrBook) t
 
 	n := &rainTreeNetwork{
 		selfAddr:     addr,
+		privateKey:   privateKey,
 		peersManager: pm,
 		mempool:      make(map[uint64]struct{}),
 	}
@@ -44,25 +51,24 @@ func NewRainTreeNetwork(addr cryptoPocket.Address, addrBook typesP2P.AddrBook) t
 }
 
 func (n *rainTreeNetwork) NetworkBroadcast(data []byte) error {
-	return n.networkBroadcastAtLevel(data, n.peersManager.getNetworkView().maxNumLevels, getNonce())
+	msg, err := n.newSignedMessage(data, n.peersManager.getNetworkView().maxNumLevels)
+	if err != nil {
+		return err
+	}
+	return n.networkBroadcastAtLevel(msg)
 }
 
-func (n *rainTreeNetwork) networkBroadcastAtLevel(data []byte, level uint32, nonce uint64) error {
+func (n *rainTreeNetwork) networkBroadcastAtLevel(msg *typesP2P.RainTreeMessage) error {
 	// This is handled either by the cleanup layer or redundancy layer
-	if level == 0 {
+	if msg.Level == 0 {
 		return nil
 	}
-	msg := &typesP2P.RainTreeMessage{
-		Level: level,
-		Data:  data,
-		Nonce: nonce,
-	}
-	msgBz, err := proto.Marshal(msg)
+	msgBz, err := marshalMessage(msg)
 	if err != nil {
 		return err
 	}
 
-	for _, target := range n.getTargetsAtLevel(level) {
+	for _, target := range n.getTargetsAtLevel(msg.Level) {
 		if shouldSendToTarget(target) {
 			if err = n.networkSendInternal(msgBz, target.address); err != nil {
 				log.Println("Error sending to peer during broadcast: ", err)
@@ -79,7 +85,7 @@ func (n *rainTreeNetwork) networkBroadcastAtLevel(data []byte, level uint32, non
 
 func (n *rainTreeNetwork) demote(rainTreeMsg *typesP2P.RainTreeMessage) error {
 	if rainTreeMsg.Level > 0 {
-		if err := n.networkBroadcastAtLevel(rainTreeMsg.Data, rainTreeMsg.Level-1, rainTreeMsg.Nonce); err != nil {
+		if err := n.networkBroadcastAtLevel(copyMessageAtLevel(rainTreeMsg, rainTreeMsg.Level-1)); err != nil {
 			return err
 		}
 	}
@@ -87,13 +93,12 @@ func (n *rainTreeNetwork) demote(rainTreeMsg *typesP2P.RainTreeMessage) error {
 }
 
 func (n *rainTreeNetwork) NetworkSend(data []byte, address cryptoPocket.Address) error {
-	msg := &typesP2P.RainTreeMessage{
-		Level: 0, // Direct send that does not need to be propagated
-		Data:  data,
-		Nonce: getNonce(),
+	msg, err := n.newSignedMessage(data, 0) // Direct send that does not need to be propagated
+	if err != nil {
+		return err
 	}
 
-	bz, err := proto.Marshal(msg)
+	bz, err := marshalMessage(msg)
 	if err != nil {
 		return err
 	}
@@ -112,15 +117,20 @@ func (n *rainTreeNetwork) networkSendInternal(data []byte, address cryptoPocket.
 		return fmt.Errorf("address %s not found in addrBookMap", address.String())
 	}
 
+	if n.isPeerBanned(address) {
+		return fmt.Errorf("peer %s is temporarily banned", address.String())
+	}
+
 	if err := peer.Dialer.Write(data); err != nil {
 		log.Println("Error writing to peer during send: ", err)
+		n.recordPeerEvent(address, typesP2P.PeerEventDialFailure)
 		return err
 	}
 
 	return nil
 }
 
-func (n *rainTreeNetwork) HandleNetworkData(data []byte) ([]byte, error) {
+func (n *rainTreeNetwork) HandleNetworkData(data []byte) ([]byte, cryptoPocket.Address, error) {
 	blockHeightInt := n.GetBus().GetConsensusModule().CurrentHeight()
 	blockHeight := fmt.Sprintf("%d", blockHeightInt)
 
@@ -133,28 +143,44 @@ func (n *rainTreeNetwork) HandleNetworkData(data []byte) ([]byte, error) {
 			telemetry.P2P_RAINTREE_MESSAGE_EVENT_METRIC_HEIGHT_LABEL, blockHeight,
 		)
 
+	msgBz, err := typesP2P.UnwrapNetworkMessage(typesP2P.NetworkType_RainTreeNetwork, data)
+	if err != nil {
+		return nil, nil, err
+	}
 	var rainTreeMsg typesP2P.RainTreeMessage
-	if err := proto.Unmarshal(data, &rainTreeMsg); err != nil {
-		return nil, err
+	if err := proto.Unmarshal(msgBz, &rainTreeMsg); err != nil {
+		return nil, nil, err
+	}
+
+	// Messages that cannot be authenticated are neither propagated nor handled by the application
+	if err := n.verifyMessage(&rainTreeMsg); err != nil {
+		return nil, nil, err
 	}
 
 	networkMessage := debug.PocketEvent{}
 	if err := proto.Unmarshal(rainTreeMsg.Data, &networkMessage); err != nil {
 		log.Println("Error decoding network message: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Continue RainTree propagation
 	if rainTreeMsg.Level > 0 {
-		if err := n.networkBroadcastAtLevel(rainTreeMsg.Data, rainTreeMsg.Level-1, rainTreeMsg.Nonce); err != nil {
-			return nil, err
+		if err := n.networkBroadcastAtLevel(copyMessageAtLevel(&rainTreeMsg, rainTreeMsg.Level-1)); err != nil {
+			return nil, nil, err
 		}
 	}
 
 	// Avoids this node from processing a messages / transactions is has already processed at the
 	// application layer. The logic above makes sure it is only propagated and returns.
 	// TODO(team): Add more tests to verify this is sufficient for deduping purposes.
-	if _, contains := n.mempool[rainTreeMsg.Nonce]; contains {
+	n.mempoolMu.Lock()
+	_, contains := n.mempool[rainTreeMsg.Nonce]
+	if !contains {
+		n.mempool[rainTreeMsg.Nonce] = struct{}{}
+	}
+	n.mempoolMu.Unlock()
+
+	if contains {
 		n.GetBus().
 			GetTelemetryModule().
 			GetEventMetricsAgent().
@@ -165,13 +191,11 @@ func (n *rainTreeNetwork) HandleNetworkData(data []byte) ([]byte, error) {
 				telemetry.P2P_RAINTREE_MESSAGE_EVENT_METRIC_HEIGHT_LABEL, blockHeight,
 			)
 
-		return nil, nil
+		return nil, nil, nil
 	}
 
-	n.mempool[rainTreeMsg.Nonce] = struct{}{}
-
 	// Return the data back to the caller so it can be handeled by the app specific bus
-	return rainTreeMsg.Data, nil
+	return rainTreeMsg.Data, rainTreeMsg.Origin, nil
 }
 
 func (n *rainTreeNetwork) GetAddrBook() typesP2P.AddrBook {
@@ -192,6 +216,20 @@ func (n *rainTreeNetwork) RemovePeerToAddrBook(peer *typesP2P.NetworkPeer) error
 	return nil
 }
 
+func (n *rainTreeNetwork) SetPeerScorer(scorer typesP2P.PeerScorer) {
+	n.peerScorer = scorer
+}
+
+func (n *rainTreeNetwork) isPeerBanned(address cryptoPocket.Address) bool {
+	return n.peerScorer != nil && n.peerScorer.IsBanned(address)
+}
+
+func (n *rainTreeNetwork) recordPeerEvent(address cryptoPocket.Address, event typesP2P.PeerEvent) {
+	if n.peerScorer != nil {
+		n.peerScorer.RecordEvent(address, event)
+	}
+}
+
 func (n *rainTreeNetwork) SetBus(bus modules.Bus) {
 	n.bus = bus
 }
@@ -133,8 +133,12 @@ func (pm *peersManager) getSelfIndexInAddrBook() (int, bool) {
 
 func (pm *peersManager) getMaxAddrBookLevels() uint32 {
 	peersManagerStateView := pm.getNetworkView()
-	addrBookSize := float64(len(peersManagerStateView.addrBookMap))
-	return uint32(math.Ceil(logBase(addrBookSize)))
+	return getMaxNumLevels(len(peersManagerStateView.addrBookMap))
+}
+
+// getMaxNumLevels returns the number of levels of the RainTree of an addr book with `addrBookLen` peers
+func getMaxNumLevels(addrBookLen int) uint32 {
+	return uint32(math.Ceil(logBase(float64(addrBookLen))))
 }
 
 func logBase(x float64) float64 {
@@ -165,8 +169,7 @@ type networkView struct {
 }
 
 func updateMaxNumLevels(pm *peersManager) {
-	addrBookSize := float64(len(pm.addrBook))
-	pm.maxNumLevels = uint32(math.Ceil(logBase(addrBookSize)))
+	pm.maxNumLevels = getMaxNumLevels(len(pm.addrBook))
 }
 
 func insertElementAtIndex[T any](slice []T, element T, index int) []T {
@@ -0,0 +1,141 @@
+package raintree
+
+import (
+	"fmt"
+)
+
+// SimulationConfig describes a RainTree broadcast over a network of `NumNodes` synthetic peers.
+// Peers are identified by their index in the (sorted) address book shared by every node.
+type SimulationConfig struct {
+	NumNodes    int
+	Originator  int
+	FailedNodes map[int]bool // Failed nodes neither handle nor propagate the messages sent to them
+}
+
+// SimulatedMessage is a RainTree message sent from one peer to another at a given level.
+type SimulatedMessage struct {
+	Level     uint32 `json:"level"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Delivered bool   `json:"delivered"` // False if the target has failed
+}
+
+// SimulatedLevel aggregates the messages sent at a given RainTree level.
+type SimulatedLevel struct {
+	Level          uint32 `json:"level"`
+	AddrBookLength int    `json:"addr_book_length"` // The length of the addr book the targets are picked from
+	NumMessages    int    `json:"num_messages"`
+	NumDelivered   int    `json:"num_delivered"`
+	NumNewNodes    int    `json:"num_new_nodes"` // The number of nodes reached for the first time at this level
+}
+
+// SimulationResult is the outcome of a simulated RainTree broadcast.
+type SimulationResult struct {
+	NumNodes       int                `json:"num_nodes"`
+	MaxNumLevels   uint32             `json:"max_num_levels"`
+	Originator     int                `json:"originator"`
+	FailedNodes    []int              `json:"failed_nodes"`
+	UnreachedNodes []int              `json:"unreached_nodes"` // Nodes that have not failed but did not receive the message
+	Levels         []SimulatedLevel   `json:"levels"`          // From `MaxNumLevels` down to 1
+	Messages       []SimulatedMessage `json:"messages"`
+	Coverage       float64            `json:"coverage"`   // The share of the nodes that have not failed which received the message
+	Redundancy     float64            `json:"redundancy"` // The number of delivered messages per reached node (excluding the originator)
+}
+
+type simulatedBroadcast struct {
+	node  int
+	level uint32
+}
+
+// Simulate broadcasts a message from the originator using the same target selection as `rainTreeNetwork`
+// and returns the messages sent, along with per level statistics.
+func Simulate(cfg SimulationConfig) (*SimulationResult, error) {
+	if cfg.NumNodes <= 0 {
+		return nil, fmt.Errorf("the number of nodes must be positive: %d", cfg.NumNodes)
+	}
+	if cfg.Originator < 0 || cfg.Originator >= cfg.NumNodes {
+		return nil, fmt.Errorf("the originator %d is not in the network of %d nodes", cfg.Originator, cfg.NumNodes)
+	}
+	if cfg.FailedNodes[cfg.Originator] {
+		return nil, fmt.Errorf("the originator %d cannot be a failed node", cfg.Originator)
+	}
+
+	maxNumLevels := getMaxNumLevels(cfg.NumNodes)
+	result := &SimulationResult{
+		NumNodes:       cfg.NumNodes,
+		MaxNumLevels:   maxNumLevels,
+		Originator:     cfg.Originator,
+		FailedNodes:    make([]int, 0),
+		UnreachedNodes: make([]int, 0),
+		Levels:         make([]SimulatedLevel, maxNumLevels),
+		Messages:       make([]SimulatedMessage, 0),
+	}
+	for i := range result.Levels {
+		level := maxNumLevels - uint32(i)
+		result.Levels[i] = SimulatedLevel{
+			Level:          level,
+			AddrBookLength: getAddrBookLengthAtLevel(cfg.NumNodes, maxNumLevels, level),
+		}
+	}
+
+	reached := make([]bool, cfg.NumNodes)
+	reached[cfg.Originator] = true
+	numDelivered := 0
+
+	// Every node handling a message at level L broadcasts it at level L-1, exactly like `HandleNetworkData`.
+	queue := []simulatedBroadcast{{node: cfg.Originator, level: maxNumLevels}}
+	for len(queue) > 0 {
+		broadcast := queue[0]
+		queue = queue[1:]
+		if broadcast.level == 0 {
+			continue
+		}
+
+		levelStats := &result.Levels[maxNumLevels-broadcast.level]
+		for _, targetPercentage := range []float64{firstMsgTargetPercentage, secondMsgTargetPercentage} {
+			i := getTargetIndex(targetPercentage, levelStats.AddrBookLength)
+			if i == 0 {
+				continue // Self is handled by the demotion below
+			}
+			to := (broadcast.node + i) % cfg.NumNodes
+			msg := SimulatedMessage{
+				Level:     broadcast.level,
+				From:      broadcast.node,
+				To:        to,
+				Delivered: !cfg.FailedNodes[to],
+			}
+			result.Messages = append(result.Messages, msg)
+			levelStats.NumMessages++
+			if !msg.Delivered {
+				continue
+			}
+			levelStats.NumDelivered++
+			numDelivered++
+			if !reached[to] {
+				reached[to] = true
+				levelStats.NumNewNodes++
+			}
+			queue = append(queue, simulatedBroadcast{node: to, level: broadcast.level - 1})
+		}
+		queue = append(queue, simulatedBroadcast{node: broadcast.node, level: broadcast.level - 1})
+	}
+
+	numReached, numAlive := 0, 0
+	for node := 0; node < cfg.NumNodes; node++ {
+		switch {
+		case cfg.FailedNodes[node]:
+			result.FailedNodes = append(result.FailedNodes, node)
+		case reached[node]:
+			numAlive++
+			numReached++
+		default:
+			numAlive++
+			result.UnreachedNodes = append(result.UnreachedNodes, node)
+		}
+	}
+	result.Coverage = float64(numReached) / float64(numAlive)
+	if numReached > 1 {
+		result.Redundancy = float64(numDelivered) / float64(numReached-1)
+	}
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package raintree
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate_CompleteNetworks(t *testing.T) {
+	// The number of messages each node receives, which match the reads expected in `p2p/module_raintree_test.go`
+	testCases := map[int][]int{
+		1:  {0},
+		2:  {0, 1},
+		3:  {0, 1, 1},
+		4:  {0, 2, 2, 1},
+		9:  {0, 1, 1, 1, 1, 1, 1, 1, 1},
+		18: {1, 1, 2, 1, 2, 1, 2, 1, 2, 1, 2, 1, 2, 1, 2, 1, 2, 1},
+	}
+	for numNodes, expectedNumReceived := range testCases {
+		t.Run(fmt.Sprintf("n=%d", numNodes), func(t *testing.T) {
+			result, err := Simulate(SimulationConfig{NumNodes: numNodes})
+			require.NoError(t, err)
+			require.Equal(t, 1.0, result.Coverage)
+			require.Empty(t, result.UnreachedNodes)
+
+			numReceived := make([]int, numNodes)
+			for _, msg := range result.Messages {
+				require.True(t, msg.Delivered)
+				numReceived[msg.To]++
+			}
+			require.Equal(t, expectedNumReceived, numReceived)
+		})
+	}
+}
+
+func TestSimulate_Levels(t *testing.T) {
+	result, err := Simulate(SimulationConfig{NumNodes: 27, Originator: 5})
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), result.MaxNumLevels)
+	require.Equal(t, 1.0, result.Coverage)
+	require.Equal(t, 1.0, result.Redundancy)
+
+	expectedLevels := []SimulatedLevel{
+		{Level: 3, AddrBookLength: 27, NumMessages: 2, NumDelivered: 2, NumNewNodes: 2},
+		{Level: 2, AddrBookLength: 18, NumMessages: 6, NumDelivered: 6, NumNewNodes: 6},
+		{Level: 1, AddrBookLength: 12, NumMessages: 18, NumDelivered: 18, NumNewNodes: 18},
+	}
+	require.Equal(t, expectedLevels, result.Levels)
+}
+
+func TestSimulate_FailedNodes(t *testing.T) {
+	// Node 9 is the first level target of the originator in a network of 27 nodes, so its whole subtree is lost
+	result, err := Simulate(SimulationConfig{NumNodes: 27, FailedNodes: map[int]bool{9: true}})
+	require.NoError(t, err)
+	require.Equal(t, []int{9}, result.FailedNodes)
+	require.Less(t, result.Coverage, 1.0)
+	require.NotEmpty(t, result.UnreachedNodes)
+
+	_, err = Simulate(SimulationConfig{NumNodes: 27, FailedNodes: map[int]bool{0: true}})
+	require.Error(t, err)
+	_, err = Simulate(SimulationConfig{NumNodes: 27, Originator: 27})
+	require.Error(t, err)
+}
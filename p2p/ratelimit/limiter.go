@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// The maximum number of keys a `KeyedLimiter` tracks before it starts evicting idle ones. A key is
+// idle once its bucket has fully refilled, so evicting it is indistinguishable from keeping it around.
+const maxTrackedKeys = 10000
+
+// TokenBucket is a thread safe token bucket that refills at `rate` tokens per second up to `burst` tokens.
+type TokenBucket struct {
+	m     sync.Mutex
+	clock clock.Clock
+	rate  float64
+	burst float64
+
+	tokens     float64
+	lastRefill time.Time
+}
+
+func NewTokenBucket(rate float64, burst uint32, clock clock.Clock) *TokenBucket {
+	return &TokenBucket{
+		clock:      clock,
+		rate:       rate,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastRefill: clock.Now(),
+	}
+}
+
+// Allow consumes a token from the bucket if one is available.
+func (b *TokenBucket) Allow() bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.allow(b.clock.Now())
+}
+
+// allow must be called while holding the lock.
+func (b *TokenBucket) allow(now time.Time) bool {
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// refill must be called while holding the lock.
+func (b *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.lastRefill); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.lastRefill = now
+	}
+}
+
+// KeyedLimiter maintains a separate `TokenBucket` per key (e.g. per peer) that all share the same rate and burst.
+type KeyedLimiter struct {
+	m       sync.Mutex
+	clock   clock.Clock
+	rate    float64
+	burst   uint32
+	buckets map[string]*TokenBucket
+}
+
+func NewKeyedLimiter(rate float64, burst uint32, clock clock.Clock) *KeyedLimiter {
+	return &KeyedLimiter{
+		clock:   clock,
+		rate:    rate,
+		burst:   burst,
+		buckets: make(map[string]*TokenBucket),
+	}
+}
+
+// Allow consumes a token from the bucket associated with `key` if one is available.
+func (l *KeyedLimiter) Allow(key string) bool {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	now := l.clock.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxTrackedKeys {
+			l.evictIdleBuckets(now)
+		}
+		b = NewTokenBucket(l.rate, l.burst, l.clock)
+		l.buckets[key] = b
+	}
+	return b.allow(now)
+}
+
+// evictIdleBuckets must be called while holding the lock.
+func (l *KeyedLimiter) evictIdleBuckets(now time.Time) {
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= b.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
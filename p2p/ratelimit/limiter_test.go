@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_BurstAndRefill(t *testing.T) {
+	clockMock := clock.NewMock()
+	bucket := NewTokenBucket(2, 3, clockMock)
+
+	for i := 0; i < 3; i++ {
+		require.True(t, bucket.Allow())
+	}
+	require.False(t, bucket.Allow())
+
+	clockMock.Add(500 * time.Millisecond)
+	require.True(t, bucket.Allow())
+	require.False(t, bucket.Allow())
+
+	// The bucket never refills past its burst
+	clockMock.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		require.True(t, bucket.Allow())
+	}
+	require.False(t, bucket.Allow())
+}
+
+func TestKeyedLimiter_KeysAreIndependent(t *testing.T) {
+	clockMock := clock.NewMock()
+	limiter := NewKeyedLimiter(1, 1, clockMock)
+
+	require.True(t, limiter.Allow("peer1"))
+	require.False(t, limiter.Allow("peer1"))
+	require.True(t, limiter.Allow("peer2"))
+
+	clockMock.Add(time.Second)
+	require.True(t, limiter.Allow("peer1"))
+}
+
+func TestKeyedLimiter_EvictsIdleKeys(t *testing.T) {
+	clockMock := clock.NewMock()
+	limiter := NewKeyedLimiter(1, 1, clockMock)
+
+	for i := 0; i < maxTrackedKeys; i++ {
+		require.True(t, limiter.Allow(string(rune(i))))
+	}
+	require.Len(t, limiter.buckets, maxTrackedKeys)
+
+	clockMock.Add(time.Second)
+	require.True(t, limiter.Allow("new_peer"))
+	require.Len(t, limiter.buckets, 1)
+}
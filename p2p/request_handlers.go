@@ -0,0 +1,92 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	"github.com/pokt-network/pocket/persistence/kvstore"
+	"github.com/pokt-network/pocket/shared/modules"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// initRequestResponse registers the built-in handlers for the data every node is expected to serve. Requests and
+// responses are not dispatched to subscribers (see `handleNetworkMessage`).
+func (m *p2pModule) initRequestResponse() error {
+	builtInHandlers := map[proto.Message]modules.P2PRequestHandler{
+		&typesP2P.BlockByHeightRequest{}: m.handleBlockByHeightRequest,
+		&typesP2P.TxByHashRequest{}:      m.handleTxByHashRequest,
+		&typesP2P.AddrBookRequest{}:      m.handleAddrBookRequest,
+	}
+	for req, handler := range builtInHandlers {
+		if err := m.RegisterRequestHandler(req, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *p2pModule) handleBlockByHeightRequest(anyReq *anypb.Any) (*anypb.Any, error) {
+	req := &typesP2P.BlockByHeightRequest{}
+	if err := anypb.UnmarshalTo(anyReq, req, proto.UnmarshalOptions{}); err != nil {
+		return nil, err
+	}
+
+	// The requester stops waiting for the response after the request timeout
+	ctx, cancel := context.WithTimeout(context.Background(), m.requestTimeout)
+	defer cancel()
+	persistenceContext, err := m.GetBus().GetPersistenceModule().NewReadContextWithCtx(ctx, int64(req.Height))
+	if err != nil {
+		return nil, err
+	}
+	defer persistenceContext.Close()
+
+	block, err := persistenceContext.GetBlock(int64(req.Height))
+	if err != nil {
+		return nil, fmt.Errorf("error getting block at height %d: %w", req.Height, err)
+	}
+	return anypb.New(&typesP2P.BlockByHeightResponse{Block: block})
+}
+
+// handleTxByHashRequest looks the transaction up in the mempool and then among the committed transactions.
+func (m *p2pModule) handleTxByHashRequest(anyReq *anypb.Any) (*anypb.Any, error) {
+	req := &typesP2P.TxByHashRequest{}
+	if err := anypb.UnmarshalTo(anyReq, req, proto.UnmarshalOptions{}); err != nil {
+		return nil, err
+	}
+
+	if tx, found := m.GetBus().GetUtilityModule().GetMempoolTransaction(req.Hash); found {
+		return anypb.New(&typesP2P.TxByHashResponse{Tx: tx})
+	}
+	txResult, err := m.GetBus().GetPersistenceModule().GetTransactionByHash(req.Hash)
+	if err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return nil, fmt.Errorf("transaction %s not found", req.Hash)
+		}
+		return nil, fmt.Errorf("error getting transaction %s: %w", req.Hash, err)
+	}
+	return anypb.New(&typesP2P.TxByHashResponse{Tx: txResult.GetTx()})
+}
+
+func (m *p2pModule) handleAddrBookRequest(anyReq *anypb.Any) (*anypb.Any, error) {
+	addrBook := m.getNetwork().GetAddrBook()
+	peers := make([]*typesP2P.PeerInfo, 0, len(addrBook))
+	for _, peer := range addrBook {
+		peerInfo := &typesP2P.PeerInfo{
+			Address:    peer.Address,
+			ServiceUrl: peer.ServiceUrl,
+		}
+		if peer.PublicKey != nil {
+			peerInfo.PublicKey = peer.PublicKey.Bytes()
+		}
+		// Peers should reach us through the addresses we advertise rather than the ones in the genesis
+		if advertisedAddresses := m.p2pConfig.GetAdvertisedAddresses(); len(advertisedAddresses) > 0 && peer.Address.Equals(m.address) {
+			peerInfo.ServiceUrl = advertisedAddresses[0]
+			peerInfo.AdvertisedAddresses = advertisedAddresses
+		}
+		peers = append(peers, peerInfo)
+	}
+	return anypb.New(&typesP2P.AddrBookResponse{Peers: peers})
+}
@@ -0,0 +1,226 @@
+package p2p
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"github.com/pokt-network/pocket/shared/debug"
+	"github.com/pokt-network/pocket/shared/modules"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	defaultRequestTimeout    = 5 * time.Second
+	defaultRequestMaxRetries = 2
+
+	// The requests of peers are handled by a few workers off the event dispatcher. Requests are dropped while all
+	// the workers are busy and the queue is full, in which case the requester times out and retries.
+	// IMPROVE: Make these configurable if the defaults turn out to be insufficient.
+	numRequestWorkers = 4
+	requestQueueSize  = 100
+)
+
+// pendingRequest is a request sent to `peer`, waiting for its response.
+type pendingRequest struct {
+	peer   cryptoPocket.Address
+	respCh chan *typesP2P.P2PResponse
+}
+
+// inboundRequest is a request received from `origin`, waiting to be handled.
+type inboundRequest struct {
+	msg    *anypb.Any
+	origin cryptoPocket.Address
+}
+
+func (m *p2pModule) Request(addr cryptoPocket.Address, req *anypb.Any) (*anypb.Any, error) {
+	numAttempts := int(m.requestMaxRetries) + 1
+
+	var peers []cryptoPocket.Address
+	if addr == nil {
+		if peers = m.getRequestPeers(); len(peers) == 0 {
+			return nil, fmt.Errorf("no peers available to send the request to")
+		}
+	}
+
+	var err error
+	for attempt := 0; attempt < numAttempts; attempt++ {
+		peer := addr
+		if peer == nil {
+			peer = peers[attempt%len(peers)]
+		}
+
+		var resp *anypb.Any
+		if resp, err = m.requestFromPeer(peer, req); err == nil {
+			return resp, nil
+		}
+		log.Printf("[WARN] Request attempt %d/%d to peer %s failed: %v\n", attempt+1, numAttempts, peer, err)
+	}
+	return nil, fmt.Errorf("request failed after %d attempts: %w", numAttempts, err)
+}
+
+func (m *p2pModule) RegisterRequestHandler(req proto.Message, handler modules.P2PRequestHandler) error {
+	if handler == nil {
+		return fmt.Errorf("cannot register a nil handler for %s", proto.MessageName(req))
+	}
+	typeUrl := getTypeUrl(req)
+
+	m.requestsMu.Lock()
+	defer m.requestsMu.Unlock()
+	if _, ok := m.requestHandlers[typeUrl]; ok {
+		return fmt.Errorf("a handler is already registered for %s", proto.MessageName(req))
+	}
+	m.requestHandlers[typeUrl] = handler
+	return nil
+}
+
+func (m *p2pModule) requestFromPeer(peer cryptoPocket.Address, req *anypb.Any) (*anypb.Any, error) {
+	requestId := rand.Uint64()
+	respCh := make(chan *typesP2P.P2PResponse, 1)
+
+	m.requestsMu.Lock()
+	m.pendingRequests[requestId] = &pendingRequest{peer: peer, respCh: respCh}
+	m.requestsMu.Unlock()
+	defer func() {
+		m.requestsMu.Lock()
+		delete(m.pendingRequests, requestId)
+		m.requestsMu.Unlock()
+	}()
+
+	anyReq, err := anypb.New(&typesP2P.P2PRequest{
+		RequestId: requestId,
+		Data:      req,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Send(peer, anyReq, debug.PocketTopic_P2P_REQUEST_TOPIC); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-respCh:
+		if resp.Error != "" {
+			return nil, fmt.Errorf("peer %s failed to handle the request: %s", peer, resp.Error)
+		}
+		m.updateAdvertisedAddresses(peer, resp.Data)
+		return resp.Data, nil
+	case <-time.After(m.requestTimeout):
+		return nil, fmt.Errorf("timed out waiting for the response of peer %s", peer)
+	}
+}
+
+// enqueueRequest queues a request received from `origin` to be handled by one of the request workers. It never
+// blocks so requests cannot hold up the inbound workers either.
+func (m *p2pModule) enqueueRequest(msg *anypb.Any, origin cryptoPocket.Address) {
+	select {
+	case m.requestQueue <- &inboundRequest{msg: msg, origin: origin}:
+	default:
+		m.emitInboundMessageDropped(dropReasonRequestQueue)
+	}
+}
+
+// runRequestWorker handles the queued requests until the module is stopped.
+func (m *p2pModule) runRequestWorker() {
+	for {
+		select {
+		case <-m.stopped:
+			return
+		case req := <-m.requestQueue:
+			if err := m.handleRequest(req.msg, req.origin); err != nil {
+				log.Printf("Error handling request from peer %s: %v\n", req.origin, err)
+			}
+		}
+	}
+}
+
+// handleRequest replies to a request from `origin` using the handler registered for its type. The response is
+// sent to the node that signed the request, so a peer cannot direct responses at another node.
+func (m *p2pModule) handleRequest(msg *anypb.Any, origin cryptoPocket.Address) error {
+	req := &typesP2P.P2PRequest{}
+	if err := anypb.UnmarshalTo(msg, req, proto.UnmarshalOptions{}); err != nil {
+		return err
+	}
+	if req.Data == nil {
+		return fmt.Errorf("received request %d without data", req.RequestId)
+	}
+
+	m.requestsMu.RLock()
+	handler, ok := m.requestHandlers[req.Data.TypeUrl]
+	m.requestsMu.RUnlock()
+
+	resp := &typesP2P.P2PResponse{RequestId: req.RequestId}
+	if !ok {
+		resp.Error = fmt.Sprintf("unsupported request type %s", req.Data.TypeUrl)
+	} else if data, err := handler(req.Data); err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Data = data
+	}
+
+	anyResp, err := anypb.New(resp)
+	if err != nil {
+		return err
+	}
+	return m.Send(origin, anyResp, debug.PocketTopic_P2P_RESPONSE_TOPIC)
+}
+
+// handleResponse hands the response over to the pending request it belongs to, if any, provided it comes from
+// the peer the request was sent to.
+func (m *p2pModule) handleResponse(msg *anypb.Any, origin cryptoPocket.Address) error {
+	resp := &typesP2P.P2PResponse{}
+	if err := anypb.UnmarshalTo(msg, resp, proto.UnmarshalOptions{}); err != nil {
+		return err
+	}
+
+	m.requestsMu.RLock()
+	pending, ok := m.pendingRequests[resp.RequestId]
+	m.requestsMu.RUnlock()
+	if !ok {
+		return nil // The request timed out or was already answered
+	}
+	if !pending.peer.Equals(origin) {
+		return fmt.Errorf("received the response to request %d from %s rather than %s", resp.RequestId, origin, pending.peer)
+	}
+
+	select {
+	case pending.respCh <- resp:
+	default:
+	}
+	return nil
+}
+
+// getRequestPeers returns the peers in the address book, excluding self and banned peers, in random order.
+func (m *p2pModule) getRequestPeers() []cryptoPocket.Address {
+	peers := make([]cryptoPocket.Address, 0)
+	for _, peer := range m.getNetwork().GetAddrBook() {
+		if peer.Address.Equals(m.address) || m.peerScorer.IsBanned(peer.Address) {
+			continue
+		}
+		peers = append(peers, peer.Address)
+	}
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+	return peers
+}
+
+func getRequestTimeout(cfg *typesP2P.RequestResponseConfig) time.Duration {
+	if timeout := cfg.GetTimeoutMsec(); timeout > 0 {
+		return time.Duration(timeout) * time.Millisecond
+	}
+	return defaultRequestTimeout
+}
+
+func getRequestMaxRetries(cfg *typesP2P.RequestResponseConfig) uint32 {
+	if maxRetries := cfg.GetMaxRetries(); maxRetries > 0 {
+		return maxRetries
+	}
+	return defaultRequestMaxRetries
+}
+
+func getTypeUrl(msg proto.Message) string {
+	return "type.googleapis.com/" + string(proto.MessageName(msg))
+}
@@ -0,0 +1,180 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/pokt-network/pocket/p2p/simnet"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"github.com/pokt-network/pocket/shared/debug"
+	"github.com/pokt-network/pocket/shared/modules"
+	modulesMock "github.com/pokt-network/pocket/shared/modules/mocks"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRequestResponse_AddrBook(t *testing.T) {
+	p2pModules := startRequestResponseTestModules(t, 4, simnet.NewHub(simnet.HubConfig{}, clock.New()))
+	requester := p2pModules[validatorId(t, 1)]
+
+	anyReq, err := anypb.New(&typesP2P.AddrBookRequest{})
+	require.NoError(t, err)
+	anyResp, err := requester.Request(nil, anyReq)
+	require.NoError(t, err)
+
+	resp := &typesP2P.AddrBookResponse{}
+	require.NoError(t, anypb.UnmarshalTo(anyResp, resp, proto.UnmarshalOptions{}))
+	require.Len(t, resp.Peers, 4)
+	for _, peer := range resp.Peers {
+		require.NotEmpty(t, peer.Address)
+		require.NotEmpty(t, peer.PublicKey)
+		require.NotEmpty(t, peer.ServiceUrl)
+	}
+}
+
+func TestRequestResponse_CustomHandler(t *testing.T) {
+	p2pModules := startRequestResponseTestModules(t, 2, simnet.NewHub(simnet.HubConfig{}, clock.New()))
+	requester := p2pModules[validatorId(t, 1)]
+	responder := p2pModules[validatorId(t, 2)]
+
+	handler := func(anyReq *anypb.Any) (*anypb.Any, error) {
+		req := &wrapperspb.StringValue{}
+		if err := anypb.UnmarshalTo(anyReq, req, proto.UnmarshalOptions{}); err != nil {
+			return nil, err
+		}
+		return anypb.New(wrapperspb.String("pong: " + req.Value))
+	}
+	require.NoError(t, responder.RegisterRequestHandler(&wrapperspb.StringValue{}, handler))
+	require.Error(t, responder.RegisterRequestHandler(&wrapperspb.StringValue{}, handler), "duplicate handler")
+
+	anyReq, err := anypb.New(wrapperspb.String("ping"))
+	require.NoError(t, err)
+	anyResp, err := requester.Request(responder.address, anyReq)
+	require.NoError(t, err)
+
+	resp := &wrapperspb.StringValue{}
+	require.NoError(t, anypb.UnmarshalTo(anyResp, resp, proto.UnmarshalOptions{}))
+	require.Equal(t, "pong: ping", resp.Value)
+
+	// Requests of a type without a handler are answered with an error
+	anyReq, err = anypb.New(wrapperspb.Bool(true))
+	require.NoError(t, err)
+	_, err = requester.Request(responder.address, anyReq)
+	require.Error(t, err)
+}
+
+// The handlers run on the request workers, which must not be needed to deliver the responses of the requests they
+// make themselves.
+func TestRequestResponse_RequestFromHandler(t *testing.T) {
+	p2pModules := startRequestResponseTestModules(t, 3, simnet.NewHub(simnet.HubConfig{}, clock.New()))
+	requester := p2pModules[validatorId(t, 1)]
+	relay := p2pModules[validatorId(t, 2)]
+	responder := p2pModules[validatorId(t, 3)]
+
+	require.NoError(t, relay.RegisterRequestHandler(&wrapperspb.StringValue{}, func(anyReq *anypb.Any) (*anypb.Any, error) {
+		anyAddrBookReq, err := anypb.New(&typesP2P.AddrBookRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return relay.Request(responder.address, anyAddrBookReq)
+	}))
+
+	anyReq, err := anypb.New(wrapperspb.String("relay"))
+	require.NoError(t, err)
+	anyResp, err := requester.Request(relay.address, anyReq)
+	require.NoError(t, err)
+
+	resp := &typesP2P.AddrBookResponse{}
+	require.NoError(t, anypb.UnmarshalTo(anyResp, resp, proto.UnmarshalOptions{}))
+	require.Len(t, resp.Peers, 3)
+}
+
+// Slow handlers must not hold up the events routed by the dispatcher, consensus messages in particular.
+func TestRequestResponse_HandlersRunOffTheDispatcher(t *testing.T) {
+	p2pModules := startRequestResponseTestModules(t, 2, simnet.NewHub(simnet.HubConfig{}, clock.New()))
+	requester := p2pModules[validatorId(t, 1)]
+	responder := p2pModules[validatorId(t, 2)]
+
+	handlerStarted, releaseHandler := make(chan struct{}), make(chan struct{})
+	require.NoError(t, responder.RegisterRequestHandler(&wrapperspb.StringValue{}, func(anyReq *anypb.Any) (*anypb.Any, error) {
+		close(handlerStarted)
+		<-releaseHandler
+		return anyReq, nil
+	}))
+	consensusMsgs := make(chan *anypb.Any, 1)
+	require.NoError(t, responder.Subscribe(debug.PocketTopic_CONSENSUS_MESSAGE_TOPIC, func(msg *anypb.Any) error {
+		consensusMsgs <- msg
+		return nil
+	}))
+
+	anyReq, err := anypb.New(wrapperspb.String("slow"))
+	require.NoError(t, err)
+	requestErr := make(chan error, 1)
+	go func() {
+		_, err := requester.Request(responder.address, anyReq)
+		requestErr <- err
+	}()
+	<-handlerStarted
+
+	require.NoError(t, requester.Send(responder.address, anyReq, debug.PocketTopic_CONSENSUS_MESSAGE_TOPIC))
+	select {
+	case <-consensusMsgs:
+	case <-time.After(time.Second):
+		t.Fatal("the consensus message was held up by the request handler")
+	}
+
+	close(releaseHandler)
+	require.NoError(t, <-requestErr)
+}
+
+func TestRequestResponse_IgnoresResponsesFromOtherPeers(t *testing.T) {
+	peer, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+	other, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+
+	respCh := make(chan *typesP2P.P2PResponse, 1)
+	m := &p2pModule{pendingRequests: map[uint64]*pendingRequest{1: {peer: peer, respCh: respCh}}}
+	anyResp, err := anypb.New(&typesP2P.P2PResponse{RequestId: 1})
+	require.NoError(t, err)
+
+	require.Error(t, m.handleResponse(anyResp, other))
+	require.Empty(t, respCh)
+	require.NoError(t, m.handleResponse(anyResp, peer))
+	require.Len(t, respCh, 1)
+}
+
+func TestRequestResponse_TimeoutAndRetries(t *testing.T) {
+	hub := simnet.NewHub(simnet.HubConfig{}, clock.New())
+	p2pModules := startRequestResponseTestModules(t, 2, hub)
+	requester := p2pModules[validatorId(t, 1)]
+	requester.requestTimeout = 50 * time.Millisecond
+
+	// The responder can receive the request, but its response never makes it back
+	hub.SetConfig(simnet.HubConfig{LossRate: 1})
+
+	anyReq, err := anypb.New(&typesP2P.AddrBookRequest{})
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = requester.Request(p2pModules[validatorId(t, 2)].address, anyReq)
+	require.Error(t, err)
+	require.GreaterOrEqual(t, time.Since(start), time.Duration(requester.requestMaxRetries+1)*requester.requestTimeout)
+	require.Empty(t, requester.pendingRequests)
+}
+
+func startRequestResponseTestModules(t *testing.T, numValidators int, hub *simnet.Hub) map[string]*p2pModule {
+	telemetryMock := prepareTelemetryMock(t)
+	return startSimulatedP2PModules(t, numValidators, hub, func(consensusMock *modulesMock.MockConsensusModule) modules.Bus {
+		ctrl := gomock.NewController(t)
+		busMock := modulesMock.NewMockBus(ctrl)
+		busMock.EXPECT().GetConsensusModule().Return(consensusMock).AnyTimes()
+		busMock.EXPECT().GetTelemetryModule().Return(telemetryMock).AnyTimes()
+		return busMock
+	})
+}
@@ -0,0 +1,167 @@
+package scoring
+
+import (
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+)
+
+// Scores are bounded so a long history of good behaviour cannot shield a peer that starts
+// misbehaving, and a burst of bad behaviour does not take forever to recover from.
+const (
+	maxScore = float64(100)
+	minScore = float64(-100)
+
+	defaultBanThreshold      = float64(-50)
+	defaultBanDuration       = 5 * time.Minute
+	defaultDecayHalfLife     = 1 * time.Minute
+	negligibleScoreMagnitude = float64(0.01)
+)
+
+// IMPROVE: Make the weights configurable once we have data from the testnet on how peers behave.
+var peerEventWeights = map[typesP2P.PeerEvent]float64{
+	typesP2P.PeerEventDialFailure:      -5,
+	typesP2P.PeerEventInvalidMessage:   -20,
+	typesP2P.PeerEventInvalidSignature: -50,
+	typesP2P.PeerEventRateLimited:      -10,
+	typesP2P.PeerEventUsefulMessage:    1,
+}
+
+var _ typesP2P.PeerScorer = &peerScorer{}
+
+type peerScore struct {
+	score       float64
+	lastUpdated time.Time
+	bannedUntil time.Time
+}
+
+// peerScorer keeps an in-memory score per peer address that decays exponentially back to neutral (0).
+// Peers whose score drops to `banThreshold` are banned for `banDuration`, after which they start
+// from a neutral score again. Only the peers that are not neutral are kept track of, so the scores
+// do not grow with every address the node ever heard of.
+type peerScorer struct {
+	m          sync.Mutex
+	clock      clock.Clock
+	scores     map[string]*peerScore
+	lastPruned time.Time
+
+	banThreshold  float64
+	banDuration   time.Duration
+	decayHalfLife time.Duration
+}
+
+func NewPeerScorer(cfg *typesP2P.PeerScoringConfig, clock clock.Clock) typesP2P.PeerScorer {
+	ps := &peerScorer{
+		clock:      clock,
+		scores:     make(map[string]*peerScore),
+		lastPruned: clock.Now(),
+
+		banThreshold:  defaultBanThreshold,
+		banDuration:   defaultBanDuration,
+		decayHalfLife: defaultDecayHalfLife,
+	}
+	if threshold := cfg.GetBanThreshold(); threshold < 0 {
+		ps.banThreshold = math.Max(threshold, minScore)
+	}
+	if duration := cfg.GetBanDurationSec(); duration > 0 {
+		ps.banDuration = time.Duration(duration) * time.Second
+	}
+	if halfLife := cfg.GetDecayHalfLifeSec(); halfLife > 0 {
+		ps.decayHalfLife = time.Duration(halfLife) * time.Second
+	}
+	return ps
+}
+
+func (ps *peerScorer) RecordEvent(address cryptoPocket.Address, event typesP2P.PeerEvent) {
+	if address == nil {
+		return
+	}
+
+	ps.m.Lock()
+	defer ps.m.Unlock()
+
+	now := ps.clock.Now()
+	ps.pruneNeutralScores(now)
+
+	key := address.String()
+	s, ok := ps.scores[key]
+	if !ok {
+		s = &peerScore{lastUpdated: now}
+		ps.scores[key] = s
+	}
+	ps.decay(s, now)
+	if now.Before(s.bannedUntil) {
+		return // The peer is already banned; there's nothing more to penalize
+	}
+
+	s.score = math.Max(minScore, math.Min(maxScore, s.score+peerEventWeights[event]))
+	if s.score <= ps.banThreshold {
+		log.Printf("[WARN] Banning peer %s for %s after %s (score: %.2f)\n", address, ps.banDuration, event, s.score)
+		s.bannedUntil = now.Add(ps.banDuration)
+		s.score = 0
+	}
+	if isNeutral(s, now) {
+		delete(ps.scores, key)
+	}
+}
+
+func (ps *peerScorer) GetScore(address cryptoPocket.Address) float64 {
+	ps.m.Lock()
+	defer ps.m.Unlock()
+
+	key := address.String()
+	s, ok := ps.scores[key]
+	if !ok {
+		return 0
+	}
+	now := ps.clock.Now()
+	ps.decay(s, now)
+	if isNeutral(s, now) {
+		delete(ps.scores, key)
+	}
+	return s.score
+}
+
+func (ps *peerScorer) IsBanned(address cryptoPocket.Address) bool {
+	ps.m.Lock()
+	defer ps.m.Unlock()
+	s, ok := ps.scores[address.String()]
+	return ok && ps.clock.Now().Before(s.bannedUntil)
+}
+
+// decay applies the decay of the score since it was last updated. It must be called while holding the lock.
+func (ps *peerScorer) decay(s *peerScore, now time.Time) {
+	elapsed := now.Sub(s.lastUpdated)
+	if elapsed > 0 {
+		s.score *= math.Pow(0.5, float64(elapsed)/float64(ps.decayHalfLife))
+		if math.Abs(s.score) < negligibleScoreMagnitude {
+			s.score = 0
+		}
+		s.lastUpdated = now
+	}
+}
+
+// pruneNeutralScores forgets the peers whose score decayed back to neutral and whose ban expired, at most once
+// per half-life since the scores do not decay much faster than that. It must be called while holding the lock.
+func (ps *peerScorer) pruneNeutralScores(now time.Time) {
+	if now.Sub(ps.lastPruned) < ps.decayHalfLife {
+		return
+	}
+	ps.lastPruned = now
+	for key, s := range ps.scores {
+		ps.decay(s, now)
+		if isNeutral(s, now) {
+			delete(ps.scores, key)
+		}
+	}
+}
+
+// isNeutral returns whether the peer is scored as if it was unknown.
+func isNeutral(s *peerScore, now time.Time) bool {
+	return s.score == 0 && !now.Before(s.bannedUntil)
+}
@@ -0,0 +1,103 @@
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerScorer_BanAfterMisbehaviour(t *testing.T) {
+	clockMock := clock.NewMock()
+	scorer := NewPeerScorer(nil, clockMock)
+
+	addr, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+
+	scorer.RecordEvent(addr, typesP2P.PeerEventInvalidMessage)
+	scorer.RecordEvent(addr, typesP2P.PeerEventInvalidMessage)
+	require.False(t, scorer.IsBanned(addr))
+	require.Equal(t, float64(-40), scorer.GetScore(addr))
+
+	scorer.RecordEvent(addr, typesP2P.PeerEventInvalidMessage)
+	require.True(t, scorer.IsBanned(addr))
+
+	clockMock.Add(defaultBanDuration - time.Second)
+	require.True(t, scorer.IsBanned(addr))
+
+	clockMock.Add(time.Second)
+	require.False(t, scorer.IsBanned(addr))
+	require.Equal(t, float64(0), scorer.GetScore(addr))
+}
+
+func TestPeerScorer_ScoreDecays(t *testing.T) {
+	clockMock := clock.NewMock()
+	scorer := NewPeerScorer(&typesP2P.PeerScoringConfig{DecayHalfLifeSec: 10}, clockMock)
+
+	addr, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+
+	scorer.RecordEvent(addr, typesP2P.PeerEventInvalidMessage)
+	require.Equal(t, float64(-20), scorer.GetScore(addr))
+
+	clockMock.Add(10 * time.Second)
+	require.InDelta(t, float64(-10), scorer.GetScore(addr), 0.001)
+
+	clockMock.Add(10 * time.Minute)
+	require.Equal(t, float64(0), scorer.GetScore(addr))
+}
+
+func TestPeerScorer_ConfigOverrides(t *testing.T) {
+	clockMock := clock.NewMock()
+	scorer := NewPeerScorer(&typesP2P.PeerScoringConfig{
+		BanThreshold:   -5,
+		BanDurationSec: 1,
+	}, clockMock)
+
+	addr, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+
+	scorer.RecordEvent(addr, typesP2P.PeerEventDialFailure)
+	require.True(t, scorer.IsBanned(addr))
+
+	clockMock.Add(time.Second)
+	require.False(t, scorer.IsBanned(addr))
+}
+
+func TestPeerScorer_UnknownPeer(t *testing.T) {
+	scorer := NewPeerScorer(nil, clock.NewMock())
+
+	addr, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+
+	require.False(t, scorer.IsBanned(addr))
+	require.Equal(t, float64(0), scorer.GetScore(addr))
+	require.NotPanics(t, func() { scorer.RecordEvent(nil, typesP2P.PeerEventInvalidSignature) })
+}
+
+func TestPeerScorer_ForgetsNeutralPeers(t *testing.T) {
+	clockMock := clock.NewMock()
+	scorer := NewPeerScorer(nil, clockMock).(*peerScorer)
+
+	queried, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+	misbehaving, err := cryptoPocket.GenerateAddress()
+	require.NoError(t, err)
+
+	// reading the score of a peer does not keep track of it
+	require.Equal(t, float64(0), scorer.GetScore(queried))
+	require.Empty(t, scorer.scores)
+
+	scorer.RecordEvent(misbehaving, typesP2P.PeerEventInvalidMessage)
+	require.Len(t, scorer.scores, 1)
+
+	// the peer is forgotten once its score decayed back to neutral, even if it is never queried again
+	clockMock.Add(time.Hour)
+	scorer.RecordEvent(queried, typesP2P.PeerEventUsefulMessage)
+	require.Len(t, scorer.scores, 1)
+	require.Equal(t, float64(1), scorer.GetScore(queried))
+	require.Equal(t, float64(0), scorer.GetScore(misbehaving))
+}
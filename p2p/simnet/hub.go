@@ -0,0 +1,152 @@
+package simnet
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+)
+
+// The number of messages buffered per listener; messages delivered to a full listener are dropped
+// the same way a congested network would drop them.
+const listenerBufferSize = 10000
+
+// HubConfig describes the conditions of the simulated network. The zero value is a perfect network
+// that delivers every message, in order, immediately.
+type HubConfig struct {
+	Latency      time.Duration // The base delay of every message
+	Jitter       time.Duration // A random delay in [0, Jitter) added on top of the latency
+	LossRate     float64       // The probability [0, 1] of a message being silently dropped
+	ReorderRate  float64       // The probability [0, 1] of a message being held back by `ReorderDelay`
+	ReorderDelay time.Duration // The extra delay applied to messages picked for reordering
+	Seed         int64         // Seed of the random number generator so runs are reproducible
+}
+
+// Hub is an in-memory network connecting any number of simulated transports in the same process.
+// Listeners are registered by host (e.g. the service url of a validator) and dialers write to them
+// through the hub, which applies the latency, loss, reordering and partitions of the simulated network.
+type Hub struct {
+	m     sync.Mutex
+	cfg   HubConfig
+	clock clock.Clock
+	rand  *rand.Rand
+
+	listeners  map[string]*listener
+	partitions map[string]int // Hosts in different partitions cannot reach each other; unlisted hosts are in partition 0
+}
+
+func NewHub(cfg HubConfig, clock clock.Clock) *Hub {
+	return &Hub{
+		cfg:        cfg,
+		clock:      clock,
+		rand:       rand.New(rand.NewSource(cfg.Seed)),
+		listeners:  make(map[string]*listener),
+		partitions: make(map[string]int),
+	}
+}
+
+// NewListener registers a listener receiving all the data written to `host`.
+func (h *Hub) NewListener(host string) (typesP2P.Transport, error) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if _, ok := h.listeners[host]; ok {
+		return nil, fmt.Errorf("a listener is already registered for host %s", host)
+	}
+	l := &listener{
+		hub:   h,
+		host:  host,
+		inbox: make(chan *packet, listenerBufferSize),
+		done:  make(chan struct{}),
+	}
+	h.listeners[host] = l
+	return l, nil
+}
+
+// NewDialer creates a dialer writing from `fromHost` to the listener registered for `toHost`.
+func (h *Hub) NewDialer(fromHost, toHost string) typesP2P.Transport {
+	return &dialer{
+		hub:      h,
+		fromHost: fromHost,
+		toHost:   toHost,
+	}
+}
+
+// SetConfig updates the conditions of the network for all the messages sent from now on.
+func (h *Hub) SetConfig(cfg HubConfig) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.cfg = cfg
+}
+
+// Partition splits the network so the hosts in each group can only reach hosts in the same group.
+// Hosts not part of any group end up in a partition of their own shared with all other unlisted hosts.
+func (h *Hub) Partition(groups ...[]string) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.partitions = make(map[string]int)
+	for i, group := range groups {
+		for _, host := range group {
+			h.partitions[host] = i + 1
+		}
+	}
+}
+
+// Heal removes all the partitions from the network.
+func (h *Hub) Heal() {
+	h.Partition()
+}
+
+// send delivers the data to the listener of `toHost` according to the configured network conditions.
+func (h *Hub) send(fromHost, toHost string, data []byte) error {
+	h.m.Lock()
+	l, ok := h.listeners[toHost]
+	if !ok {
+		h.m.Unlock()
+		return fmt.Errorf("no listener found for host %s", toHost)
+	}
+	if h.partitions[fromHost] != h.partitions[toHost] {
+		h.m.Unlock()
+		return fmt.Errorf("host %s is unreachable from %s", toHost, fromHost)
+	}
+	if h.cfg.LossRate > 0 && h.rand.Float64() < h.cfg.LossRate {
+		h.m.Unlock()
+		return nil
+	}
+	delay := h.getDelay()
+	h.m.Unlock()
+
+	// The data is copied so the sender can reuse its buffer, like it would with a real connection
+	p := &packet{
+		data:     append([]byte(nil), data...),
+		fromHost: fromHost,
+	}
+	if delay == 0 {
+		l.deliver(p)
+		return nil
+	}
+	h.clock.AfterFunc(delay, func() { l.deliver(p) })
+	return nil
+}
+
+// getDelay must be called while holding the lock.
+func (h *Hub) getDelay() time.Duration {
+	delay := h.cfg.Latency
+	if h.cfg.Jitter > 0 {
+		delay += time.Duration(h.rand.Int63n(int64(h.cfg.Jitter)))
+	}
+	if h.cfg.ReorderRate > 0 && h.rand.Float64() < h.cfg.ReorderRate {
+		delay += h.cfg.ReorderDelay
+	}
+	return delay
+}
+
+func (h *Hub) removeListener(host string) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	delete(h.listeners, host)
+}
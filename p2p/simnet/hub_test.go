@@ -0,0 +1,100 @@
+package simnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_Delivery(t *testing.T) {
+	hub := NewHub(HubConfig{}, clock.NewMock())
+	l := newTestListener(t, hub, "val_1")
+
+	require.NoError(t, hub.NewDialer("val_2", "val_1").Write([]byte("data")))
+	data, fromHost, err := l.ReadFrom()
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), data)
+	require.Equal(t, "val_2", fromHost)
+
+	require.Error(t, hub.NewDialer("val_2", "val_3").Write([]byte("data")), "no listener for val_3")
+
+	_, err = hub.NewListener("val_1")
+	require.Error(t, err, "duplicate listener for val_1")
+
+	require.NoError(t, l.Close())
+	_, err = l.Read()
+	require.Error(t, err)
+	require.Error(t, hub.NewDialer("val_2", "val_1").Write([]byte("data")), "closed listener")
+}
+
+func TestHub_Loss(t *testing.T) {
+	hub := NewHub(HubConfig{LossRate: 1}, clock.NewMock())
+	l := newTestListener(t, hub, "val_1")
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, hub.NewDialer("val_2", "val_1").Write([]byte("data")))
+	}
+	require.Len(t, l.(*listener).inbox, 0)
+}
+
+func TestHub_LatencyAndReordering(t *testing.T) {
+	clockMock := clock.NewMock()
+	hub := NewHub(HubConfig{
+		Latency:      10 * time.Millisecond,
+		ReorderRate:  1,
+		ReorderDelay: 100 * time.Millisecond,
+	}, clockMock)
+	l := newTestListener(t, hub, "val_1")
+	d := hub.NewDialer("val_2", "val_1")
+
+	require.NoError(t, d.Write([]byte("first")))
+	hub.SetConfig(HubConfig{Latency: 10 * time.Millisecond})
+	require.NoError(t, d.Write([]byte("second")))
+
+	clockMock.Add(5 * time.Millisecond)
+	require.Len(t, l.(*listener).inbox, 0)
+
+	clockMock.Add(5 * time.Millisecond)
+	requireRead(t, l, "second")
+
+	clockMock.Add(100 * time.Millisecond)
+	requireRead(t, l, "first")
+}
+
+func TestHub_Partitions(t *testing.T) {
+	hub := NewHub(HubConfig{}, clock.NewMock())
+	l1 := newTestListener(t, hub, "val_1")
+	newTestListener(t, hub, "val_2")
+	newTestListener(t, hub, "val_3")
+
+	hub.Partition([]string{"val_1"}, []string{"val_2", "val_3"})
+	require.Error(t, hub.NewDialer("val_2", "val_1").Write([]byte("data")))
+	require.NoError(t, hub.NewDialer("val_2", "val_3").Write([]byte("data")))
+
+	hub.Heal()
+	require.NoError(t, hub.NewDialer("val_2", "val_1").Write([]byte("data")))
+	requireRead(t, l1, "data")
+}
+
+func newTestListener(t *testing.T, hub *Hub, host string) typesP2P.PeerAwareTransport {
+	l, err := hub.NewListener(host)
+	require.NoError(t, err)
+	return l.(typesP2P.PeerAwareTransport)
+}
+
+func requireRead(t *testing.T, l typesP2P.Transport, expected string) {
+	done := make(chan []byte)
+	go func() {
+		data, _ := l.Read()
+		done <- data
+	}()
+	select {
+	case data := <-done:
+		require.Equal(t, expected, string(data))
+	case <-time.After(time.Second):
+		t.Fatalf("Timeout waiting for %s", expected)
+	}
+}
@@ -0,0 +1,97 @@
+package simnet
+
+import (
+	"fmt"
+	"sync"
+
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+)
+
+var _ typesP2P.PeerAwareTransport = &listener{}
+var _ typesP2P.PeerAwareTransport = &dialer{}
+
+type packet struct {
+	data     []byte
+	fromHost string
+}
+
+type listener struct {
+	hub  *Hub
+	host string
+
+	inbox     chan *packet
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *listener) IsListener() bool {
+	return true
+}
+
+func (l *listener) Read() ([]byte, error) {
+	data, _, err := l.ReadFrom()
+	return data, err
+}
+
+func (l *listener) ReadFrom() ([]byte, string, error) {
+	select {
+	case p := <-l.inbox:
+		return p.data, p.fromHost, nil
+	case <-l.done:
+		return nil, "", fmt.Errorf("listener for host %s is closed", l.host)
+	}
+}
+
+func (l *listener) RemoteHost() string {
+	return ""
+}
+
+func (l *listener) Write(_ []byte) error {
+	return fmt.Errorf("connection is a listener")
+}
+
+func (l *listener) Close() error {
+	l.closeOnce.Do(func() {
+		l.hub.removeListener(l.host)
+		close(l.done)
+	})
+	return nil
+}
+
+func (l *listener) deliver(p *packet) {
+	select {
+	case <-l.done:
+	case l.inbox <- p:
+	default:
+	}
+}
+
+type dialer struct {
+	hub      *Hub
+	fromHost string
+	toHost   string
+}
+
+func (d *dialer) IsListener() bool {
+	return false
+}
+
+func (d *dialer) Read() ([]byte, error) {
+	return nil, fmt.Errorf("connection is not a listener")
+}
+
+func (d *dialer) ReadFrom() ([]byte, string, error) {
+	return nil, "", fmt.Errorf("connection is not a listener")
+}
+
+func (d *dialer) RemoteHost() string {
+	return d.toHost
+}
+
+func (d *dialer) Write(data []byte) error {
+	return d.hub.send(d.fromHost, d.toHost, data)
+}
+
+func (d *dialer) Close() error {
+	return nil
+}
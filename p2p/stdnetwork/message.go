@@ -0,0 +1,70 @@
+package stdnetwork
+
+import (
+	"fmt"
+	"math/rand"
+
+	types "github.com/pokt-network/pocket/p2p/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"google.golang.org/protobuf/proto"
+)
+
+// newSignedMessage creates a message originating from this node with a signature over its payload so the
+// nodes receiving it, directly or relayed, can authenticate its origin.
+func (n *network) newSignedMessage(data []byte, hopsLeft uint32) (*types.StdNetworkMessage, error) {
+	if n.privateKey == nil {
+		return nil, fmt.Errorf("cannot sign stdnetwork message without a private key")
+	}
+
+	msg := &types.StdNetworkMessage{
+		Data:     data,
+		Nonce:    rand.Uint64(),
+		HopsLeft: hopsLeft,
+		Origin:   n.selfAddr,
+	}
+	signableBz, err := getSignableBytes(msg)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Signature, err = n.privateKey.Sign(signableBz); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// verifyMessage checks that the message was signed by its origin, which must be in the address book.
+func (n *network) verifyMessage(msg *types.StdNetworkMessage) error {
+	origin := cryptoPocket.Address(msg.Origin)
+	peer, ok := n.addrBookMap[origin.String()]
+	if !ok || peer.PublicKey == nil {
+		return fmt.Errorf("%w: unknown origin %s", types.ErrInvalidSignature, origin)
+	}
+
+	signableBz, err := getSignableBytes(msg)
+	if err != nil {
+		return err
+	}
+	if !peer.PublicKey.Verify(signableBz, msg.Signature) {
+		return fmt.Errorf("%w: origin %s", types.ErrInvalidSignature, origin)
+	}
+	return nil
+}
+
+// getSignableBytes returns the bytes signed by the origin of a message. The hops left are excluded because
+// they are decremented by every relay.
+func getSignableBytes(msg *types.StdNetworkMessage) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(&types.StdNetworkMessage{
+		Data:   msg.Data,
+		Nonce:  msg.Nonce,
+		Origin: msg.Origin,
+	})
+}
+
+// marshalMessage serializes the message in the envelope written to peers.
+func marshalMessage(msg *types.StdNetworkMessage) ([]byte, error) {
+	msgBz, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return types.WrapNetworkMessage(types.NetworkType_StdNetwork, msgBz)
+}
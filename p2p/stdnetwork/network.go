@@ -3,37 +3,96 @@
 package stdnetwork
 
 import (
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"log"
+	"math/rand"
+	"sync"
 
 	types "github.com/pokt-network/pocket/p2p/types"
 
 	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
 	"github.com/pokt-network/pocket/shared/modules"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultMaxRelayHops = 1
+	maxSeenMessages     = 10000
 )
 
 var _ types.Network = &network{}
 var _ modules.IntegratableModule = &network{}
 
+// network floods (or gossips, if `relay_fanout` is set) broadcast messages: every node relays the messages it
+// has not seen before to its own peers until they run out of hops.
 type network struct {
+	selfAddr    cryptoPocket.Address
+	privateKey  cryptoPocket.PrivateKey
 	addrBookMap types.AddrBookMap
+	peerScorer  types.PeerScorer
+
+	maxRelayHops uint32
+	relayFanout  int // Messages are relayed to every peer if zero
+
+	seenMu    sync.Mutex
+	seen      map[string]struct{} // The hashes of the messages already handled
+	seenOrder []string
 }
 
-func NewNetwork(addrBook types.AddrBook) (n types.Network) {
+func NewNetwork(selfAddr cryptoPocket.Address, addrBook types.AddrBook, privateKey cryptoPocket.PrivateKey, cfg *types.StdNetworkConfig) (n types.Network) {
 	addrBookMap := make(types.AddrBookMap)
 	for _, peer := range addrBook {
 		addrBookMap[peer.Address.String()] = peer
 	}
+	maxRelayHops := cfg.GetMaxRelayHops()
+	if maxRelayHops == 0 {
+		maxRelayHops = defaultMaxRelayHops
+	}
 	return &network{
-		addrBookMap: addrBookMap,
+		selfAddr:     selfAddr,
+		privateKey:   privateKey,
+		addrBookMap:  addrBookMap,
+		maxRelayHops: maxRelayHops,
+		relayFanout:  int(cfg.GetRelayFanout()),
+		seen:         make(map[string]struct{}),
 	}
 }
 
-// TODO(olshansky): How do we avoid self-broadcasts given that `AddrBook` may contain self in the current p2p implementation?
 func (n *network) NetworkBroadcast(data []byte) error {
+	msg, err := n.newSignedMessage(data, n.maxRelayHops)
+	if err != nil {
+		return err
+	}
+	// Our own message is ignored if a peer relays it back to us
+	n.markSeen(getMessageHash(msg))
+	return n.broadcastToPeers(msg, 0)
+}
+
+// broadcastToPeers sends the message to `fanout` random peers, or all of them if zero, skipping self.
+func (n *network) broadcastToPeers(msg *types.StdNetworkMessage, fanout int) error {
+	msgBz, err := marshalMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	peers := make(types.AddrBook, 0, len(n.addrBookMap))
 	for _, peer := range n.addrBookMap {
-		if err := peer.Dialer.Write(data); err != nil {
+		if n.isSelf(peer.Address) || n.isPeerBanned(peer.Address) {
+			continue
+		}
+		peers = append(peers, peer)
+	}
+	if fanout > 0 && len(peers) > fanout {
+		rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+		peers = peers[:fanout]
+	}
+
+	for _, peer := range peers {
+		if err := peer.Dialer.Write(msgBz); err != nil {
 			log.Println("Error writing to one of the peers during broadcast: ", err)
+			n.recordPeerEvent(peer.Address, types.PeerEventDialFailure)
 			continue
 		}
 	}
@@ -46,16 +105,86 @@ func (n *network) NetworkSend(data []byte, address cryptoPocket.Address) error {
 		return fmt.Errorf("peer with address %v not in addrBookMap", peer)
 	}
 
-	if err := peer.Dialer.Write(data); err != nil {
+	if n.isPeerBanned(address) {
+		return fmt.Errorf("peer %s is temporarily banned", address.String())
+	}
+
+	msg, err := n.newSignedMessage(data, 0)
+	if err != nil {
+		return err
+	}
+	msgBz, err := marshalMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := peer.Dialer.Write(msgBz); err != nil {
 		log.Println("Error writing to peer during send: ", err)
+		n.recordPeerEvent(address, types.PeerEventDialFailure)
 		return err
 	}
 
 	return nil
 }
 
-func (n *network) HandleNetworkData(data []byte) ([]byte, error) {
-	return data, nil // intentional passthrough
+// HandleNetworkData returns the data of the messages that have not been seen before, relaying them to our
+// peers if they have hops left, and nil for the ones that have already been handled.
+func (n *network) HandleNetworkData(data []byte) ([]byte, cryptoPocket.Address, error) {
+	msgBz, err := types.UnwrapNetworkMessage(types.NetworkType_StdNetwork, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	msg := &types.StdNetworkMessage{}
+	if err := proto.Unmarshal(msgBz, msg); err != nil {
+		return nil, nil, err
+	}
+	// Messages are verified before being marked as seen so forged ones cannot prevent the genuine ones from
+	// being handled
+	if err := n.verifyMessage(msg); err != nil {
+		return nil, nil, err
+	}
+
+	if !n.markSeen(getMessageHash(msg)) {
+		return nil, nil, nil
+	}
+
+	if msg.HopsLeft > 0 {
+		relayedMsg := &types.StdNetworkMessage{
+			Data:      msg.Data,
+			Nonce:     msg.Nonce,
+			HopsLeft:  msg.HopsLeft - 1,
+			Origin:    msg.Origin,
+			Signature: msg.Signature,
+		}
+		if err := n.broadcastToPeers(relayedMsg, n.relayFanout); err != nil {
+			log.Println("Error relaying message: ", err)
+		}
+	}
+
+	return msg.Data, msg.Origin, nil
+}
+
+// markSeen returns false if the message was already seen.
+func (n *network) markSeen(msgHash string) bool {
+	n.seenMu.Lock()
+	defer n.seenMu.Unlock()
+	if _, ok := n.seen[msgHash]; ok {
+		return false
+	}
+	n.seen[msgHash] = struct{}{}
+	n.seenOrder = append(n.seenOrder, msgHash)
+	if len(n.seenOrder) > maxSeenMessages {
+		delete(n.seen, n.seenOrder[0])
+		n.seenOrder = n.seenOrder[1:]
+	}
+	return true
+}
+
+// getMessageHash excludes `hops_left` since it is decremented at every hop.
+func getMessageHash(msg *types.StdNetworkMessage) string {
+	bz := make([]byte, 8, 8+len(msg.Data))
+	binary.BigEndian.PutUint64(bz, msg.Nonce)
+	return hex.EncodeToString(cryptoPocket.SHA3Hash(append(bz, msg.Data...)))
 }
 
 func (n *network) GetAddrBook() types.AddrBook {
@@ -76,5 +205,23 @@ func (n *network) RemovePeerToAddrBook(peer *types.NetworkPeer) error {
 	return nil
 }
 
+func (n *network) SetPeerScorer(scorer types.PeerScorer) {
+	n.peerScorer = scorer
+}
+
+func (n *network) isSelf(address cryptoPocket.Address) bool {
+	return n.selfAddr != nil && n.selfAddr.Equals(address)
+}
+
+func (n *network) isPeerBanned(address cryptoPocket.Address) bool {
+	return n.peerScorer != nil && n.peerScorer.IsBanned(address)
+}
+
+func (n *network) recordPeerEvent(address cryptoPocket.Address, event types.PeerEvent) {
+	if n.peerScorer != nil {
+		n.peerScorer.RecordEvent(address, event)
+	}
+}
+
 func (n *network) GetBus() modules.Bus  { return nil }
 func (n *network) SetBus(_ modules.Bus) {}
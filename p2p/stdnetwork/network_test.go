@@ -0,0 +1,154 @@
+package stdnetwork
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	mocksP2P "github.com/pokt-network/pocket/p2p/types/mocks"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStdNetwork_BroadcastExcludesSelf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	self, selfKey := newTestPeer(t, ctrl)
+	peer, _ := newTestPeer(t, ctrl)
+	n := NewNetwork(self.Address, typesP2P.AddrBook{self, peer}, selfKey, nil)
+
+	// The strict mock of the dialer of self fails the test if it is written to
+	var broadcastBz []byte
+	peer.Dialer.(*mocksP2P.MockTransport).EXPECT().Write(gomock.Any()).Do(func(bz []byte) {
+		broadcastBz = bz
+	}).Times(1)
+	require.NoError(t, n.NetworkBroadcast([]byte("data")))
+
+	// Our own message is not handled again if a peer relays it back
+	data, _, err := n.HandleNetworkData(broadcastBz)
+	require.NoError(t, err)
+	require.Nil(t, data)
+}
+
+func TestStdNetwork_RelaysAndDeduplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	self, selfKey := newTestPeer(t, ctrl)
+	peer1, peer1Key := newTestPeer(t, ctrl)
+	peer2, _ := newTestPeer(t, ctrl)
+	n := NewNetwork(self.Address, typesP2P.AddrBook{self, peer1, peer2}, selfKey, &typesP2P.StdNetworkConfig{MaxRelayHops: 2})
+
+	relayed := make([]*typesP2P.StdNetworkMessage, 0)
+	for _, peer := range []*typesP2P.NetworkPeer{peer1, peer2} {
+		peer.Dialer.(*mocksP2P.MockTransport).EXPECT().Write(gomock.Any()).Do(func(bz []byte) {
+			relayed = append(relayed, unmarshalTestMessage(t, bz))
+		}).Times(1)
+	}
+
+	msgBz := newTestMessage(t, peer1Key, &typesP2P.StdNetworkMessage{Data: []byte("data"), Nonce: 1, HopsLeft: 1})
+	data, origin, err := n.HandleNetworkData(msgBz)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), data)
+	require.Equal(t, peer1.Address, origin)
+	require.Len(t, relayed, 2)
+	for _, msg := range relayed {
+		require.Equal(t, uint32(0), msg.HopsLeft)
+		require.Equal(t, uint64(1), msg.Nonce)
+		require.Equal(t, []byte(peer1.Address), msg.Origin, "the origin and its signature are relayed")
+	}
+
+	// The same message relayed by another peer is neither handled nor relayed again, even with a different hop count
+	data, _, err = n.HandleNetworkData(newTestMessage(t, peer1Key, &typesP2P.StdNetworkMessage{Data: []byte("data"), Nonce: 1}))
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	// The same payload with a different nonce is a different message; it is not relayed since it has no hops left
+	data, _, err = n.HandleNetworkData(newTestMessage(t, peer1Key, &typesP2P.StdNetworkMessage{Data: []byte("data"), Nonce: 2}))
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), data)
+}
+
+func TestStdNetwork_RejectsRainTreeMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	self, selfKey := newTestPeer(t, ctrl)
+	peer, peerKey := newTestPeer(t, ctrl)
+	n := NewNetwork(self.Address, typesP2P.AddrBook{self, peer}, selfKey, nil)
+
+	// Even a validly signed message is rejected, without verifying its signature, if it was sent with RainTree
+	stdNetworkMsg := unmarshalTestMessage(t, newTestMessage(t, peerKey, &typesP2P.StdNetworkMessage{Data: []byte("data"), Nonce: 1}))
+	stdNetworkMsgBz, err := proto.Marshal(stdNetworkMsg)
+	require.NoError(t, err)
+	rainTreeMsgBz, err := typesP2P.WrapNetworkMessage(typesP2P.NetworkType_RainTreeNetwork, stdNetworkMsgBz)
+	require.NoError(t, err)
+	_, _, err = n.HandleNetworkData(rainTreeMsgBz)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, typesP2P.ErrInvalidSignature)
+}
+
+func TestStdNetwork_RejectsUnauthenticatedMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	self, selfKey := newTestPeer(t, ctrl)
+	peer, peerKey := newTestPeer(t, ctrl)
+	_, unknownKey := newTestPeer(t, ctrl)
+	n := NewNetwork(self.Address, typesP2P.AddrBook{self, peer}, selfKey, nil)
+
+	// Unsigned, forged by a node claiming to be a peer, from an unknown node and tampered with
+	msg := &typesP2P.StdNetworkMessage{Data: []byte("data"), Nonce: 1, Origin: peer.Address}
+	forgedMsg := unmarshalTestMessage(t, newTestMessage(t, unknownKey, msg))
+	forgedMsg.Origin = peer.Address
+	tamperedMsg := unmarshalTestMessage(t, newTestMessage(t, peerKey, msg))
+	tamperedMsg.Data = []byte("tampered")
+
+	for _, msgBz := range [][]byte{
+		marshalTestMessage(t, msg),
+		marshalTestMessage(t, forgedMsg),
+		newTestMessage(t, unknownKey, msg),
+		marshalTestMessage(t, tamperedMsg),
+	} {
+		_, _, err := n.HandleNetworkData(msgBz)
+		require.ErrorIs(t, err, typesP2P.ErrInvalidSignature)
+	}
+
+	// The rejected messages are not marked as seen, so the genuine one is still handled
+	data, _, err := n.HandleNetworkData(newTestMessage(t, peerKey, msg))
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), data)
+}
+
+func newTestPeer(t *testing.T, ctrl *gomock.Controller) (*typesP2P.NetworkPeer, cryptoPocket.PrivateKey) {
+	privateKey, err := cryptoPocket.GeneratePrivateKey()
+	require.NoError(t, err)
+	return &typesP2P.NetworkPeer{
+		Address:   privateKey.Address(),
+		PublicKey: privateKey.PublicKey(),
+		Dialer:    mocksP2P.NewMockTransport(ctrl),
+	}, privateKey
+}
+
+// newTestMessage returns a copy of the message signed by `origin`.
+func newTestMessage(t *testing.T, origin cryptoPocket.PrivateKey, msg *typesP2P.StdNetworkMessage) []byte {
+	signedMsg := &typesP2P.StdNetworkMessage{
+		Data:     msg.Data,
+		Nonce:    msg.Nonce,
+		HopsLeft: msg.HopsLeft,
+		Origin:   origin.Address(),
+	}
+	signableBz, err := getSignableBytes(signedMsg)
+	require.NoError(t, err)
+	signedMsg.Signature, err = origin.Sign(signableBz)
+	require.NoError(t, err)
+	return marshalTestMessage(t, signedMsg)
+}
+
+func marshalTestMessage(t *testing.T, msg *typesP2P.StdNetworkMessage) []byte {
+	bz, err := marshalMessage(msg)
+	require.NoError(t, err)
+	return bz
+}
+
+func unmarshalTestMessage(t *testing.T, bz []byte) *typesP2P.StdNetworkMessage {
+	msgBz, err := typesP2P.UnwrapNetworkMessage(typesP2P.NetworkType_StdNetwork, bz)
+	require.NoError(t, err)
+	msg := &typesP2P.StdNetworkMessage{}
+	require.NoError(t, proto.Unmarshal(msgBz, msg))
+	return msg
+}
@@ -2,59 +2,165 @@ package p2p
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
+	"sync"
 
 	typesP2P "github.com/pokt-network/pocket/p2p/types"
 	"github.com/pokt-network/pocket/shared/modules"
 )
 
 const (
-	TCPNetworkLayerProtocol = "tcp4"
+	// Using "tcp" rather than "tcp4" or "tcp6" lets the listeners and dialers work with both IPv4 and IPv6
+	TCPNetworkLayerProtocol = "tcp"
 )
 
+// ListenerFactory creates the listener of a transport, i.e. the connection used to read data from peers.
+type ListenerFactory func(cfg modules.P2PConfig) (typesP2P.Transport, error)
+
+// DialerFactory creates the dialer of a transport, i.e. the connection used to write data to the peer at `url`.
+type DialerFactory func(cfg modules.P2PConfig, url string) (typesP2P.Transport, error)
+
+type transportFactories struct {
+	createListener ListenerFactory
+	createDialer   DialerFactory
+}
+
+var (
+	transportsMu sync.RWMutex
+	transports   = map[typesP2P.ConnectionType]transportFactories{
+		typesP2P.ConnectionType_EmptyConnection: {createEmptyListener, createEmptyDialer},
+		typesP2P.ConnectionType_TCPConnection:   {createTCPTransportListener, createTCPTransportDialer},
+		typesP2P.ConnectionType_QUICConnection:  {createQUICTransportListener, createQUICTransportDialer},
+	}
+)
+
+// RegisterTransport makes a new transport available to the p2p module when `connection_type` is set to `connType`.
+func RegisterTransport(connType typesP2P.ConnectionType, createListener ListenerFactory, createDialer DialerFactory) error {
+	if connType == typesP2P.ConnectionType_UnspecifiedConnection {
+		return fmt.Errorf("cannot register a transport for an unspecified connection type")
+	}
+	if createListener == nil || createDialer == nil {
+		return fmt.Errorf("cannot register a transport for %s without a listener and a dialer", connType)
+	}
+
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	if _, ok := transports[connType]; ok {
+		return fmt.Errorf("a transport is already registered for %s", connType)
+	}
+	transports[connType] = transportFactories{createListener, createDialer}
+	return nil
+}
+
 func CreateListener(cfg modules.P2PConfig) (typesP2P.Transport, error) {
-	switch cfg.IsEmptyConnType() { // TECHDEBT kept in switch format because this should be an enum not a bool
-	case true:
-		return createEmptyListener(cfg)
-	case false:
-		return createTCPListener(cfg)
-	default:
-		return nil, fmt.Errorf("unsupported connection type for listener: %v", cfg.IsEmptyConnType())
+	factories, err := getTransportFactories(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported connection type for listener: %w", err)
 	}
+	return factories.createListener(cfg)
 }
 
 func CreateDialer(cfg modules.P2PConfig, url string) (typesP2P.Transport, error) {
-	switch cfg.IsEmptyConnType() {
-	case true:
-		return createEmptyDialer(cfg, url)
-	case false:
-		return createTCPDialer(cfg, url)
-	default:
-		return nil, fmt.Errorf("unsupported connection type for dialer: %v", cfg.IsEmptyConnType())
+	factories, err := getTransportFactories(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported connection type for dialer: %w", err)
+	}
+	return factories.createDialer(cfg, url)
+}
+
+func getTransportFactories(cfg modules.P2PConfig) (transportFactories, error) {
+	connType := getConnectionType(cfg)
+
+	transportsMu.RLock()
+	defer transportsMu.RUnlock()
+
+	factories, ok := transports[connType]
+	if !ok {
+		return transportFactories{}, fmt.Errorf("no transport registered for %s", connType)
+	}
+	return factories, nil
+}
+
+// getConnectionType returns the connection type set in the config, falling back to the deprecated
+// `is_empty_connection_type` flag when it is unspecified.
+// TECHDEBT: `modules.P2PConfig` cannot expose the `ConnectionType` enum since it lives in the p2p module.
+func getConnectionType(cfg modules.P2PConfig) typesP2P.ConnectionType {
+	if c, ok := cfg.(interface {
+		GetConnectionType() typesP2P.ConnectionType
+	}); ok && c.GetConnectionType() != typesP2P.ConnectionType_UnspecifiedConnection {
+		return c.GetConnectionType()
+	}
+	if cfg.IsEmptyConnType() {
+		return typesP2P.ConnectionType_EmptyConnection
 	}
+	return typesP2P.ConnectionType_TCPConnection
 }
 
 var _ typesP2P.Transport = &tcpConn{}
+var _ typesP2P.PeerAwareTransport = &tcpConn{}
 
 type tcpConn struct {
-	address  *net.TCPAddr
-	listener *net.TCPListener
+	address *net.TCPAddr // only applicable to dialers
+
+	// Only applicable to listeners. A single connection can listen on several addresses (e.g. an IPv4
+	// and an IPv6 interface), in which case the accepted connections of all of them are read in order.
+	listeners      []*net.TCPListener
+	accepted       chan acceptResult
+	closed         chan struct{}
+	closeOnce      sync.Once
+	maxMessageSize uint64
+}
+
+type acceptResult struct {
+	conn net.Conn
+	err  error
 }
 
 func createTCPListener(cfg modules.P2PConfig) (*tcpConn, error) {
-	addr, err := net.ResolveTCPAddr(TCPNetworkLayerProtocol, fmt.Sprintf(":%d", cfg.GetConsensusPort()))
-	if err != nil {
-		return nil, err
+	listenAddresses := getListenAddresses(cfg)
+	c := &tcpConn{
+		listeners:      make([]*net.TCPListener, 0, len(listenAddresses)),
+		accepted:       make(chan acceptResult),
+		closed:         make(chan struct{}),
+		maxMessageSize: getMaxMessageSizeBytes(cfg),
 	}
-	l, err := net.ListenTCP(TCPNetworkLayerProtocol, addr)
-	if err != nil {
-		return nil, err
+	for _, listenAddress := range listenAddresses {
+		addr, err := net.ResolveTCPAddr(TCPNetworkLayerProtocol, listenAddress)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("error resolving listen address %s: %w", listenAddress, err)
+		}
+		l, err := net.ListenTCP(TCPNetworkLayerProtocol, addr)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("error listening on %s: %w", listenAddress, err)
+		}
+		c.listeners = append(c.listeners, l)
 	}
-	return &tcpConn{
-		address:  addr,
-		listener: l,
-	}, nil
+	for _, l := range c.listeners {
+		go c.acceptLoop(l)
+	}
+	return c, nil
+}
+
+// getListenAddresses returns the addresses the node listens on, defaulting to all the (IPv4 and IPv6)
+// interfaces on the consensus port.
+func getListenAddresses(cfg modules.P2PConfig) []string {
+	if listenAddresses := cfg.GetListenAddresses(); len(listenAddresses) > 0 {
+		return listenAddresses
+	}
+	return []string{fmt.Sprintf(":%d", cfg.GetConsensusPort())}
+}
+
+func createTCPTransportListener(cfg modules.P2PConfig) (typesP2P.Transport, error) {
+	return createTCPListener(cfg)
+}
+
+func createTCPTransportDialer(cfg modules.P2PConfig, url string) (typesP2P.Transport, error) {
+	return createTCPDialer(cfg, url)
 }
 
 func createTCPDialer(_ modules.P2PConfig, url string) (*tcpConn, error) {
@@ -67,26 +173,78 @@ func createTCPDialer(_ modules.P2PConfig, url string) (*tcpConn, error) {
 	}, nil
 }
 
+func (c *tcpConn) acceptLoop(l *net.TCPListener) {
+	for {
+		conn, err := l.Accept()
+		select {
+		case c.accepted <- acceptResult{conn, err}:
+		case <-c.closed:
+			if conn != nil {
+				conn.Close()
+			}
+			return
+		}
+	}
+}
+
 func (c *tcpConn) IsListener() bool {
-	return c.listener != nil
+	return c.listeners != nil
+}
+
+// ListenAddrs returns the addresses the listener is bound to (e.g. to find out the port picked by the
+// OS when listening on port 0).
+func (c *tcpConn) ListenAddrs() []net.Addr {
+	addrs := make([]net.Addr, 0, len(c.listeners))
+	for _, l := range c.listeners {
+		addrs = append(addrs, l.Addr())
+	}
+	return addrs
 }
 
 func (c *tcpConn) Read() ([]byte, error) {
+	data, _, err := c.ReadFrom()
+	return data, err
+}
+
+func (c *tcpConn) ReadFrom() ([]byte, string, error) {
 	if !c.IsListener() {
-		return nil, fmt.Errorf("connection is not a listener")
+		return nil, "", fmt.Errorf("connection is not a listener")
 	}
-	conn, err := c.listener.Accept()
-	if err != nil {
-		return nil, fmt.Errorf("error accepting connection: %v", err)
+
+	var result acceptResult
+	select {
+	case result = <-c.accepted:
+	case <-c.closed:
+		return nil, "", fmt.Errorf("listener is closed")
+	}
+	if result.err != nil {
+		return nil, "", fmt.Errorf("error accepting connection: %v", result.err)
 	}
+	conn := result.conn
 	defer conn.Close()
 
-	data, err := ioutil.ReadAll(conn)
+	var remoteHost string
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		remoteHost = addr.IP.String()
+	}
+
+	// Reading one byte past the limit is enough to know the message is too large without buffering all of it
+	data, err := ioutil.ReadAll(io.LimitReader(conn, int64(c.maxMessageSize)+1))
 	if err != nil {
-		return nil, fmt.Errorf("error reading from conn: %v", err)
+		return nil, remoteHost, fmt.Errorf("error reading from conn: %v", err)
 	}
+	if uint64(len(data)) > c.maxMessageSize {
+		return nil, remoteHost, fmt.Errorf("message from %s exceeds the max message size of %d bytes", remoteHost, c.maxMessageSize)
+	}
+
+	return data, remoteHost, nil
+}
 
-	return data, nil
+func (c *tcpConn) RemoteHost() string {
+	if c.IsListener() || c.address == nil {
+		return ""
+	}
+	return c.address.IP.String()
 }
 
 func (c *tcpConn) Write(data []byte) error {
@@ -108,10 +266,19 @@ func (c *tcpConn) Write(data []byte) error {
 }
 
 func (c *tcpConn) Close() error {
-	if c.IsListener() {
-		return c.listener.Close()
+	if !c.IsListener() {
+		return nil
 	}
-	return nil
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		for _, l := range c.listeners {
+			if closeErr := l.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
 }
 
 var _ typesP2P.Transport = &emptyConn{}
@@ -0,0 +1,317 @@
+package p2p
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	"github.com/pokt-network/pocket/shared/modules"
+	"github.com/quic-go/quic-go"
+)
+
+const (
+	QUICNetworkLayerProtocol = "udp"
+	quicALPN                 = "pocket-p2p"
+	quicKeepAlivePeriod      = 15 * time.Second
+	// The time a write can take, including dialing the peer and opening a stream, so a peer that does not
+	// respond cannot block the writes to it indefinitely
+	quicWriteTimeout = 10 * time.Second
+	// The time a message can take to be read once its stream is accepted, since messages are read one at a time
+	quicReadTimeout = 10 * time.Second
+	// The streams a peer can open on a connection before the ones already opened are read. The other streams
+	// are flow controlled by QUIC until then.
+	quicMaxIncomingUniStreams = 16
+)
+
+var _ typesP2P.Transport = &quicConn{}
+var _ typesP2P.PeerAwareTransport = &quicConn{}
+
+// quicConn implements the `Transport` interface on top of QUIC. Every message is sent on its own
+// unidirectional stream of a single (encrypted) connection per peer, so a lost packet only delays the
+// message it belongs to rather than every message queued behind it as is the case with TCP.
+type quicConn struct {
+	// Only applicable to dialers
+	address *net.UDPAddr
+	url     string
+	connMu  sync.Mutex
+	conn    quic.Connection
+
+	// Only applicable to listeners
+	listeners      []*quic.Listener
+	received       chan quicStream
+	closed         chan struct{}
+	closeOnce      sync.Once
+	maxMessageSize uint64
+
+	tlsConfig *tls.Config
+}
+
+// quicStream is a stream opened by a peer, which is only read when the listener is read from so the messages
+// are read at the pace of the inbound path of the module rather than as soon as they are received.
+type quicStream struct {
+	stream     quic.ReceiveStream
+	remoteHost string
+	err        error
+}
+
+func createQUICTransportListener(cfg modules.P2PConfig) (typesP2P.Transport, error) {
+	return createQUICListener(cfg)
+}
+
+func createQUICTransportDialer(cfg modules.P2PConfig, url string) (typesP2P.Transport, error) {
+	return createQUICDialer(cfg, url)
+}
+
+func createQUICListener(cfg modules.P2PConfig) (*quicConn, error) {
+	tlsConfig, err := newQUICTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	listenAddresses := getListenAddresses(cfg)
+	c := &quicConn{
+		listeners:      make([]*quic.Listener, 0, len(listenAddresses)),
+		received:       make(chan quicStream),
+		closed:         make(chan struct{}),
+		maxMessageSize: getMaxMessageSizeBytes(cfg),
+		tlsConfig:      tlsConfig,
+	}
+	for _, listenAddress := range listenAddresses {
+		l, err := quic.ListenAddr(listenAddress, tlsConfig, newQUICConfig())
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("error listening on %s: %w", listenAddress, err)
+		}
+		c.listeners = append(c.listeners, l)
+	}
+	for _, l := range c.listeners {
+		go c.acceptLoop(l)
+	}
+	return c, nil
+}
+
+func createQUICDialer(_ modules.P2PConfig, url string) (*quicConn, error) {
+	addr, err := net.ResolveUDPAddr(QUICNetworkLayerProtocol, url)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := newQUICTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &quicConn{
+		address:   addr,
+		url:       url,
+		tlsConfig: tlsConfig,
+	}, nil
+}
+
+// newQUICTLSConfig returns the TLS config of a QUIC connection using an ephemeral self-signed certificate.
+// QUIC mandates TLS, so the traffic between peers is always encrypted, but the certificates are not used
+// to authenticate peers; that is done at the application layer (e.g. RainTree message signatures).
+// TECHDEBT: Derive the certificate from the node's key so peers can be authenticated during the handshake.
+func newQUICTLSConfig() (*tls.Config, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(10 * 365 * 24 * time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{certDER},
+			PrivateKey:  privateKey,
+		}},
+		InsecureSkipVerify: true, // See the TECHDEBT above
+		NextProtos:         []string{quicALPN},
+		MinVersion:         tls.VersionTLS13,
+	}, nil
+}
+
+func newQUICConfig() *quic.Config {
+	return &quic.Config{
+		KeepAlivePeriod:       quicKeepAlivePeriod,
+		MaxIncomingUniStreams: quicMaxIncomingUniStreams,
+	}
+}
+
+func (c *quicConn) acceptLoop(l *quic.Listener) {
+	for {
+		conn, err := l.Accept(context.Background())
+		if err != nil {
+			select {
+			case <-c.closed:
+				return
+			case c.received <- quicStream{err: fmt.Errorf("error accepting connection: %v", err)}:
+				continue
+			}
+		}
+		go c.acceptStreams(conn)
+	}
+}
+
+// acceptStreams hands every stream opened by the peer on `conn` over to the listener, to be read as a separate
+// message, until either the connection or the listener is closed.
+func (c *quicConn) acceptStreams(conn quic.Connection) {
+	var remoteHost string
+	if addr, ok := conn.RemoteAddr().(*net.UDPAddr); ok {
+		remoteHost = addr.IP.String()
+	}
+	for {
+		stream, err := conn.AcceptUniStream(context.Background())
+		if err != nil {
+			return // The connection was closed by the peer or timed out
+		}
+		select {
+		case c.received <- quicStream{stream: stream, remoteHost: remoteHost}:
+		case <-c.closed:
+			stream.CancelRead(0)
+			return
+		}
+	}
+}
+
+func (c *quicConn) readStream(stream quic.ReceiveStream, remoteHost string) ([]byte, error) {
+	if err := stream.SetReadDeadline(time.Now().Add(quicReadTimeout)); err != nil {
+		return nil, err
+	}
+	// Reading one byte past the limit is enough to know the message is too large without buffering all of it
+	data, err := io.ReadAll(io.LimitReader(stream, int64(c.maxMessageSize)+1))
+	if err != nil {
+		stream.CancelRead(0)
+		return nil, fmt.Errorf("error reading from stream: %v", err)
+	}
+	if uint64(len(data)) > c.maxMessageSize {
+		stream.CancelRead(0)
+		return nil, fmt.Errorf("message from %s exceeds the max message size of %d bytes", remoteHost, c.maxMessageSize)
+	}
+	return data, nil
+}
+
+func (c *quicConn) IsListener() bool {
+	return c.listeners != nil
+}
+
+// ListenAddrs returns the addresses the listener is bound to (e.g. to find out the port picked by the
+// OS when listening on port 0).
+func (c *quicConn) ListenAddrs() []net.Addr {
+	addrs := make([]net.Addr, 0, len(c.listeners))
+	for _, l := range c.listeners {
+		addrs = append(addrs, l.Addr())
+	}
+	return addrs
+}
+
+func (c *quicConn) Read() ([]byte, error) {
+	data, _, err := c.ReadFrom()
+	return data, err
+}
+
+func (c *quicConn) ReadFrom() ([]byte, string, error) {
+	if !c.IsListener() {
+		return nil, "", fmt.Errorf("connection is not a listener")
+	}
+	select {
+	case s := <-c.received:
+		if s.err != nil {
+			return nil, s.remoteHost, s.err
+		}
+		data, err := c.readStream(s.stream, s.remoteHost)
+		return data, s.remoteHost, err
+	case <-c.closed:
+		return nil, "", fmt.Errorf("listener is closed")
+	}
+}
+
+func (c *quicConn) RemoteHost() string {
+	if c.IsListener() || c.address == nil {
+		return ""
+	}
+	return c.address.IP.String()
+}
+
+func (c *quicConn) Write(data []byte) error {
+	if c.IsListener() {
+		return fmt.Errorf("connection is a listener")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), quicWriteTimeout)
+	defer cancel()
+
+	conn, err := c.getConnection(ctx)
+	if err != nil {
+		return err
+	}
+	stream, err := conn.OpenUniStreamSync(ctx)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err = stream.SetWriteDeadline(deadline); err != nil {
+		stream.CancelWrite(0)
+		return err
+	}
+	if _, err = stream.Write(data); err != nil {
+		stream.CancelWrite(0)
+		return err
+	}
+	return stream.Close()
+}
+
+// getConnection returns the connection to the peer, dialing it if there is none or the previous one was closed.
+func (c *quicConn) getConnection(ctx context.Context) (quic.Connection, error) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	if c.conn != nil {
+		select {
+		case <-c.conn.Context().Done():
+		default:
+			return c.conn, nil
+		}
+	}
+	conn, err := quic.DialAddr(ctx, c.url, c.tlsConfig, newQUICConfig())
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	return conn, nil
+}
+
+func (c *quicConn) Close() error {
+	if !c.IsListener() {
+		c.connMu.Lock()
+		defer c.connMu.Unlock()
+		if c.conn == nil {
+			return nil
+		}
+		err := c.conn.CloseWithError(0, "")
+		c.conn = nil
+		return err
+	}
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		for _, l := range c.listeners {
+			if closeErr := l.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
+}
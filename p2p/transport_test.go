@@ -0,0 +1,211 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	typesP2P "github.com/pokt-network/pocket/p2p/types"
+	"github.com/pokt-network/pocket/shared/modules"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTCPTransport_MultipleListenAddresses(t *testing.T) {
+	listenAddresses := []string{"127.0.0.1:0"}
+	if ln, err := net.Listen(TCPNetworkLayerProtocol, "[::1]:0"); err == nil {
+		ln.Close()
+		listenAddresses = append(listenAddresses, "[::1]:0")
+	} else {
+		t.Log("IPv6 loopback is unavailable, only testing IPv4")
+	}
+
+	listener, err := createTCPListener(&typesP2P.P2PConfig{ListenAddresses: listenAddresses})
+	require.NoError(t, err)
+	defer listener.Close()
+
+	listenAddrs := listener.ListenAddrs()
+	require.Len(t, listenAddrs, len(listenAddresses))
+
+	for _, listenAddr := range listenAddrs {
+		dialer, err := createTCPDialer(nil, listenAddr.String())
+		require.NoError(t, err)
+		require.NoError(t, dialer.Write([]byte(listenAddr.String())))
+
+		data, remoteHost, err := listener.ReadFrom()
+		require.NoError(t, err)
+		require.Equal(t, listenAddr.String(), string(data))
+		require.Equal(t, listenAddr.(*net.TCPAddr).IP.String(), remoteHost)
+		require.Equal(t, dialer.RemoteHost(), remoteHost)
+	}
+}
+
+func TestTCPTransport_DefaultListenAddress(t *testing.T) {
+	require.Equal(t, []string{":8080"}, getListenAddresses(&typesP2P.P2PConfig{ConsensusPort: 8080}))
+	require.Equal(t, []string{"[::]:9090"}, getListenAddresses(&typesP2P.P2PConfig{
+		ConsensusPort:   8080,
+		ListenAddresses: []string{"[::]:9090"},
+	}))
+}
+
+func TestTCPTransport_InvalidListenAddress(t *testing.T) {
+	_, err := createTCPListener(&typesP2P.P2PConfig{ListenAddresses: []string{"127.0.0.1:0", "not an address"}})
+	require.Error(t, err)
+}
+
+func TestTCPTransport_CloseUnblocksRead(t *testing.T) {
+	listener, err := createTCPListener(&typesP2P.P2PConfig{ListenAddresses: []string{"127.0.0.1:0"}})
+	require.NoError(t, err)
+
+	readErr := make(chan error)
+	go func() {
+		_, _, err := listener.ReadFrom()
+		readErr <- err
+	}()
+
+	require.NoError(t, listener.Close())
+	require.Error(t, <-readErr)
+	require.NoError(t, listener.Close(), "closing twice should be a no-op")
+}
+
+func TestTransportRegistry_ConnectionType(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      *typesP2P.P2PConfig
+		expected typesP2P.ConnectionType
+	}{
+		{"defaults to tcp", &typesP2P.P2PConfig{}, typesP2P.ConnectionType_TCPConnection},
+		{"deprecated empty connection flag", &typesP2P.P2PConfig{IsEmptyConnectionType: true}, typesP2P.ConnectionType_EmptyConnection},
+		{"connection type takes precedence", &typesP2P.P2PConfig{IsEmptyConnectionType: true, ConnectionType: typesP2P.ConnectionType_QUICConnection}, typesP2P.ConnectionType_QUICConnection},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, getConnectionType(tc.cfg))
+		})
+	}
+}
+
+func TestTransportRegistry_RegisterTransport(t *testing.T) {
+	connType := typesP2P.ConnectionType(100)
+	createListener := func(modules.P2PConfig) (typesP2P.Transport, error) { return &emptyConn{}, nil }
+	createDialer := func(modules.P2PConfig, string) (typesP2P.Transport, error) { return &emptyConn{}, nil }
+
+	cfg := &typesP2P.P2PConfig{ConnectionType: connType}
+	_, err := CreateListener(cfg)
+	require.Error(t, err, "no transport is registered yet")
+
+	require.NoError(t, RegisterTransport(connType, createListener, createDialer))
+	t.Cleanup(func() {
+		transportsMu.Lock()
+		defer transportsMu.Unlock()
+		delete(transports, connType)
+	})
+
+	listener, err := CreateListener(cfg)
+	require.NoError(t, err)
+	require.IsType(t, &emptyConn{}, listener)
+	dialer, err := CreateDialer(cfg, "127.0.0.1:8080")
+	require.NoError(t, err)
+	require.IsType(t, &emptyConn{}, dialer)
+
+	require.Error(t, RegisterTransport(connType, createListener, createDialer), "duplicate transport")
+	require.Error(t, RegisterTransport(typesP2P.ConnectionType_TCPConnection, createListener, createDialer), "built-in transport")
+	require.Error(t, RegisterTransport(typesP2P.ConnectionType_UnspecifiedConnection, createListener, createDialer))
+	require.Error(t, RegisterTransport(typesP2P.ConnectionType(101), nil, createDialer))
+}
+
+func TestQUICTransport_ReadWrite(t *testing.T) {
+	cfg := &typesP2P.P2PConfig{
+		ConnectionType:  typesP2P.ConnectionType_QUICConnection,
+		ListenAddresses: []string{"127.0.0.1:0"},
+	}
+	listener, err := CreateListener(cfg)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	listenAddr := listener.(*quicConn).ListenAddrs()[0]
+	dialer, err := CreateDialer(cfg, listenAddr.String())
+	require.NoError(t, err)
+	defer dialer.Close()
+
+	// The messages are written on separate streams of the same connection, so they can be read in any order
+	numMessages := 10
+	expected := make([]string, 0, numMessages)
+	for i := 0; i < numMessages; i++ {
+		msg := fmt.Sprintf("message %d", i)
+		expected = append(expected, msg)
+		require.NoError(t, dialer.Write([]byte(msg)))
+	}
+
+	received := make([]string, 0, numMessages)
+	for i := 0; i < numMessages; i++ {
+		data, remoteHost, err := listener.(*quicConn).ReadFrom()
+		require.NoError(t, err)
+		require.Equal(t, "127.0.0.1", remoteHost)
+		received = append(received, string(data))
+	}
+	require.ElementsMatch(t, expected, received)
+}
+
+func TestQUICTransport_MaxMessageSize(t *testing.T) {
+	cfg := &typesP2P.P2PConfig{
+		ConnectionType:      typesP2P.ConnectionType_QUICConnection,
+		ListenAddresses:     []string{"127.0.0.1:0"},
+		MaxMessageSizeBytes: 8,
+	}
+	listener, err := createQUICListener(cfg)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	dialer, err := createQUICDialer(cfg, listener.ListenAddrs()[0].String())
+	require.NoError(t, err)
+	defer dialer.Close()
+
+	require.NoError(t, dialer.Write([]byte("too large message")))
+	_, _, err = listener.ReadFrom()
+	require.Error(t, err)
+
+	require.NoError(t, dialer.Write([]byte("small")))
+	data, _, err := listener.ReadFrom()
+	require.NoError(t, err)
+	require.Equal(t, []byte("small"), data)
+}
+
+func TestQUICTransport_MaxIncomingStreams(t *testing.T) {
+	cfg := &typesP2P.P2PConfig{
+		ConnectionType:  typesP2P.ConnectionType_QUICConnection,
+		ListenAddresses: []string{"127.0.0.1:0"},
+	}
+	listener, err := createQUICListener(cfg)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	dialer, err := createQUICDialer(cfg, listener.ListenAddrs()[0].String())
+	require.NoError(t, err)
+	defer dialer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), quicWriteTimeout)
+	defer cancel()
+	conn, err := dialer.getConnection(ctx)
+	require.NoError(t, err)
+
+	// The streams are not read until the listener is, so a peer cannot open more than the limit
+	for i := 0; i < quicMaxIncomingUniStreams; i++ {
+		stream, err := conn.OpenUniStream()
+		require.NoError(t, err)
+		_, err = stream.Write([]byte(fmt.Sprintf("message %d", i)))
+		require.NoError(t, err)
+		require.NoError(t, stream.Close())
+	}
+	_, err = conn.OpenUniStream()
+	require.Error(t, err)
+
+	// Reading a message lets the peer open another stream
+	_, _, err = listener.ReadFrom()
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, err := conn.OpenUniStream()
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+}
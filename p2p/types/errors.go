@@ -0,0 +1,7 @@
+package types
+
+import "errors"
+
+// ErrInvalidSignature is returned (wrapped) by `Network.HandleNetworkData` when the origin of a message
+// cannot be authenticated, so the caller can tell forged or tampered messages apart from malformed ones.
+var ErrInvalidSignature = errors.New("invalid message signature")
@@ -20,8 +20,12 @@ type Network interface {
 	AddPeerToAddrBook(peer *NetworkPeer) error
 	RemovePeerToAddrBook(peer *NetworkPeer) error
 
+	// Peer quality helpers; a nil scorer disables scoring
+	SetPeerScorer(scorer PeerScorer)
+
 	// This function was added to specifically support the RainTree implementation.
 	// Handles the raw data received from the network and returns the data to be processed
-	// by the application layer.
-	HandleNetworkData(data []byte) ([]byte, error)
+	// by the application layer, along with the address of the node that originated it as
+	// verified from its signature.
+	HandleNetworkData(data []byte) (appMsgData []byte, origin cryptoPocket.Address, err error)
 }
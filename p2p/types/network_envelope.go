@@ -0,0 +1,37 @@
+package types
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// WrapNetworkMessage wraps the serialized message of `network` in the envelope written to peers.
+func WrapNetworkMessage(network NetworkType, msgBz []byte) ([]byte, error) {
+	return proto.Marshal(&NetworkEnvelope{
+		Network: network,
+		Data:    msgBz,
+	})
+}
+
+// UnwrapNetworkMessage returns the serialized message in the envelope, failing if it was sent with a network other
+// than `network`.
+func UnwrapNetworkMessage(network NetworkType, data []byte) ([]byte, error) {
+	envelope := &NetworkEnvelope{}
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Network != network {
+		return nil, fmt.Errorf("expected a %s message but got a %s one", network, envelope.Network)
+	}
+	return envelope.Data, nil
+}
+
+// GetNetworkType returns the network the message in the envelope was sent with.
+func GetNetworkType(data []byte) (NetworkType, error) {
+	envelope := &NetworkEnvelope{}
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return NetworkType_UnknownNetwork, err
+	}
+	return envelope.Network, nil
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+)
+
+// PeerEvent is an observation about the behaviour of a peer that affects its score.
+type PeerEvent uint8
+
+const (
+	PeerEventDialFailure      PeerEvent = iota // Writing to the peer failed
+	PeerEventInvalidMessage                    // The peer sent data that could not be decoded
+	PeerEventInvalidSignature                  // The peer sent (or relayed) a message with an invalid signature
+	PeerEventRateLimited                       // The peer exceeded its inbound rate limit
+	PeerEventUsefulMessage                     // The peer sent a valid message that was forwarded to the application
+)
+
+func (e PeerEvent) String() string {
+	switch e {
+	case PeerEventDialFailure:
+		return "dial_failure"
+	case PeerEventInvalidMessage:
+		return "invalid_message"
+	case PeerEventInvalidSignature:
+		return "invalid_signature"
+	case PeerEventRateLimited:
+		return "rate_limited"
+	case PeerEventUsefulMessage:
+		return "useful_message"
+	default:
+		return "unknown"
+	}
+}
+
+// PeerScorer tracks the quality of peers so misbehaving ones can be temporarily banned or deprioritized.
+type PeerScorer interface {
+	// RecordEvent updates the score of the peer with the given address.
+	RecordEvent(address cryptoPocket.Address, event PeerEvent)
+	// GetScore returns the current score of the peer; 0 is neutral and negative scores are worse.
+	GetScore(address cryptoPocket.Address) float64
+	// IsBanned returns true if the peer is currently banned and should not be sent to or read from.
+	IsBanned(address cryptoPocket.Address) bool
+}
@@ -8,3 +8,13 @@ type Transport interface {
 	Write([]byte) error
 	Close() error
 }
+
+// PeerAwareTransport is optionally implemented by transports that know the remote host on the other
+// end of the connection. It is used to attribute inbound data to the peer that sent it.
+type PeerAwareTransport interface {
+	Transport
+	// ReadFrom is the same as `Read` but also returns the host the data was received from.
+	ReadFrom() (data []byte, remoteHost string, err error)
+	// RemoteHost returns the host a dialer writes to, or an empty string for listeners.
+	RemoteHost() string
+}
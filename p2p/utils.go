@@ -45,3 +45,24 @@ func ValidatorToNetworkPeer(cfg modules.P2PConfig, v modules.Actor) (*typesP2P.N
 
 	return peer, nil
 }
+
+// getPeerAddressFromHost returns the address of the peer in the address book whose dialer writes to
+// `remoteHost`. It returns nil if the host is unknown or shared by more than one peer (e.g. several
+// nodes behind the same NAT), in which case the data cannot be reliably attributed to a single peer.
+func getPeerAddressFromHost(addrBook typesP2P.AddrBook, remoteHost string) cryptoPocket.Address {
+	if remoteHost == "" {
+		return nil
+	}
+	var address cryptoPocket.Address
+	for _, peer := range addrBook {
+		dialer, ok := peer.Dialer.(typesP2P.PeerAwareTransport)
+		if !ok || dialer.RemoteHost() != remoteHost {
+			continue
+		}
+		if address != nil {
+			return nil
+		}
+		address = peer.Address
+	}
+	return address
+}
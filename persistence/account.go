@@ -29,13 +29,22 @@ func (p PostgresContext) getAccountAmountStr(address string, height int64) (amou
 	}
 
 	amount = defaultAccountAmountStr
-	if err = tx.QueryRow(ctx, types.GetAccountAmountQuery(address, height)).Scan(&amount); err != pgx.ErrNoRows {
+	query, args := types.GetAccountAmountQuery(address, height)
+	if err = tx.QueryRow(ctx, query, args...).Scan(&amount); err != pgx.ErrNoRows {
 		return
 	}
 
 	return amount, nil
 }
 
+func (p PostgresContext) GetAccountAmountWithProof(address []byte, height int64) (amount string, proof []byte, err error) {
+	if amount, err = p.GetAccountAmount(address, height); err != nil {
+		return
+	}
+	proof, err = p.stateTrees.prove(types.AccountMerkleTree, address, height)
+	return
+}
+
 func (p PostgresContext) AddAccountAmount(address []byte, amount string) error {
 	return p.operationAccountAmount(address, amount, func(orig *big.Int, delta *big.Int) error {
 		orig.Add(orig, delta)
@@ -62,7 +71,8 @@ func (p PostgresContext) SetAccountAmount(address []byte, amount string) error {
 		return err
 	}
 	// DISCUSS(team): Do we want to panic if `amount < 0` here?
-	if _, err = tx.Exec(ctx, types.InsertAccountAmountQuery(hex.EncodeToString(address), amount, height)); err != nil {
+	query, args := types.InsertAccountAmountQuery(hex.EncodeToString(address), amount, height)
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
 		return err
 	}
 	return nil
@@ -84,7 +94,8 @@ func (p PostgresContext) InsertPool(name string, address []byte, amount string)
 	if err != nil {
 		return err
 	}
-	if _, err = tx.Exec(ctx, types.InsertPoolAmountQuery(name, amount, height)); err != nil {
+	query, args := types.InsertPoolAmountQuery(name, amount, height)
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
 		return err
 	}
 	return nil
@@ -97,13 +108,22 @@ func (p PostgresContext) GetPoolAmount(name string, height int64) (amount string
 	}
 
 	amount = defaultAccountAmountStr
-	if err = tx.QueryRow(ctx, types.GetPoolAmountQuery(name, height)).Scan(&amount); err != pgx.ErrNoRows {
+	query, args := types.GetPoolAmountQuery(name, height)
+	if err = tx.QueryRow(ctx, query, args...).Scan(&amount); err != pgx.ErrNoRows {
 		return
 	}
 
 	return amount, nil
 }
 
+func (p PostgresContext) GetPoolAmountWithProof(name string, height int64) (amount string, proof []byte, err error) {
+	if amount, err = p.GetPoolAmount(name, height); err != nil {
+		return
+	}
+	proof, err = p.stateTrees.prove(types.PoolMerkleTree, []byte(name), height)
+	return
+}
+
 func (p PostgresContext) AddPoolAmount(name string, amount string) error {
 	return p.operationPoolAmount(name, amount, func(s *big.Int, s1 *big.Int) error {
 		s.Add(s, s1)
@@ -130,7 +150,8 @@ func (p PostgresContext) SetPoolAmount(name string, amount string) error {
 	if err != nil {
 		return err
 	}
-	if _, err = tx.Exec(ctx, types.InsertPoolAmountQuery(name, amount, height)); err != nil {
+	query, args := types.InsertPoolAmountQuery(name, amount, height)
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
 		return err
 	}
 	return nil
@@ -143,7 +164,7 @@ func (p *PostgresContext) operationPoolAmount(name string, amount string, op fun
 func (p *PostgresContext) operationPoolOrAccAmount(name, amount string,
 	op func(*big.Int, *big.Int) error,
 	getAmount func(string, int64) (string, error),
-	insert func(name, amount string, height int64) string) error {
+	insert func(name, amount string, height int64) (string, []any)) error {
 	ctx, tx, err := p.GetCtxAndTx()
 	if err != nil {
 		return err
@@ -167,7 +188,8 @@ func (p *PostgresContext) operationPoolOrAccAmount(name, amount string,
 	if err := op(originalAmountBig, amountBig); err != nil {
 		return err
 	}
-	if _, err = tx.Exec(ctx, insert(name, types.BigIntToString(originalAmountBig), height)); err != nil {
+	query, args := insert(name, types.BigIntToString(originalAmountBig), height)
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
 		return err
 	}
 	return nil
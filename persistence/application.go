@@ -25,6 +25,10 @@ func (p PostgresContext) GetApp(address []byte, height int64) (operator, publicK
 	return
 }
 
+func (p PostgresContext) GetAppWithProof(address []byte, height int64) (modules.Actor, []byte, error) {
+	return p.getActorWithProof(types.AppMerkleTree, address, height)
+}
+
 func (p PostgresContext) InsertApp(address []byte, publicKey []byte, output []byte, _ bool, _ int32, maxRelays string, stakedTokens string, chains []string, pausedHeight int64, unstakingHeight int64) error {
 	return p.InsertActor(types.ApplicationActor, types.BaseActor{
 		Address:            hex.EncodeToString(address),
@@ -3,9 +3,10 @@ package persistence
 import (
 	"encoding/binary"
 	"encoding/hex"
-	"log"
+	"fmt"
 
 	"github.com/pokt-network/pocket/persistence/types"
+	"github.com/pokt-network/pocket/shared/indexer"
 )
 
 // OPTIMIZE(team): get from blockstore or keep in memory
@@ -19,6 +20,17 @@ func (p PostgresContext) GetLatestBlockHeight() (latestHeight uint64, err error)
 	return
 }
 
+// getMaxBlockHeight returns the height of the latest block in the database, or -1 if there are none.
+func (p PostgresContext) getMaxBlockHeight() (maxHeight int64, err error) {
+	ctx, tx, err := p.GetCtxAndTx()
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.QueryRow(ctx, types.GetMaxBlockHeightQuery()).Scan(&maxHeight)
+	return
+}
+
 // OPTIMIZE(team): get from blockstore or keep in cache/memory
 func (p PostgresContext) GetBlockHash(height int64) ([]byte, error) {
 	ctx, tx, err := p.GetCtxAndTx()
@@ -27,7 +39,8 @@ func (p PostgresContext) GetBlockHash(height int64) ([]byte, error) {
 	}
 
 	var hexHash string
-	err = tx.QueryRow(ctx, types.GetBlockHashQuery(height)).Scan(&hexHash)
+	query, args := types.GetBlockHashQuery(height)
+	err = tx.QueryRow(ctx, query, args...).Scan(&hexHash)
 	if err != nil {
 		return nil, err
 	}
@@ -35,25 +48,39 @@ func (p PostgresContext) GetBlockHash(height int64) ([]byte, error) {
 	return hex.DecodeString(hexHash)
 }
 
+func (p PostgresContext) GetBlock(height int64) ([]byte, error) {
+	return p.blockStore.getByHeight(height)
+}
+
 func (p PostgresContext) GetHeight() (int64, error) {
 	return p.Height, nil
 }
 
+// TransactionExists returns whether the transaction has been indexed. The write context also accounts for the
+// transactions it stored but has yet to commit (e.g. a transaction replayed within the same block).
 func (p PostgresContext) TransactionExists(transactionHash string) (bool, error) {
-	log.Println("TODO: TransactionExists not implemented")
-	return false, nil
+	hash, err := hex.DecodeString(transactionHash)
+	if err != nil {
+		return false, err
+	}
+	return p.txIndexer.exists(hash, !p.isReadOnly)
 }
 
-func (p PostgresContext) StoreTransaction(transactionProtoBytes []byte) error {
-	log.Println("TODO: StoreTransaction not implemented")
+func (p PostgresContext) StoreTransaction(txResult indexer.TxResult) error {
+	if p.isReadOnly {
+		return fmt.Errorf("transactions can only be stored in a write context")
+	}
+	p.txIndexer.stage(txResult)
 	return nil
 }
 
-func (p PostgresContext) StoreBlock(blockProtoBytes []byte) error {
-	// INVESTIGATE: Note that we are writing this directly to the blockStore. Depending on how
-	// the use of the PostgresContext evolves, we may need to write this to `ContextStore` and copy
-	// over to `BlockStore` when the block is committed.
-	return p.blockstore.Put(heightToBytes(p.Height), blockProtoBytes)
+// StoreBlock stages the block, which is written to the block store once the context is committed.
+func (p PostgresContext) StoreBlock(blockProtoBytes []byte, hash string, quorumCert []byte) error {
+	if p.isReadOnly {
+		return fmt.Errorf("blocks can only be stored in a write context")
+	}
+	p.blockStore.stage(p.Height, hash, quorumCert, blockProtoBytes)
+	return nil
 }
 
 func (p PostgresContext) InsertBlock(height uint64, hash string, proposerAddr []byte, quorumCert []byte) error {
@@ -62,13 +89,19 @@ func (p PostgresContext) InsertBlock(height uint64, hash string, proposerAddr []
 		return err
 	}
 
-	_, err = tx.Exec(ctx, types.InsertBlockQuery(height, hash, proposerAddr, quorumCert))
+	query, args := types.InsertBlockQuery(height, hash, proposerAddr, quorumCert)
+	_, err = tx.Exec(ctx, query, args...)
 	return err
 }
 
 // CLEANUP: Should this be moved to a shared directory?
+// The height is big endian so the keys of consecutive heights sort in order.
 func heightToBytes(height int64) []byte {
 	heightBytes := make([]byte, 8)
-	binary.LittleEndian.PutUint64(heightBytes, uint64(height))
+	binary.BigEndian.PutUint64(heightBytes, uint64(height))
 	return heightBytes
 }
+
+func bytesToHeight(heightBytes []byte) int64 {
+	return int64(binary.BigEndian.Uint64(heightBytes))
+}
@@ -0,0 +1,233 @@
+package persistence
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"sync"
+
+	typesCons "github.com/pokt-network/pocket/consensus/types"
+	"github.com/pokt-network/pocket/persistence/kvstore"
+	"github.com/pokt-network/pocket/shared/codec"
+)
+
+var (
+	blockKeyPrefix      = []byte("block/")
+	blockHashKeyPrefix  = []byte("hash/")
+	quorumCertKeyPrefix = []byte("qc/")
+	// Written once the blocks stored by previous versions of the node are migrated, so they are only looked for once
+	legacyKeysMigratedKey = []byte("legacy_keys_migrated")
+)
+
+// The blocks stored by previous versions of the node were keyed by their little endian height, without a prefix.
+const legacyBlockKeyLen = 8
+
+// blockStore keeps the serialized blocks in a KV store. The blocks are keyed by their big endian height, so they
+// are sorted by height, and indexed by hash. The quorum certificate of each block is kept alongside it so the
+// latest one is available without deserializing the block.
+//
+// The blocks stored by the write context are staged until it is committed, when they are written at once, so
+// the block store only holds the blocks whose state was committed. Reads only see the committed blocks.
+type blockStore struct {
+	mu sync.Mutex
+
+	store  kvstore.KVStore
+	staged kvstore.Batch
+}
+
+func newBlockStore(store kvstore.KVStore) *blockStore {
+	return &blockStore{store: store, staged: store.NewBatch()}
+}
+
+func (s *blockStore) stage(height int64, hash string, quorumCert, blockProtoBytes []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.staged.Put(getBlockKey(height), blockProtoBytes)
+	s.staged.Put(getBlockHashKey(hash), heightToBytes(height))
+	s.staged.Put(getQuorumCertKey(height), quorumCert)
+}
+
+// commit writes the staged blocks.
+func (s *blockStore) commit() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.staged.Write()
+}
+
+// discard drops the blocks staged since the last commit.
+func (s *blockStore) discard() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.staged = s.store.NewBatch()
+}
+
+// clear drops the staged blocks along with the whole store.
+func (s *blockStore) clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.staged = s.store.NewBatch()
+	return s.store.ClearAll()
+}
+
+// deleteAbove deletes the blocks above the height, along with their hash and quorum certificate, and returns
+// their number.
+func (s *blockStore) deleteAbove(height int64) (numDeleted int, err error) {
+	batch := s.store.NewBatch()
+
+	it, err := s.store.Iterator(getBlockKey(height+1), kvstore.PrefixEndBytes(blockKeyPrefix), false)
+	if err != nil {
+		return 0, err
+	}
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		batch.Delete(key)
+		batch.Delete(getQuorumCertKey(bytesToHeight(key[len(blockKeyPrefix):])))
+		numDeleted++
+	}
+	it.Close()
+	if err := it.Error(); err != nil {
+		return 0, err
+	}
+
+	it, err = s.store.PrefixIterator(blockHashKeyPrefix, false)
+	if err != nil {
+		return 0, err
+	}
+	for ; it.Valid(); it.Next() {
+		if bytesToHeight(it.Value()) > height {
+			batch.Delete(it.Key())
+		}
+	}
+	it.Close()
+	if err := it.Error(); err != nil {
+		return 0, err
+	}
+
+	return numDeleted, batch.Write()
+}
+
+// migrateLegacyKeys re-keys the blocks stored by previous versions of the node, indexing them by hash along with
+// their quorum certificate, and returns their number. Each block is migrated atomically so an interrupted
+// migration resumes on the next start.
+func (s *blockStore) migrateLegacyKeys() (numMigrated int, err error) {
+	if migrated, err := s.store.Exists(legacyKeysMigratedKey); err != nil || migrated {
+		return 0, err
+	}
+
+	// The legacy keys are unprefixed, so only the ranges around the prefixes of the current keys, which are in
+	// ascending order, are scanned
+	var start []byte
+	for _, prefix := range [][]byte{blockKeyPrefix, blockHashKeyPrefix, quorumCertKeyPrefix, nil} {
+		n, err := s.migrateLegacyKeysInRange(start, prefix)
+		numMigrated += n
+		if err != nil {
+			return numMigrated, err
+		}
+		start = kvstore.PrefixEndBytes(prefix)
+	}
+	return numMigrated, s.store.Put(legacyKeysMigratedKey, []byte{1})
+}
+
+// migrateLegacyKeysInRange migrates the legacy blocks whose keys are in [start, end), skipping the other keys.
+func (s *blockStore) migrateLegacyKeysInRange(start, end []byte) (numMigrated int, err error) {
+	it, err := s.store.Iterator(start, end, false)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+
+	codec := codec.GetCodec()
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		if len(key) != legacyBlockKeyLen {
+			continue
+		}
+		height := int64(binary.LittleEndian.Uint64(key))
+		block := &typesCons.Block{}
+		if err := codec.Unmarshal(it.Value(), block); err != nil || block.BlockHeader.GetHeight() != height {
+			log.Printf("[WARN] Skipping the key %x of the block store, which is not a block stored by a previous version of the node\n", key)
+			continue
+		}
+
+		batch := s.store.NewBatch()
+		batch.Delete(key)
+		batch.Put(getBlockKey(height), it.Value())
+		batch.Put(getBlockHashKey(block.BlockHeader.Hash), heightToBytes(height))
+		batch.Put(getQuorumCertKey(height), block.BlockHeader.QuorumCertificate)
+		if err := batch.Write(); err != nil {
+			return numMigrated, err
+		}
+		numMigrated++
+	}
+	return numMigrated, it.Error()
+}
+
+func (s *blockStore) getByHeight(height int64) ([]byte, error) {
+	return s.store.Get(getBlockKey(height))
+}
+
+func (s *blockStore) getByHash(hash string) ([]byte, error) {
+	heightBz, err := s.store.Get(getBlockHashKey(hash))
+	if err != nil {
+		return nil, err
+	}
+	return s.getByHeight(bytesToHeight(heightBz))
+}
+
+// iterate calls `fn` with the blocks from `fromHeight` to `toHeight` (inclusive), in ascending order, until it
+// returns false.
+func (s *blockStore) iterate(fromHeight, toHeight int64, fn func(height int64, blockProtoBytes []byte) bool) error {
+	it, err := s.store.Iterator(getBlockKey(fromHeight), getBlockKey(toHeight+1), false)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		if !fn(bytesToHeight(it.Key()[len(blockKeyPrefix):]), it.Value()) {
+			break
+		}
+	}
+	return it.Error()
+}
+
+// getLatest returns the block at the greatest height along with its quorum certificate.
+func (s *blockStore) getLatest() (height int64, blockProtoBytes, quorumCert []byte, err error) {
+	it, err := s.store.PrefixIterator(blockKeyPrefix, true)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer it.Close()
+
+	if !it.Valid() {
+		if err := it.Error(); err != nil {
+			return 0, nil, nil, err
+		}
+		return 0, nil, nil, fmt.Errorf("no blocks in the block store: %w", kvstore.ErrKeyNotFound)
+	}
+	height, blockProtoBytes = bytesToHeight(it.Key()[len(blockKeyPrefix):]), it.Value()
+	if quorumCert, err = s.store.Get(getQuorumCertKey(height)); err != nil {
+		return 0, nil, nil, err
+	}
+	return height, blockProtoBytes, quorumCert, nil
+}
+
+func (s *blockStore) exists(height int64) (bool, error) {
+	return s.store.Exists(getBlockKey(height))
+}
+
+func getBlockKey(height int64) []byte {
+	return append(append([]byte{}, blockKeyPrefix...), heightToBytes(height)...)
+}
+
+func getBlockHashKey(hash string) []byte {
+	return append(append([]byte{}, blockHashKeyPrefix...), hash...)
+}
+
+func getQuorumCertKey(height int64) []byte {
+	return append(append([]byte{}, quorumCertKeyPrefix...), heightToBytes(height)...)
+}
@@ -1,23 +1,57 @@
 package persistence
 
 import (
-	"context"
+	"fmt"
 	"log"
+
+	"github.com/pokt-network/pocket/shared/crypto"
 )
 
+// Postgres identifiers are limited to 63 bytes, so save points are named after a prefix of the hash of their key
+const savePointNameHashLen = 16
+
 func (p PostgresContext) NewSavePoint(bytes []byte) error {
-	log.Println("TODO: NewSavePoint not implemented")
-	return nil
+	ctx, tx, err := p.GetCtxAndTx()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, fmt.Sprintf("SAVEPOINT %s", getSavePointName(bytes)))
+	return err
 }
 
+// RollbackToSavePoint reverts the writes made since the save point was created, which also recovers the
+// transaction if a statement failed in the meantime. The save point can be rolled back to again.
 func (p PostgresContext) RollbackToSavePoint(bytes []byte) error {
-	log.Println("TODO: RollbackToSavePoint not fully implemented")
-	return p.GetTx().Rollback(context.TODO())
+	ctx, tx, err := p.GetCtxAndTx()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", getSavePointName(bytes)))
+	return err
+}
+
+// ReleaseSavePoint keeps the writes made since the save point was created and destroys it (along with the
+// save points created after it) so it no longer uses resources until the context is committed.
+func (p PostgresContext) ReleaseSavePoint(bytes []byte) error {
+	ctx, tx, err := p.GetCtxAndTx()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, fmt.Sprintf("RELEASE SAVEPOINT %s", getSavePointName(bytes)))
+	return err
+}
+
+func getSavePointName(bytes []byte) string {
+	return fmt.Sprintf("savepoint_%x", crypto.SHA3Hash(bytes)[:savePointNameHashLen])
 }
 
+// AppHash returns the hash of the roots of the state merkle trees, updated with the state written at the
+// height of the context.
 func (p PostgresContext) AppHash() ([]byte, error) {
-	log.Println("TODO: AppHash not implemented")
-	return []byte("A real app hash, I am not"), nil
+	if p.isReadOnly {
+		return nil, fmt.Errorf("the app hash can only be computed in a write context")
+	}
+	return p.stateTrees.update(p)
 }
 
 func (p PostgresContext) Reset() error {
@@ -26,33 +60,51 @@ func (p PostgresContext) Reset() error {
 
 func (p PostgresContext) Commit() error {
 	log.Printf("About to commit context at height %d.\n", p.Height)
+	defer p.releaseConn()
 
-	ctx := context.TODO()
-	if err := p.GetTx().Commit(context.TODO()); err != nil {
-		return err
+	// The state written by the context is committed to even if the app hash was never requested (e.g. genesis)
+	if !p.stateTrees.isUpdated() {
+		if _, err := p.AppHash(); err != nil {
+			return err
+		}
 	}
-	if err := p.conn.Close(ctx); err != nil {
-		log.Println("[TODO][ERROR] Implement connection pooling. Error when closing DB connecting...", err)
 
+	if err := p.GetTx().Commit(p.ctx); err != nil {
+		return err
+	}
+	// The state is committed to the database at this point, so the transactions and the block are committed even if
+	// the merkle trees fail to be
+	treesErr := p.stateTrees.commit(p.Height)
+	if err := p.txIndexer.commit(); err != nil {
+		return err
+	}
+	// NOTE: If the block store is not written, the node refuses to start until the missing block is restored
+	if err := p.blockStore.commit(); err != nil {
+		return err
+	}
+	if treesErr != nil {
+		// The node has to be restarted for `syncStateTrees` to rebuild the missing roots from the database
+		return fmt.Errorf("committed the state at height %d but not its merkle roots, which are missing until the node restarts and rebuilds them: %w", p.Height, treesErr)
 	}
 	return nil
 }
 
 func (p PostgresContext) Release() error {
 	log.Printf("About to release context at height %d.\n", p.Height)
+	defer p.releaseConn()
 
-	ctx := context.TODO()
-	if err := p.GetTx().Rollback(ctx); err != nil {
-		return err
-	}
-	if err := p.conn.Close(ctx); err != nil {
-		log.Println("[TODO][ERROR] Implement connection pooling. Error when closing DB connecting...", err)
+	if !p.isReadOnly {
+		p.stateTrees.discard()
+		p.txIndexer.discard()
+		p.blockStore.discard()
 	}
-	return nil
+	return p.GetTx().Rollback(p.ctx)
 }
 
 func (p PostgresContext) Close() error {
 	log.Printf("About to close context at height %d.\n", p.Height)
+	defer p.releaseConn()
 
-	return p.conn.Close(context.TODO())
+	// Read contexts never write, so their transaction is only rolled back to return the connection to the pool
+	return p.GetTx().Rollback(p.ctx)
 }
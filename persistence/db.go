@@ -2,28 +2,17 @@ package persistence
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/pokt-network/pocket/persistence/types"
 
-	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
-	"github.com/pokt-network/pocket/persistence/kvstore"
 	"github.com/pokt-network/pocket/shared/modules"
 )
 
 const (
-	CreateSchema    = "CREATE SCHEMA"
-	SetSearchPathTo = "SET search_path TO"
-	CreateTable     = "CREATE TABLE"
-
-	IfNotExists = "IF NOT EXISTS"
-
-	CreateEnumType = "CREATE TYPE %s AS ENUM"
-
-	// DUPLICATE OBJECT error. For reference: https://www.postgresql.org/docs/8.4/errcodes-appendix.html
-	DuplicateObjectErrorCode = "42710"
+	defaultMaxConnsCount        = 8
+	defaultStatementTimeoutMsec = 10000
 )
 
 var protocolActorSchemas = []types.ProtocolActorSchema{
@@ -35,128 +24,99 @@ var protocolActorSchemas = []types.ProtocolActorSchema{
 
 var _ modules.PersistenceRWContext = &PostgresContext{}
 
+// NOTE: The context is named after Postgres, its original backend, but it is shared by every database backend.
 type PostgresContext struct {
-	Height     int64 // TODO(olshansky): `Height` is only externalized for testing purposes. Replace with helpers...
-	conn       *pgx.Conn
-	tx         pgx.Tx
-	blockstore kvstore.KVStore
+	Height int64 // TODO(olshansky): `Height` is only externalized for testing purposes. Replace with helpers...
+	tx     SQLTx
+	// All the queries of the context are bound to `ctx`, which is cancelled once the context is committed,
+	// released or closed, or when its deadline expires.
+	ctx        context.Context
+	cancel     context.CancelFunc
+	blockStore *blockStore
+	stateTrees *stateTrees
+	txIndexer  *txIndexer
+	isReadOnly bool
 }
 
-func (pg *PostgresContext) GetCtxAndTx() (context.Context, pgx.Tx, error) {
-	return context.TODO(), pg.GetTx(), nil
+func (pg *PostgresContext) GetCtxAndTx() (context.Context, SQLTx, error) {
+	return pg.ctx, pg.GetTx(), nil
 }
 
-func (pg *PostgresContext) GetTx() pgx.Tx {
+func (pg *PostgresContext) GetTx() SQLTx {
 	return pg.tx
 }
 
 func (pg *PostgresContext) GetCtx() (context.Context, error) {
-	return context.TODO(), nil
+	return pg.ctx, nil
 }
 
-// TECHDEBT: Implement proper connection pooling
-func connectToDatabase(postgresUrl string, schema string) (*pgx.Conn, error) {
-	ctx := context.TODO()
-
-	conn, err := pgx.Connect(context.Background(), postgresUrl)
-	if err != nil {
-		return nil, fmt.Errorf("unable to connect to database: %v", err)
-	}
-
-	// Creating and setting a new schema so we can running multiple nodes on one postgres instance. See
-	// more details at https://github.com/go-pg/pg/issues/351.
-	if _, err = conn.Exec(ctx, fmt.Sprintf("%s %s %s", CreateSchema, IfNotExists, schema)); err != nil {
-		return nil, err
-	}
-
-	// Creating and setting a new schema so we can run multiple nodes on one postgres instance.
-	// See more details at https://github.com/go-pg/pg/issues/351.
-	if _, err := conn.Exec(ctx, fmt.Sprintf("%s %s %s", CreateSchema, IfNotExists, schema)); err != nil {
-		return nil, err
-	}
-	if _, err := conn.Exec(ctx, fmt.Sprintf("%s %s", SetSearchPathTo, schema)); err != nil {
-		return nil, err
-	}
-
-	return conn, nil
+// isOpen returns false once the context has been committed, released or closed, or its deadline expired.
+func (pg *PostgresContext) isOpen() bool {
+	return pg.ctx.Err() == nil
 }
 
-// TODO(pokt-network/pocket/issues/77): Enable proper up and down migrations
-func initializeDatabase(conn *pgx.Conn) error {
-	// Initialize the tables if they don't already exist
-	if err := initializeAllTables(context.TODO(), conn); err != nil {
-		return fmt.Errorf("unable to initialize tables: %v", err)
-	}
-	return nil
+// releaseConn returns the connection to the pool, which rolls back its transaction if it is still in progress.
+// It is safe to call multiple times.
+func (pg *PostgresContext) releaseConn() {
+	pg.tx.release()
+	pg.cancel()
 }
 
-// TODO(pokt-network/pocket/issues/77): Delete all the `initializeAllTables` calls once proper migrations are implemented.
-func initializeAllTables(ctx context.Context, db *pgx.Conn) error {
-	if err := initializeAccountTables(ctx, db); err != nil {
-		return err
-	}
-
-	if err := initializeGovTables(ctx, db); err != nil {
-		return err
-	}
-
-	if err := initializeBlockTables(ctx, db); err != nil {
-		return err
-	}
-
-	for _, actor := range protocolActorSchemas {
-		if err := initializeProtocolActorTables(ctx, db, actor); err != nil {
-			return err
-		}
-	}
-
-	return nil
+// SQLTx is a transaction of the database backing the persistence module. The contexts run their queries through
+// it so they are shared by the database backends.
+type SQLTx interface {
+	Exec(ctx context.Context, sql string, args ...any) (rowsAffected int64, err error)
+	Query(ctx context.Context, sql string, args ...any) (SQLRows, error)
+	// The returned row fails to scan with `pgx.ErrNoRows` if the query returns no rows, whatever the backend.
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	// Begin starts a pseudo nested transaction backed by a save point.
+	Begin(ctx context.Context) (SQLTx, error)
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+
+	// release returns the connection of the transaction to the pool, which rolls back the transaction if it is
+	// still in progress. It is safe to call multiple times.
+	release()
 }
 
-func initializeProtocolActorTables(ctx context.Context, db *pgx.Conn, actor types.ProtocolActorSchema) error {
-	if _, err := db.Exec(ctx, fmt.Sprintf(`%s %s %s %s`, CreateTable, IfNotExists, actor.GetTableName(), actor.GetTableSchema())); err != nil {
-		return err
-	}
-	if actor.GetChainsTableName() != "" {
-		if _, err := db.Exec(ctx, fmt.Sprintf(`%s %s %s %s`, CreateTable, IfNotExists, actor.GetChainsTableName(), actor.GetChainsTableSchema())); err != nil {
-			return err
-		}
-	}
-	return nil
+// SQLRows is implemented by the rows returned by the queries of both backends.
+type SQLRows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close()
 }
 
-func initializeAccountTables(ctx context.Context, db *pgx.Conn) error {
-	if _, err := db.Exec(ctx, fmt.Sprintf(`%s %s %s %s`, CreateTable, IfNotExists, types.AccountTableName, types.AccountTableSchema)); err != nil {
-		return err
-	}
-	if _, err := db.Exec(ctx, fmt.Sprintf(`%s %s %s %s`, CreateTable, IfNotExists, types.PoolTableName, types.PoolTableSchema)); err != nil {
-		return err
-	}
-	return nil
+// database is the SQL database backing the persistence module.
+type database interface {
+	// beginTx acquires a connection from the pool and begins a transaction on it. Only one write transaction
+	// is expected at a time.
+	beginTx(ctx context.Context, readOnly bool) (SQLTx, error)
+	// beginMigrationTx begins a transaction that prevents other processes from migrating the schema until it
+	// ends. The schema migrations table exists once it began.
+	beginMigrationTx(ctx context.Context) (SQLTx, error)
+	backend() string
+	stats() poolStats
+	close()
 }
 
-func initializeGovTables(ctx context.Context, db *pgx.Conn) error {
-	if _, err := db.Exec(ctx, fmt.Sprintf(`%s %s`, fmt.Sprintf(CreateEnumType, types.ValTypeName), types.ValTypeEnumTypes)); err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code != DuplicateObjectErrorCode {
-			return err
-		}
-	}
-
-	if _, err := db.Exec(ctx, fmt.Sprintf(`%s %s %s %s`, CreateTable, IfNotExists, types.ParamsTableName, types.ParamsTableSchema)); err != nil {
-		return err
-	}
-
-	if _, err := db.Exec(ctx, fmt.Sprintf(`%s %s %s %s`, CreateTable, IfNotExists, types.FlagsTableName, types.FlagsTableSchema)); err != nil {
-		return err
+// openDatabase connects to the database backend configured for the node.
+func openDatabase(ctx context.Context, cfg modules.PersistenceConfig) (database, error) {
+	switch backend := cfg.GetDatabaseBackend(); backend {
+	case "", types.PostgresBackend:
+		return connectToPostgres(ctx, cfg)
+	case types.SQLiteBackend:
+		return openSQLite(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown database backend: %s", backend)
 	}
-
-	return nil
 }
 
-func initializeBlockTables(ctx context.Context, db *pgx.Conn) error {
-	if _, err := db.Exec(ctx, fmt.Sprintf(`%s %s %s %s`, CreateTable, IfNotExists, types.BlockTableName, types.BlockTableSchema)); err != nil {
-		return err
+// initializeDatabase migrates the schema of the node to the latest version, and refuses to proceed if the schema
+// was migrated by a newer version of the node.
+func initializeDatabase(ctx context.Context, db database) error {
+	if err := migrateSchema(ctx, db, types.LatestSchemaVersion()); err != nil {
+		return fmt.Errorf("unable to migrate the schema: %v", err)
 	}
 	return nil
 }
@@ -28,11 +28,9 @@ func (m *PersistenceModule) HandleDebugMessage(debugMessage *debug.DebugMessage)
 
 // TODO(olshansky): Create a shared interface `Block` to avoid the use of typesCons here.
 func (m *PersistenceModule) showLatestBlockInStore(_ *debug.DebugMessage) {
-	// TODO: Add an iterator to the `kvstore` and use that instead
-	height := m.GetBus().GetConsensusModule().CurrentHeight() - 1 // -1 because we want the latest committed height
-	blockBytes, err := m.GetBlockStore().Get(heightToBytes(int64(height)))
+	height, blockBytes, _, err := m.GetLatestBlock()
 	if err != nil {
-		log.Printf("Error getting block %d from block store: %s \n", height, err)
+		log.Printf("Error getting the latest block from block store: %s \n", err)
 		return
 	}
 	codec := codec.GetCodec()
@@ -53,8 +51,16 @@ func (m *PersistenceModule) clearState(_ *debug.DebugMessage) {
 		log.Printf("Error clearing state: %s \n", err)
 		return
 	}
-	if err := m.blockStore.ClearAll(); err != nil {
+	if err := m.blockStore.clear(); err != nil {
 		log.Printf("Error clearing block store: %s \n", err)
 		return
 	}
+	if err := m.stateTrees.clear(); err != nil {
+		log.Printf("Error clearing state trees: %s \n", err)
+		return
+	}
+	if err := m.txIndexer.clear(); err != nil {
+		log.Printf("Error clearing transaction index: %s \n", err)
+		return
+	}
 }
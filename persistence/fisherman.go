@@ -24,6 +24,10 @@ func (p PostgresContext) GetFisherman(address []byte, height int64) (operator, p
 	return
 }
 
+func (p PostgresContext) GetFishermanWithProof(address []byte, height int64) (modules.Actor, []byte, error) {
+	return p.getActorWithProof(types.FishMerkleTree, address, height)
+}
+
 func (p PostgresContext) InsertFisherman(address []byte, publicKey []byte, output []byte, _ bool, _ int32, serviceURL string, stakedTokens string, chains []string, pausedHeight int64, unstakingHeight int64) error {
 	return p.InsertActor(types.FishermanActor, types.BaseActor{
 		Address:            hex.EncodeToString(address),
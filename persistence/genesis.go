@@ -162,7 +162,8 @@ func (p PostgresContext) GetAllAccounts(height int64) (accs []modules.Account, e
 	if err != nil {
 		return nil, err
 	}
-	rows, err := tx.Query(ctx, types.SelectAccounts(height, types.AccountTableName))
+	query, args := types.SelectAccounts(height, types.AccountTableName)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +187,8 @@ func (p PostgresContext) GetAllPools(height int64) (accs []modules.Account, err
 	if err != nil {
 		return nil, err
 	}
-	rows, err := tx.Query(ctx, types.SelectPools(height, types.PoolTableName))
+	query, args := types.SelectPools(height, types.PoolTableName)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -205,7 +207,8 @@ func (p PostgresContext) GetAllApps(height int64) (apps []modules.Actor, err err
 	if err != nil {
 		return nil, err
 	}
-	rows, err := tx.Query(ctx, types.ApplicationActor.GetAllQuery(height))
+	query, args := types.ApplicationActor.GetAllQuery(height)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -234,7 +237,8 @@ func (p PostgresContext) GetAllValidators(height int64) (vals []modules.Actor, e
 	if err != nil {
 		return nil, err
 	}
-	rows, err := tx.Query(ctx, types.ValidatorActor.GetAllQuery(height))
+	query, args := types.ValidatorActor.GetAllQuery(height)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -263,7 +267,8 @@ func (p PostgresContext) GetAllServiceNodes(height int64) (sn []modules.Actor, e
 	if err != nil {
 		return nil, err
 	}
-	rows, err := tx.Query(ctx, types.ServiceNodeActor.GetAllQuery(height))
+	query, args := types.ServiceNodeActor.GetAllQuery(height)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -292,7 +297,8 @@ func (p PostgresContext) GetAllFishermen(height int64) (f []modules.Actor, err e
 	if err != nil {
 		return nil, err
 	}
-	rows, err := tx.Query(ctx, types.FishermanActor.GetAllQuery(height))
+	query, args := types.FishermanActor.GetAllQuery(height)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
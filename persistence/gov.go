@@ -26,7 +26,8 @@ func (p PostgresContext) InitParams() error {
 	if err != nil {
 		return err
 	}
-	_, err = tx.Exec(ctx, types.InsertParams(types.DefaultParams(), p.Height))
+	query, args := types.InsertParams(types.DefaultParams(), p.Height)
+	_, err = tx.Exec(ctx, query, args...)
 	return err
 }
 
@@ -104,7 +105,8 @@ func setParamOrFlag[T types.SupportedParamTypes](p PostgresContext, paramName st
 	if enabled != nil {
 		tableName = types.FlagsTableName
 	}
-	if _, err = tx.Exec(ctx, types.InsertParamOrFlag(tableName, paramName, height, paramValue, enabled)); err != nil {
+	query, args := types.InsertParamOrFlag(tableName, paramName, height, paramValue, enabled)
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
 		return err
 	}
 	return nil
@@ -117,7 +119,8 @@ func getParamOrFlag[T int | string | []byte](p PostgresContext, tableName, param
 	}
 
 	var stringVal string
-	row := tx.QueryRow(ctx, types.GetParamOrFlagQuery(tableName, paramName, height))
+	query, args := types.GetParamOrFlagQuery(tableName, paramName, height)
+	row := tx.QueryRow(ctx, query, args...)
 	if tableName == types.ParamsTableName {
 		err = row.Scan(&stringVal)
 	} else {
@@ -1,28 +1,77 @@
 package kvstore
 
 import (
+	"bytes"
+	"errors"
 	"log"
 
 	badger "github.com/dgraph-io/badger/v3"
 )
 
+// KVReader reads the entries of a store, or of a snapshot of it.
+type KVReader interface {
+	Get(key []byte) ([]byte, error)
+	GetAll(prefixKey []byte, descending bool) ([][]byte, error)
+	Exists(key []byte) (bool, error)
+
+	// Iterator iterates over the entries whose keys are within `[start, end)`, ordered by key (descending if
+	// `reverse`). A nil bound leaves that end of the range unbounded.
+	Iterator(start, end []byte, reverse bool) (Iterator, error)
+	// PrefixIterator iterates over the entries whose keys start with the prefix, ordered by key.
+	PrefixIterator(prefix []byte, reverse bool) (Iterator, error)
+}
+
 // CLEANUP: move this structure to a shared module
 type KVStore interface {
 	// Lifecycle methods
 	Stop() error
 
 	// Accessors
-	// TODO: Add a proper iterator interface
+	KVReader
 	Put(key []byte, value []byte) error
-	Get(key []byte) ([]byte, error)
-	// TODO: Add pagination for `GetAll`
-	GetAll(prefixKey []byte, descending bool) ([][]byte, error)
-	Exists(key []byte) (bool, error)
+	Delete(key []byte) error
 	ClearAll() error
+
+	// NewBatch returns a batch whose writes are applied to the store at once, when it is written
+	NewBatch() Batch
+	// Snapshot returns a view of the store which the writes made after it is taken are not visible in
+	Snapshot() (Snapshot, error)
+}
+
+// Iterator is positioned on its first entry when it is created, if any. It must be closed once done with.
+//
+//	for ; it.Valid(); it.Next() {
+//		key, value := it.Key(), it.Value()
+//	}
+//	if err := it.Error(); err != nil { ... }
+type Iterator interface {
+	Valid() bool
+	Next()
+	Key() []byte
+	Value() []byte
+	Error() error // The first error encountered while iterating, which invalidates the iterator
+	Close()
+}
+
+// Batch buffers writes until they are written to the store in a single atomic operation.
+type Batch interface {
+	Put(key []byte, value []byte)
+	Delete(key []byte)
+	// Write applies the buffered writes to the store and empties the batch
+	Write() error
+}
+
+// Snapshot must be released once done with.
+type Snapshot interface {
+	KVReader
+	Release()
 }
 
 var _ KVStore = &badgerKVStore{}
 
+// ErrKeyNotFound is returned by `Get` when the key does not exist in the store
+var ErrKeyNotFound = badger.ErrKeyNotFound
+
 type badgerKVStore struct {
 	db *badger.DB
 }
@@ -35,7 +84,9 @@ func NewKVStore(path string) (KVStore, error) {
 	return badgerKVStore{db: db}, nil
 }
 
-func NewMemKVStore() KVStore {
+// NewBadgerMemKVStore returns a badger store held in memory, for when the behaviour of badger itself matters
+// (e.g. in tests); `NewMemKVStore` is lighter otherwise.
+func NewBadgerMemKVStore() KVStore {
 	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true))
 	if err != nil {
 		log.Fatal(err)
@@ -59,58 +110,68 @@ func (store badgerKVStore) Get(key []byte) ([]byte, error) {
 	tx := store.db.NewTransaction(false)
 	defer tx.Discard()
 
-	item, err := tx.Get(key)
-	if err != nil {
-		return nil, err
-	}
+	return badgerGet(tx, key)
+}
 
-	value, err := item.ValueCopy(nil)
+func (store badgerKVStore) GetAll(prefix []byte, descending bool) (values [][]byte, err error) {
+	// INVESTIGATE: research `badger.views` for further improvements and optimizations
+	it, err := store.PrefixIterator(prefix, descending)
 	if err != nil {
 		return nil, err
 	}
+	return iteratorValues(it)
+}
 
-	if err := tx.Commit(); err != nil {
-		return nil, err
-	}
+func (store badgerKVStore) Exists(key []byte) (bool, error) {
+	return exists(store, key)
+}
 
-	return value, nil
+func (store badgerKVStore) Iterator(start, end []byte, reverse bool) (Iterator, error) {
+	return newBadgerIterator(store.db.NewTransaction(false), start, end, reverse, true), nil
 }
 
-func (store badgerKVStore) GetAll(prefix []byte, descending bool) (values [][]byte, err error) {
-	// INVESTIGATE: research `badger.views` for further improvements and optimizations
-	txn := store.db.NewTransaction(false)
-	defer txn.Discard()
+func (store badgerKVStore) PrefixIterator(prefix []byte, reverse bool) (Iterator, error) {
+	return store.Iterator(prefix, PrefixEndBytes(prefix), reverse)
+}
 
-	opt := badger.DefaultIteratorOptions
-	opt.Prefix = prefix
-	opt.Reverse = descending
-	if descending {
-		prefix = prefixEndBytes(prefix)
+func (store badgerKVStore) Delete(key []byte) error {
+	tx := store.db.NewTransaction(true)
+	defer tx.Discard()
+
+	if err := tx.Delete(key); err != nil {
+		return err
 	}
-	it := txn.NewIterator(opt)
-	defer it.Close()
 
-	for it.Seek(prefix); it.Valid(); it.Next() {
-		item := it.Item()
-		err = item.Value(func(v []byte) error {
-			b := make([]byte, len(v))
-			copy(b, v)
-			values = append(values, b)
-			return nil
-		})
+	return tx.Commit()
+}
+
+func (store badgerKVStore) NewBatch() Batch {
+	return &batch{write: store.writeBatch}
+}
+
+// writeBatch applies the operations in a single transaction so they are committed atomically.
+func (store badgerKVStore) writeBatch(ops []batchOp) error {
+	tx := store.db.NewTransaction(true)
+	defer tx.Discard()
+
+	for _, op := range ops {
+		var err error
+		if op.isDelete {
+			err = tx.Delete(op.key)
+		} else {
+			err = tx.Set(op.key, op.value)
+		}
 		if err != nil {
-			return
+			return err
 		}
 	}
-	return
+
+	return tx.Commit()
 }
 
-func (store badgerKVStore) Exists(key []byte) (bool, error) {
-	val, err := store.Get(key)
-	if err != nil {
-		return false, err
-	}
-	return val != nil, nil
+// Snapshot is backed by a read-only transaction, which sees the store as of when it began.
+func (store badgerKVStore) Snapshot() (Snapshot, error) {
+	return &badgerSnapshot{tx: store.db.NewTransaction(false)}, nil
 }
 
 func (store badgerKVStore) ClearAll() error {
@@ -121,15 +182,179 @@ func (store badgerKVStore) Stop() error {
 	return store.db.Close()
 }
 
+var _ Snapshot = &badgerSnapshot{}
+
+type badgerSnapshot struct {
+	tx *badger.Txn
+}
+
+func (s *badgerSnapshot) Get(key []byte) ([]byte, error) {
+	return badgerGet(s.tx, key)
+}
+
+func (s *badgerSnapshot) GetAll(prefix []byte, descending bool) ([][]byte, error) {
+	it, err := s.PrefixIterator(prefix, descending)
+	if err != nil {
+		return nil, err
+	}
+	return iteratorValues(it)
+}
+
+func (s *badgerSnapshot) Exists(key []byte) (bool, error) {
+	return exists(s, key)
+}
+
+func (s *badgerSnapshot) Iterator(start, end []byte, reverse bool) (Iterator, error) {
+	return newBadgerIterator(s.tx, start, end, reverse, false), nil
+}
+
+func (s *badgerSnapshot) PrefixIterator(prefix []byte, reverse bool) (Iterator, error) {
+	return s.Iterator(prefix, PrefixEndBytes(prefix), reverse)
+}
+
+func (s *badgerSnapshot) Release() {
+	s.tx.Discard()
+}
+
+var _ Iterator = &badgerIterator{}
+
+type badgerIterator struct {
+	tx      *badger.Txn
+	ownsTx  bool // Whether the transaction is discarded along with the iterator (i.e. it is not a snapshot's)
+	it      *badger.Iterator
+	start   []byte
+	end     []byte
+	reverse bool
+	err     error
+}
+
+func newBadgerIterator(tx *badger.Txn, start, end []byte, reverse, ownsTx bool) *badgerIterator {
+	opt := badger.DefaultIteratorOptions
+	opt.Reverse = reverse
+	it := tx.NewIterator(opt)
+
+	switch {
+	case !reverse && start != nil:
+		it.Seek(start)
+	case reverse && end != nil:
+		// Seeking in reverse lands on the greatest key up to the given one, but `end` is excluded
+		it.Seek(end)
+		if it.Valid() && bytes.Equal(it.Item().Key(), end) {
+			it.Next()
+		}
+	default:
+		it.Rewind()
+	}
+
+	return &badgerIterator{tx: tx, ownsTx: ownsTx, it: it, start: start, end: end, reverse: reverse}
+}
+
+func (i *badgerIterator) Valid() bool {
+	if i.err != nil || !i.it.Valid() {
+		return false
+	}
+	key := i.it.Item().Key()
+	if i.reverse {
+		return i.start == nil || bytes.Compare(key, i.start) >= 0
+	}
+	return i.end == nil || bytes.Compare(key, i.end) < 0
+}
+
+func (i *badgerIterator) Next() {
+	i.it.Next()
+}
+
+func (i *badgerIterator) Key() []byte {
+	return i.it.Item().KeyCopy(nil)
+}
+
+func (i *badgerIterator) Value() []byte {
+	value, err := i.it.Item().ValueCopy(nil)
+	if err != nil {
+		i.err = err
+	}
+	return value
+}
+
+func (i *badgerIterator) Error() error {
+	return i.err
+}
+
+func (i *badgerIterator) Close() {
+	i.it.Close()
+	if i.ownsTx {
+		i.tx.Discard()
+	}
+}
+
+var _ Batch = &batch{}
+
+// batch buffers the operations which `write` applies to the store atomically.
+type batch struct {
+	ops   []batchOp
+	write func(ops []batchOp) error
+}
+
+type batchOp struct {
+	key      []byte
+	value    []byte
+	isDelete bool
+}
+
+func (b *batch) Put(key []byte, value []byte) {
+	b.ops = append(b.ops, batchOp{key: copyBytes(key), value: copyBytes(value)})
+}
+
+func (b *batch) Delete(key []byte) {
+	b.ops = append(b.ops, batchOp{key: copyBytes(key), isDelete: true})
+}
+
+func (b *batch) Write() error {
+	if err := b.write(b.ops); err != nil {
+		return err
+	}
+	b.ops = nil
+	return nil
+}
+
+func badgerGet(tx *badger.Txn, key []byte) ([]byte, error) {
+	item, err := tx.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return item.ValueCopy(nil)
+}
+
+// exists returns false, rather than `ErrKeyNotFound`, if the key does not exist.
+func exists(reader KVReader, key []byte) (bool, error) {
+	if _, err := reader.Get(key); err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// iteratorValues returns the values of the remaining entries of the iterator, and closes it.
+func iteratorValues(it Iterator) (values [][]byte, err error) {
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		values = append(values, it.Value())
+	}
+	return values, it.Error()
+}
+
 // PrefixEndBytes returns the end byteslice for a noninclusive range
 // that would include all byte slices for which the input is the prefix
-func prefixEndBytes(prefix []byte) []byte {
+func PrefixEndBytes(prefix []byte) []byte {
 	if len(prefix) == 0 {
 		return nil
 	}
 
 	if prefix[len(prefix)-1] == byte(255) {
-		return prefixEndBytes(prefix[:len(prefix)-1])
+		return PrefixEndBytes(prefix[:len(prefix)-1])
 	}
 
 	end := make([]byte, len(prefix))
@@ -0,0 +1,161 @@
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testStores = map[string]func() KVStore{
+	"badger": NewBadgerMemKVStore,
+	"mem":    NewMemKVStore,
+}
+
+func TestKVStore_GetPutDelete(t *testing.T) {
+	for name, newStore := range testStores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+			defer store.Stop()
+
+			_, err := store.Get([]byte("a"))
+			require.ErrorIs(t, err, ErrKeyNotFound)
+			exists, err := store.Exists([]byte("a"))
+			require.NoError(t, err)
+			require.False(t, exists)
+
+			require.NoError(t, store.Put([]byte("a"), []byte("1")))
+			value, err := store.Get([]byte("a"))
+			require.NoError(t, err)
+			require.Equal(t, []byte("1"), value)
+			exists, err = store.Exists([]byte("a"))
+			require.NoError(t, err)
+			require.True(t, exists)
+
+			require.NoError(t, store.Delete([]byte("a")))
+			_, err = store.Get([]byte("a"))
+			require.ErrorIs(t, err, ErrKeyNotFound)
+		})
+	}
+}
+
+func TestKVStore_Iterator(t *testing.T) {
+	for name, newStore := range testStores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+			defer store.Stop()
+			putTestEntries(t, store, "a", "b/1", "b/2", "b/3", "c")
+
+			testCases := []struct {
+				name         string
+				start, end   []byte
+				reverse      bool
+				expectedKeys []string
+			}{
+				{"unbounded", nil, nil, false, []string{"a", "b/1", "b/2", "b/3", "c"}},
+				{"unbounded reverse", nil, nil, true, []string{"c", "b/3", "b/2", "b/1", "a"}},
+				{"start inclusive and end exclusive", []byte("b/1"), []byte("b/3"), false, []string{"b/1", "b/2"}},
+				{"start inclusive and end exclusive in reverse", []byte("b/1"), []byte("b/3"), true, []string{"b/2", "b/1"}},
+				{"bounds between keys", []byte("aa"), []byte("bb"), false, []string{"b/1", "b/2", "b/3"}},
+				{"bounds between keys in reverse", []byte("aa"), []byte("bb"), true, []string{"b/3", "b/2", "b/1"}},
+				{"start only", []byte("b/3"), nil, false, []string{"b/3", "c"}},
+				{"end only in reverse", nil, []byte("b/1"), true, []string{"a"}},
+				{"empty range", []byte("c"), []byte("b"), false, nil},
+			}
+			for _, tc := range testCases {
+				it, err := store.Iterator(tc.start, tc.end, tc.reverse)
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedKeys, iteratorKeys(t, it), tc.name)
+			}
+
+			it, err := store.PrefixIterator([]byte("b/"), true)
+			require.NoError(t, err)
+			require.Equal(t, []string{"b/3", "b/2", "b/1"}, iteratorKeys(t, it))
+			values, err := store.GetAll([]byte("b/"), false)
+			require.NoError(t, err)
+			require.Equal(t, [][]byte{[]byte("b/1"), []byte("b/2"), []byte("b/3")}, values)
+		})
+	}
+}
+
+func TestKVStore_Batch(t *testing.T) {
+	for name, newStore := range testStores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+			defer store.Stop()
+			putTestEntries(t, store, "a", "b")
+
+			batch := store.NewBatch()
+			batch.Put([]byte("c"), []byte("c"))
+			batch.Delete([]byte("a"))
+			// nothing is written until the batch is
+			exists, err := store.Exists([]byte("a"))
+			require.NoError(t, err)
+			require.True(t, exists)
+			exists, err = store.Exists([]byte("c"))
+			require.NoError(t, err)
+			require.False(t, exists)
+
+			require.NoError(t, batch.Write())
+			it, err := store.Iterator(nil, nil, false)
+			require.NoError(t, err)
+			require.Equal(t, []string{"b", "c"}, iteratorKeys(t, it))
+
+			// the batch is emptied once written
+			require.NoError(t, store.Put([]byte("a"), []byte("a")))
+			require.NoError(t, batch.Write())
+			exists, err = store.Exists([]byte("a"))
+			require.NoError(t, err)
+			require.True(t, exists)
+		})
+	}
+}
+
+func TestKVStore_Snapshot(t *testing.T) {
+	for name, newStore := range testStores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+			defer store.Stop()
+			putTestEntries(t, store, "a", "b")
+
+			snapshot, err := store.Snapshot()
+			require.NoError(t, err)
+			defer snapshot.Release()
+
+			require.NoError(t, store.Put([]byte("a"), []byte("modified")))
+			require.NoError(t, store.Put([]byte("c"), []byte("c")))
+			require.NoError(t, store.Delete([]byte("b")))
+
+			// the snapshot does not see the writes made after it was taken
+			value, err := snapshot.Get([]byte("a"))
+			require.NoError(t, err)
+			require.Equal(t, []byte("a"), value)
+			exists, err := snapshot.Exists([]byte("c"))
+			require.NoError(t, err)
+			require.False(t, exists)
+			it, err := snapshot.Iterator(nil, nil, false)
+			require.NoError(t, err)
+			require.Equal(t, []string{"a", "b"}, iteratorKeys(t, it))
+
+			// while the store does
+			it, err = store.Iterator(nil, nil, false)
+			require.NoError(t, err)
+			require.Equal(t, []string{"a", "c"}, iteratorKeys(t, it))
+		})
+	}
+}
+
+// putTestEntries puts every key with itself as its value.
+func putTestEntries(t *testing.T, store KVStore, keys ...string) {
+	for _, key := range keys {
+		require.NoError(t, store.Put([]byte(key), []byte(key)))
+	}
+}
+
+func iteratorKeys(t *testing.T, it Iterator) (keys []string) {
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	require.NoError(t, it.Error())
+	return keys
+}
@@ -0,0 +1,184 @@
+package kvstore
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+)
+
+var _ KVStore = &memKVStore{}
+
+// memKVStore keeps the entries in a map. Its iterators and snapshots copy the entries they cover when they are
+// created, so they are not affected by the writes made afterwards.
+type memKVStore struct {
+	mu      sync.RWMutex
+	entries map[string][]byte
+}
+
+func NewMemKVStore() KVStore {
+	return &memKVStore{entries: make(map[string][]byte)}
+}
+
+func (store *memKVStore) Put(key []byte, value []byte) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.entries[string(key)] = copyBytes(value)
+	return nil
+}
+
+func (store *memKVStore) Get(key []byte) ([]byte, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	value, ok := store.entries[string(key)]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return copyBytes(value), nil
+}
+
+func (store *memKVStore) GetAll(prefix []byte, descending bool) ([][]byte, error) {
+	it, err := store.PrefixIterator(prefix, descending)
+	if err != nil {
+		return nil, err
+	}
+	return iteratorValues(it)
+}
+
+func (store *memKVStore) Exists(key []byte) (bool, error) {
+	return exists(store, key)
+}
+
+// OPTIMIZE: The keys in range are sorted every time an iterator is created, which is fine for the small stores
+// kept in memory (e.g. in tests) but not for large ones.
+func (store *memKVStore) Iterator(start, end []byte, reverse bool) (Iterator, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	it := &memIterator{}
+	for key, value := range store.entries {
+		if (start != nil && bytes.Compare([]byte(key), start) < 0) || (end != nil && bytes.Compare([]byte(key), end) >= 0) {
+			continue
+		}
+		it.keys = append(it.keys, []byte(key))
+		it.values = append(it.values, value)
+	}
+	sort.Sort(it)
+	if reverse {
+		for i, j := 0, it.Len()-1; i < j; i, j = i+1, j-1 {
+			it.Swap(i, j)
+		}
+	}
+	return it, nil
+}
+
+func (store *memKVStore) PrefixIterator(prefix []byte, reverse bool) (Iterator, error) {
+	return store.Iterator(prefix, PrefixEndBytes(prefix), reverse)
+}
+
+func (store *memKVStore) Delete(key []byte) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.entries, string(key))
+	return nil
+}
+
+func (store *memKVStore) NewBatch() Batch {
+	return &batch{write: store.writeBatch}
+}
+
+func (store *memKVStore) writeBatch(ops []batchOp) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, op := range ops {
+		if op.isDelete {
+			delete(store.entries, string(op.key))
+		} else {
+			store.entries[string(op.key)] = op.value
+		}
+	}
+	return nil
+}
+
+// Snapshot copies the whole store.
+func (store *memKVStore) Snapshot() (Snapshot, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	entries := make(map[string][]byte, len(store.entries))
+	for key, value := range store.entries {
+		entries[key] = value
+	}
+	return &memSnapshot{memKVStore: &memKVStore{entries: entries}}, nil
+}
+
+func (store *memKVStore) ClearAll() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.entries = make(map[string][]byte)
+	return nil
+}
+
+func (store *memKVStore) Stop() error {
+	return nil
+}
+
+var _ Snapshot = &memSnapshot{}
+
+// memSnapshot only exposes the reads of the copy of the store it wraps.
+type memSnapshot struct {
+	*memKVStore
+}
+
+func (s *memSnapshot) Release() {}
+
+var _ Iterator = &memIterator{}
+
+// memIterator iterates over entries copied from the store. The values are not copied themselves since the store
+// never mutates them: it replaces them.
+type memIterator struct {
+	keys   [][]byte
+	values [][]byte
+	pos    int
+}
+
+func (i *memIterator) Valid() bool {
+	return i.pos < len(i.keys)
+}
+
+func (i *memIterator) Next() {
+	i.pos++
+}
+
+func (i *memIterator) Key() []byte {
+	return copyBytes(i.keys[i.pos])
+}
+
+func (i *memIterator) Value() []byte {
+	return copyBytes(i.values[i.pos])
+}
+
+func (i *memIterator) Error() error {
+	return nil
+}
+
+func (i *memIterator) Close() {}
+
+// sort.Interface ordering the entries by key
+func (i *memIterator) Len() int           { return len(i.keys) }
+func (i *memIterator) Less(a, b int) bool { return bytes.Compare(i.keys[a], i.keys[b]) < 0 }
+func (i *memIterator) Swap(a, b int) {
+	i.keys[a], i.keys[b] = i.keys[b], i.keys[a]
+	i.values[a], i.values[b] = i.values[b], i.values[a]
+}
+
+func copyBytes(bz []byte) []byte {
+	if bz == nil {
+		return nil
+	}
+	return append([]byte{}, bz...)
+}
@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/pokt-network/pocket/telemetry"
+)
+
+const poolMetricsReportInterval = 10 * time.Second
+
+// poolStats describes the usage of the database connection pool, whatever the backend.
+type poolStats struct {
+	acquiredConns   int64
+	idleConns       int64
+	totalConns      int64
+	emptyAcquires   int64 // Acquisitions that had to wait for a connection
+	acquireDuration time.Duration
+}
+
+var poolMetrics = []struct {
+	name        string
+	description string
+}{
+	{telemetry.PERSISTENCE_POOL_ACQUIRED_CONNS_TIMESERIES_METRIC_NAME, telemetry.PERSISTENCE_POOL_ACQUIRED_CONNS_TIMESERIES_METRIC_DESCRIPTION},
+	{telemetry.PERSISTENCE_POOL_IDLE_CONNS_TIMESERIES_METRIC_NAME, telemetry.PERSISTENCE_POOL_IDLE_CONNS_TIMESERIES_METRIC_DESCRIPTION},
+	{telemetry.PERSISTENCE_POOL_TOTAL_CONNS_TIMESERIES_METRIC_NAME, telemetry.PERSISTENCE_POOL_TOTAL_CONNS_TIMESERIES_METRIC_DESCRIPTION},
+	{telemetry.PERSISTENCE_POOL_EMPTY_ACQUIRES_TIMESERIES_METRIC_NAME, telemetry.PERSISTENCE_POOL_EMPTY_ACQUIRES_TIMESERIES_METRIC_DESCRIPTION},
+	{telemetry.PERSISTENCE_POOL_ACQUIRE_DURATION_TIMESERIES_METRIC_NAME, telemetry.PERSISTENCE_POOL_ACQUIRE_DURATION_TIMESERIES_METRIC_DESCRIPTION},
+}
+
+func (m *PersistenceModule) registerPoolMetrics() {
+	timeSeriesAgent := m.GetBus().GetTelemetryModule().GetTimeSeriesAgent()
+	for _, metric := range poolMetrics {
+		timeSeriesAgent.GaugeRegister(metric.name, metric.description)
+	}
+}
+
+// reportPoolMetrics periodically reports the usage of the database connection pool until the module stops.
+func (m *PersistenceModule) reportPoolMetrics() {
+	ticker := time.NewTicker(poolMetricsReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopped:
+			return
+		case <-ticker.C:
+		}
+
+		stat := m.db.stats()
+		timeSeriesAgent := m.GetBus().GetTelemetryModule().GetTimeSeriesAgent()
+		timeSeriesAgent.GaugeSet(telemetry.PERSISTENCE_POOL_ACQUIRED_CONNS_TIMESERIES_METRIC_NAME, float64(stat.acquiredConns))
+		timeSeriesAgent.GaugeSet(telemetry.PERSISTENCE_POOL_IDLE_CONNS_TIMESERIES_METRIC_NAME, float64(stat.idleConns))
+		timeSeriesAgent.GaugeSet(telemetry.PERSISTENCE_POOL_TOTAL_CONNS_TIMESERIES_METRIC_NAME, float64(stat.totalConns))
+		timeSeriesAgent.GaugeSet(telemetry.PERSISTENCE_POOL_EMPTY_ACQUIRES_TIMESERIES_METRIC_NAME, float64(stat.emptyAcquires))
+		timeSeriesAgent.GaugeSet(telemetry.PERSISTENCE_POOL_ACQUIRE_DURATION_TIMESERIES_METRIC_NAME, float64(stat.acquireDuration.Milliseconds()))
+	}
+}
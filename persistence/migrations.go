@@ -0,0 +1,197 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/pokt-network/pocket/persistence/types"
+	"github.com/pokt-network/pocket/shared/modules"
+)
+
+// MigrationStatus describes a migration of the schema and whether it is applied.
+type MigrationStatus struct {
+	Version     int64
+	Description string
+	AppliedAt   *time.Time // nil if the migration is not applied
+}
+
+// SchemaMigrator migrates the schema of a node, outside of a running node, to a specific version.
+type SchemaMigrator struct {
+	db database
+}
+
+func NewSchemaMigrator(cfg modules.PersistenceConfig) (*SchemaMigrator, error) {
+	db, err := openDatabase(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaMigrator{db: db}, nil
+}
+
+// Migrate applies or reverts the migrations needed for the schema to be at `targetVersion`.
+func (m *SchemaMigrator) Migrate(ctx context.Context, targetVersion int64) error {
+	return migrateSchema(ctx, m.db, targetVersion)
+}
+
+// Rollback reverts the latest migration applied to the schema.
+func (m *SchemaMigrator) Rollback(ctx context.Context) error {
+	version, err := m.getSchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version == 0 {
+		return fmt.Errorf("no migration to roll back")
+	}
+	return migrateSchema(ctx, m.db, version-1)
+}
+
+// Status returns every migration known by the node along with the ones applied to the schema that are not.
+func (m *SchemaMigrator) Status(ctx context.Context) (statuses []MigrationStatus, err error) {
+	tx, err := m.db.beginMigrationTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.release()
+
+	rows, err := tx.Query(ctx, types.GetAppliedMigrationsQuery())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]MigrationStatus)
+	for rows.Next() {
+		var status MigrationStatus
+		var appliedAt any
+		if err := rows.Scan(&status.Version, &status.Description, &appliedAt); err != nil {
+			return nil, err
+		}
+		appliedAtTime, err := getAppliedAtTime(appliedAt)
+		if err != nil {
+			return nil, err
+		}
+		status.AppliedAt = &appliedAtTime
+		applied[status.Version] = status
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, migration := range types.Migrations {
+		status, ok := applied[migration.Version]
+		if !ok {
+			status = MigrationStatus{Version: migration.Version, Description: migration.Description}
+		}
+		statuses = append(statuses, status)
+		delete(applied, migration.Version)
+	}
+	// Migrations applied by a newer version of the node
+	var unknownStatuses []MigrationStatus
+	for _, status := range applied {
+		unknownStatuses = append(unknownStatuses, status)
+	}
+	sort.Slice(unknownStatuses, func(i, j int) bool {
+		return unknownStatuses[i].Version < unknownStatuses[j].Version
+	})
+	return append(statuses, unknownStatuses...), nil
+}
+
+func (m *SchemaMigrator) Close() {
+	m.db.close()
+}
+
+func (m *SchemaMigrator) getSchemaVersion(ctx context.Context) (int64, error) {
+	tx, err := m.db.beginMigrationTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.release()
+	return getSchemaVersion(ctx, tx)
+}
+
+// migrateSchema applies or reverts, within a single transaction, the migrations needed for the schema to be at
+// `targetVersion`. It refuses to touch a schema migrated by a newer version of the node.
+func migrateSchema(ctx context.Context, db database, targetVersion int64) error {
+	latestVersion := types.LatestSchemaVersion()
+	if targetVersion < 0 || targetVersion > latestVersion {
+		return fmt.Errorf("unknown schema version %d: the latest version is %d", targetVersion, latestVersion)
+	}
+
+	tx, err := db.beginMigrationTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.release() // rolls back the transaction unless committed
+
+	version, err := getSchemaVersion(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if version > latestVersion {
+		return fmt.Errorf("the schema is at version %d, which is newer than the latest version %d known by this node", version, latestVersion)
+	}
+
+	if version < targetVersion {
+		for _, migration := range types.Migrations {
+			if migration.Version <= version || migration.Version > targetVersion {
+				continue
+			}
+			log.Printf("Applying schema migration %d: %s\n", migration.Version, migration.Description)
+			if err := execMigrationStatements(ctx, tx, migration.UpStatements(db.backend())); err != nil {
+				return fmt.Errorf("failed to apply schema migration %d: %w", migration.Version, err)
+			}
+			query, args := types.InsertSchemaMigrationQuery(migration.Version, migration.Description, time.Now().Unix(), db.backend())
+			if _, err := tx.Exec(ctx, query, args...); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := len(types.Migrations) - 1; i >= 0; i-- {
+		migration := types.Migrations[i]
+		if migration.Version > version || migration.Version <= targetVersion {
+			continue
+		}
+		log.Printf("Reverting schema migration %d: %s\n", migration.Version, migration.Description)
+		if err := execMigrationStatements(ctx, tx, migration.DownStatements(db.backend())); err != nil {
+			return fmt.Errorf("failed to revert schema migration %d: %w", migration.Version, err)
+		}
+		query, args := types.DeleteSchemaMigrationQuery(migration.Version)
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func execMigrationStatements(ctx context.Context, tx SQLTx, statements []string) error {
+	for _, statement := range statements {
+		if _, err := tx.Exec(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getSchemaVersion returns the version of the latest migration applied to the schema, or 0 if there is none.
+func getSchemaVersion(ctx context.Context, tx SQLTx) (version int64, err error) {
+	err = tx.QueryRow(ctx, types.GetSchemaVersionQuery()).Scan(&version)
+	return
+}
+
+// getAppliedAtTime returns when a migration was applied, which the schemas of Postgres databases below
+// `types.UnixAppliedAtSchemaVersion` store as a timestamp rather than in seconds since the unix epoch.
+func getAppliedAtTime(appliedAt any) (time.Time, error) {
+	switch appliedAt := appliedAt.(type) {
+	case time.Time:
+		return appliedAt, nil
+	case int64:
+		return time.Unix(appliedAt, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("unexpected type %T of the time a migration was applied at", appliedAt)
+	}
+}
@@ -2,15 +2,18 @@ package persistence
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/pokt-network/pocket/persistence/types"
 
-	"github.com/jackc/pgx/v4"
 	"github.com/pokt-network/pocket/persistence/kvstore"
+	"github.com/pokt-network/pocket/shared/indexer"
 	"github.com/pokt-network/pocket/shared/modules"
 	"github.com/pokt-network/pocket/shared/test_artifacts"
 )
@@ -23,12 +26,23 @@ var _ modules.PersistenceConfig = &types.PersistenceConfig{}
 type PersistenceModule struct {
 	bus modules.Bus
 
-	postgresURL string
-	nodeSchema  string
+	db             database
+	contextTimeout time.Duration // Contexts have no deadline if zero
+	stopped        chan struct{} // Closed when the module is stopped so the background goroutines can exit
+	stopOnce       sync.Once
+	background     sync.WaitGroup // The background goroutines, which must exit before the stores are closed
+
 	genesisPath string
-	blockStore  kvstore.KVStore // INVESTIGATE: We may need to create a custom `BlockStore` package in the future
+	blockStore  *blockStore // INVESTIGATE: We may need to create a custom `BlockStore` package in the future
+	treeStore   kvstore.KVStore
+	stateTrees  *stateTrees
+	txIndexer   *txIndexer
+
+	pruning         *pruningStrategy // Nothing is pruned if nil
+	pruningInterval time.Duration
+	pruningMu       sync.Mutex
+	prunedHeight    int64 // The cutoff height of the last pruning
 
-	// TECHDEBT: Need to implement context pooling (for writes), timeouts (for read & writes), etc...
 	writeContext *PostgresContext // only one write context is allowed at a time
 }
 
@@ -48,36 +62,76 @@ func Create(configPath, genesisPath string) (modules.PersistenceModule, error) {
 		return nil, err
 	}
 	genesis := g.(*types.PersistenceGenesisState)
-	conn, err := connectToDatabase(cfg.GetPostgresUrl(), cfg.GetNodeSchema())
+	pruning, err := newPruningStrategy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	pruningIntervalMsec := cfg.GetPruningIntervalMsec()
+	if pruningIntervalMsec == 0 {
+		pruningIntervalMsec = defaultPruningIntervalMsec
+	}
+
+	db, err := openDatabase(context.Background(), cfg)
 	if err != nil {
 		return nil, err
 	}
-	if err := initializeDatabase(conn); err != nil {
+	if err := initializeDatabase(context.Background(), db); err != nil {
 		return nil, err
 	}
-	conn.Close(context.TODO())
 
-	blockStore, err := initializeBlockStore(cfg.GetBlockStorePath())
+	blockKVStore, err := initializeBlockStore(cfg.GetBlockStorePath())
+	if err != nil {
+		return nil, err
+	}
+	blockStore := newBlockStore(blockKVStore)
+	if numMigrated, err := blockStore.migrateLegacyKeys(); err != nil {
+		return nil, fmt.Errorf("failed to migrate the block store: %w", err)
+	} else if numMigrated > 0 {
+		log.Printf("Migrated %d blocks stored by a previous version of the node to the current block store layout\n", numMigrated)
+	}
+
+	treeStore, err := initializeBlockStore(cfg.GetTreeStorePath())
+	if err != nil {
+		return nil, err
+	}
+	stateTrees, err := newStateTrees(treeStore)
+	if err != nil {
+		return nil, err
+	}
+
+	txIndexerStore, err := initializeBlockStore(cfg.GetTxIndexerPath())
 	if err != nil {
 		return nil, err
 	}
 
 	persistenceMod := &PersistenceModule{
-		bus:          nil,
-		postgresURL:  cfg.GetPostgresUrl(),
-		nodeSchema:   cfg.GetNodeSchema(),
-		genesisPath:  genesisPath,
-		blockStore:   blockStore,
-		writeContext: nil,
+		bus:             nil,
+		db:              db,
+		contextTimeout:  time.Duration(cfg.GetContextTimeoutMsec()) * time.Millisecond,
+		stopped:         make(chan struct{}),
+		genesisPath:     genesisPath,
+		blockStore:      blockStore,
+		treeStore:       treeStore,
+		stateTrees:      stateTrees,
+		txIndexer:       newTxIndexer(txIndexerStore),
+		pruning:         pruning,
+		pruningInterval: time.Duration(pruningIntervalMsec) * time.Millisecond,
+		writeContext:    nil,
+	}
+
+	if err := persistenceMod.reconcileBlockStore(); err != nil {
+		return nil, err
+	}
+
+	if err := persistenceMod.syncStateTrees(); err != nil {
+		return nil, err
 	}
 
 	// Determine if we should hydrate the genesis db or use the current state of the DB attached
 	if shouldHydrateGenesis, err := persistenceMod.shouldHydrateGenesisDb(); err != nil {
 		return nil, err
 	} else if shouldHydrateGenesis {
-		// TECHDEBT: reconsider if this is the best place to call `populateGenesisState`. Note that
-		// 		     this forces the genesis state to be reloaded on every node startup until state sync is
-		//           implemented.
+		// TECHDEBT: reconsider if this is the best place to call `populateGenesisState`.
 		// NOTE: `populateGenesisState` does not return an error but logs a fatal error if there's a problem
 		persistenceMod.populateGenesisState(genesis)
 	} else {
@@ -121,14 +175,40 @@ func (m *PersistenceModule) InitGenesis(pathToGenesisJSON string) (genesis modul
 
 func (m *PersistenceModule) Start() error {
 	log.Println("Starting persistence module...")
+	// The bus is not set when the module is used on its own (e.g. in the utility tests)
+	if m.bus != nil {
+		m.registerPoolMetrics()
+		m.runInBackground(m.reportPoolMetrics)
+	}
+	if m.pruning != nil {
+		m.runInBackground(m.runPruning)
+	}
 	return nil
 }
 
 func (m *PersistenceModule) Stop() error {
-	m.blockStore.Stop()
+	// Stopping the module more than once is a no-op
+	m.stopOnce.Do(func() {
+		close(m.stopped)
+		// An ongoing pruning run is waited for so it does not use the database once it is closed
+		m.background.Wait()
+		m.blockStore.store.Stop()
+		m.treeStore.Stop()
+		m.txIndexer.close()
+		m.db.close()
+	})
 	return nil
 }
 
+// runInBackground runs `fn` in a goroutine which `Stop` waits for.
+func (m *PersistenceModule) runInBackground(fn func()) {
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		fn()
+	}()
+}
+
 func (m *PersistenceModule) GetModuleName() string {
 	return PersistenceModuleName
 }
@@ -145,69 +225,114 @@ func (m *PersistenceModule) GetBus() modules.Bus {
 }
 
 func (m *PersistenceModule) NewRWContext(height int64) (modules.PersistenceRWContext, error) {
-	if m.writeContext != nil && !m.writeContext.conn.IsClosed() {
-		return nil, fmt.Errorf("write context already exists")
-	}
-	conn, err := connectToDatabase(m.postgresURL, m.nodeSchema)
-	if err != nil {
-		return nil, err
+	return m.NewRWContextWithCtx(context.Background(), height)
+}
+
+func (m *PersistenceModule) NewRWContextWithCtx(ctx context.Context, height int64) (modules.PersistenceRWContext, error) {
+	if m.writeContext != nil {
+		if m.writeContext.isOpen() {
+			return nil, fmt.Errorf("write context already exists")
+		}
+		// The previous write context may have expired without being committed or released
+		m.writeContext.releaseConn()
 	}
-	tx, err := conn.BeginTx(context.TODO(), pgx.TxOptions{
-		IsoLevel:       pgx.ReadUncommitted,
-		AccessMode:     pgx.ReadWrite,
-		DeferrableMode: pgx.Deferrable, // TODO(andrew): Research if this should be `Deferrable`
-	})
+
+	writeContext, err := m.newPostgresContext(ctx, height, false)
 	if err != nil {
 		return nil, err
 	}
 
-	m.writeContext = &PostgresContext{
-		Height:     height,
-		conn:       conn,
-		tx:         tx,
-		blockstore: m.blockStore,
-	}
+	// Drop whatever a previous write context left behind without being committed or released
+	m.stateTrees.discard()
+	m.txIndexer.discard()
+	m.blockStore.discard()
 
+	m.writeContext = writeContext
 	return *m.writeContext, nil
-
 }
 
 func (m *PersistenceModule) NewReadContext(height int64) (modules.PersistenceReadContext, error) {
-	conn, err := connectToDatabase(m.postgresURL, m.nodeSchema)
+	return m.NewReadContextWithCtx(context.Background(), height)
+}
+
+func (m *PersistenceModule) NewReadContextWithCtx(ctx context.Context, height int64) (modules.PersistenceReadContext, error) {
+	readContext, err := m.newPostgresContext(ctx, height, true)
 	if err != nil {
 		return nil, err
 	}
-	tx, err := conn.BeginTx(context.TODO(), pgx.TxOptions{
-		IsoLevel:       pgx.ReadCommitted,
-		AccessMode:     pgx.ReadOnly,
-		DeferrableMode: pgx.NotDeferrable, // TODO(andrew): Research if this should be `Deferrable`
-	})
+	return *readContext, nil
+}
+
+// newPostgresContext acquires a connection from the pool and begins a transaction on it. The context is bound
+// to `ctx`, so cancelling the latter aborts the queries of the context.
+func (m *PersistenceModule) newPostgresContext(ctx context.Context, height int64, readOnly bool) (*PostgresContext, error) {
+	var cancel context.CancelFunc
+	if m.contextTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, m.contextTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	tx, err := m.db.beginTx(ctx, readOnly)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	return PostgresContext{
+	return &PostgresContext{
 		Height:     height,
-		conn:       conn,
 		tx:         tx,
-		blockstore: m.blockStore,
+		ctx:        ctx,
+		cancel:     cancel,
+		blockStore: m.blockStore,
+		stateTrees: m.stateTrees,
+		txIndexer:  m.txIndexer,
+		isReadOnly: readOnly,
 	}, nil
 }
 
 func (m *PersistenceModule) ResetContext() error {
 	if m.writeContext != nil {
-		if !m.writeContext.GetTx().Conn().IsClosed() {
+		if m.writeContext.isOpen() {
 			if err := m.writeContext.Release(); err != nil {
 				log.Println("[TODO][ERROR] Error releasing write context...", err)
 			}
 		}
+		m.writeContext.releaseConn()
 		m.writeContext = nil
 	}
 	return nil
 }
 
 func (m *PersistenceModule) GetBlockStore() kvstore.KVStore {
-	return m.blockStore
+	return m.blockStore.store
+}
+
+func (m *PersistenceModule) GetBlockByHeight(height uint64) ([]byte, error) {
+	return m.blockStore.getByHeight(int64(height))
+}
+
+func (m *PersistenceModule) GetBlockByHash(hash string) ([]byte, error) {
+	return m.blockStore.getByHash(hash)
+}
+
+func (m *PersistenceModule) IterateBlocks(fromHeight, toHeight uint64, fn func(height uint64, blockProtoBytes []byte) bool) error {
+	return m.blockStore.iterate(int64(fromHeight), int64(toHeight), func(height int64, blockProtoBytes []byte) bool {
+		return fn(uint64(height), blockProtoBytes)
+	})
+}
+
+func (m *PersistenceModule) GetLatestBlock() (height uint64, blockProtoBytes, quorumCert []byte, err error) {
+	latestHeight, blockProtoBytes, quorumCert, err := m.blockStore.getLatest()
+	return uint64(latestHeight), blockProtoBytes, quorumCert, err
+}
+
+func (m *PersistenceModule) GetTransactionByHash(hash string) (indexer.TxResult, error) {
+	hashBz, err := hex.DecodeString(hash)
+	if err != nil {
+		return nil, err
+	}
+	return m.txIndexer.getByHash(hashBz)
 }
 
 func initializeBlockStore(blockStorePath string) (kvstore.KVStore, error) {
@@ -217,19 +342,85 @@ func initializeBlockStore(blockStorePath string) (kvstore.KVStore, error) {
 	return kvstore.NewKVStore(blockStorePath)
 }
 
-// TODO(drewsky): Simplify and externalize the logic for whether genesis should be populated and
-// move the if logic out of this file.
-func (m *PersistenceModule) shouldHydrateGenesisDb() (bool, error) {
+// reconcileBlockStore deletes the blocks above the latest one in the database, which were written to the block
+// store without their state being committed (e.g. by a previous version of the node), and fails if the latest
+// block in the database is missing from the block store (i.e. the node stopped while committing it).
+func (m *PersistenceModule) reconcileBlockStore() error {
 	checkContext, err := m.NewReadContext(-1)
 	if err != nil {
-		return false, err
+		return err
+	}
+	maxHeight, err := checkContext.(PostgresContext).getMaxBlockHeight()
+	checkContext.Close()
+	if err != nil {
+		return err
 	}
-	defer checkContext.Close()
 
-	maxHeight, err := checkContext.GetLatestBlockHeight()
-	if err == nil || maxHeight == 0 {
-		return true, nil
+	numDeleted, err := m.blockStore.deleteAbove(maxHeight)
+	if err != nil {
+		return err
+	}
+	if numDeleted > 0 {
+		log.Printf("[WARN] Deleted %d blocks above height %d from the block store, whose state was not committed\n", numDeleted, maxHeight)
 	}
 
-	return m.blockStore.Exists(heightToBytes(int64(maxHeight)))
+	if maxHeight < 0 {
+		return nil
+	}
+	if exists, err := m.blockStore.exists(maxHeight); err != nil {
+		return err
+	} else if !exists {
+		// NOTE: The block cannot be rebuilt from its row in the database, which does not hold its transactions.
+		// TODO: Fetch the missing block from the peers once state sync is implemented
+		return fmt.Errorf("the block at height %d is committed to the database but missing from the block store, restore the block store from a backup or resync the node", maxHeight)
+	}
+	return nil
+}
+
+// syncStateTrees updates the merkle trees with the heights committed to the database but not to the tree
+// store (e.g. if the latter is in memory) so the app hash commits to the whole state.
+func (m *PersistenceModule) syncStateTrees() error {
+	checkContext, err := m.NewReadContext(-1)
+	if err != nil {
+		return err
+	}
+	latestHeight, err := checkContext.GetLatestBlockHeight()
+	checkContext.Close()
+	if err != nil {
+		// There are no blocks in the database yet
+		return nil
+	}
+
+	rootsHeight, err := m.stateTrees.getLatestRootsHeight()
+	if err != nil {
+		return err
+	}
+	for height := rootsHeight + 1; height <= int64(latestHeight); height++ {
+		readContext, err := m.NewReadContext(height)
+		if err != nil {
+			return err
+		}
+		_, err = m.stateTrees.update(readContext.(PostgresContext))
+		readContext.Close()
+		if err != nil {
+			return err
+		}
+		if err := m.stateTrees.commit(height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shouldHydrateGenesisDb reports whether no state was committed yet, once the state trees are synced with the
+// database. Hydrating the genesis state on top of the committed state would write it at height 0 over the latest
+// trees and change the app hash.
+// TODO(drewsky): Simplify and externalize the logic for whether genesis should be populated and
+// move the if logic out of this file.
+func (m *PersistenceModule) shouldHydrateGenesisDb() (bool, error) {
+	rootsHeight, err := m.stateTrees.getLatestRootsHeight()
+	if err != nil {
+		return false, err
+	}
+	return rootsHeight < 0, nil
 }
@@ -0,0 +1,192 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgconn/stmtcache"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pokt-network/pocket/persistence/types"
+	"github.com/pokt-network/pocket/shared/modules"
+)
+
+const (
+	// Upper bound of the distinct queries built by `persistence/types`
+	statementCacheCapacity = 256
+
+	CreateSchema    = "CREATE SCHEMA"
+	SetSearchPathTo = "SET search_path TO"
+
+	IfNotExists = "IF NOT EXISTS"
+)
+
+var _ database = &postgresDatabase{}
+
+type postgresDatabase struct {
+	pool *pgxpool.Pool
+}
+
+func connectToPostgres(ctx context.Context, cfg modules.PersistenceConfig) (database, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.GetPostgresUrl())
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the database url: %v", err)
+	}
+	poolConfig.MaxConns = defaultMaxConnsCount
+	if maxConns := cfg.GetMaxConnsCount(); maxConns > 0 {
+		poolConfig.MaxConns = maxConns
+	}
+	poolConfig.MinConns = cfg.GetMinConnsCount()
+	statementTimeoutMsec := cfg.GetStatementTimeoutMsec()
+	if statementTimeoutMsec == 0 {
+		statementTimeoutMsec = defaultStatementTimeoutMsec
+	}
+	// The statement timeout is enforced by the database so the queries of every connection are bounded
+	poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatUint(statementTimeoutMsec, 10)
+	// The values of the queries are bound as arguments so their SQL is constant, which means each of them is
+	// prepared once per connection and the prepared statement is reused by the subsequent calls.
+	poolConfig.ConnConfig.BuildStatementCache = func(conn *pgconn.PgConn) stmtcache.Cache {
+		return stmtcache.New(conn, stmtcache.ModePrepare, statementCacheCapacity)
+	}
+
+	schema := cfg.GetNodeSchema()
+	poolConfig.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		// Creating and setting a new schema so we can run multiple nodes on one postgres instance.
+		// See more details at https://github.com/go-pg/pg/issues/351.
+		if _, err := conn.Exec(ctx, fmt.Sprintf("%s %s %s", CreateSchema, IfNotExists, schema)); err != nil {
+			return err
+		}
+		if _, err := conn.Exec(ctx, fmt.Sprintf("%s %s", SetSearchPathTo, schema)); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to database: %v", err)
+	}
+	return &postgresDatabase{pool: pool}, nil
+}
+
+func (db *postgresDatabase) beginTx(ctx context.Context, readOnly bool) (SQLTx, error) {
+	txOptions := pgx.TxOptions{
+		IsoLevel:       pgx.ReadUncommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.Deferrable, // TODO(andrew): Research if this should be `Deferrable`
+	}
+	if readOnly {
+		txOptions = pgx.TxOptions{
+			IsoLevel:       pgx.ReadCommitted,
+			AccessMode:     pgx.ReadOnly,
+			DeferrableMode: pgx.NotDeferrable, // TODO(andrew): Research if this should be `Deferrable`
+		}
+	}
+
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.BeginTx(ctx, txOptions)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+	return &postgresTx{tx: tx, conn: conn}, nil
+}
+
+func (db *postgresDatabase) beginMigrationTx(ctx context.Context) (SQLTx, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The table is created before the transaction begins so it can be locked
+	if _, err := conn.Exec(ctx, types.CreateSchemaMigrationsTableQuery(db.backend())); err != nil {
+		conn.Release()
+		return nil, err
+	}
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, types.LockSchemaMigrationsTableQuery()); err != nil {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			log.Printf("[WARN] Error rolling back the migration transaction: %v\n", rollbackErr)
+		}
+		conn.Release()
+		return nil, err
+	}
+	return &postgresTx{tx: tx, conn: conn}, nil
+}
+
+func (db *postgresDatabase) backend() string {
+	return types.PostgresBackend
+}
+
+func (db *postgresDatabase) stats() poolStats {
+	stat := db.pool.Stat()
+	return poolStats{
+		acquiredConns:   int64(stat.AcquiredConns()),
+		idleConns:       int64(stat.IdleConns()),
+		totalConns:      int64(stat.TotalConns()),
+		emptyAcquires:   stat.EmptyAcquireCount(),
+		acquireDuration: stat.AcquireDuration(),
+	}
+}
+
+func (db *postgresDatabase) close() {
+	db.pool.Close()
+}
+
+var _ SQLTx = &postgresTx{}
+
+type postgresTx struct {
+	tx   pgx.Tx
+	conn *pgxpool.Conn // nil for pseudo nested transactions, which do not own their connection
+}
+
+func (t *postgresTx) Exec(ctx context.Context, sql string, args ...any) (int64, error) {
+	commandTag, err := t.tx.Exec(ctx, sql, args...)
+	return commandTag.RowsAffected(), err
+}
+
+func (t *postgresTx) Query(ctx context.Context, sql string, args ...any) (SQLRows, error) {
+	return t.tx.Query(ctx, sql, args...)
+}
+
+func (t *postgresTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return t.tx.QueryRow(ctx, sql, args...)
+}
+
+func (t *postgresTx) Begin(ctx context.Context) (SQLTx, error) {
+	tx, err := t.tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresTx{tx: tx}, nil
+}
+
+func (t *postgresTx) Commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+func (t *postgresTx) Rollback(ctx context.Context) error {
+	return t.tx.Rollback(ctx)
+}
+
+// release returns the connection to the pool, rolling back the transaction first if it is still open since the pool
+// would otherwise hand out a connection in the middle of a transaction.
+func (t *postgresTx) release() {
+	if t.conn == nil {
+		return
+	}
+	if err := t.tx.Rollback(context.Background()); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		log.Printf("[WARN] Error rolling back the transaction before releasing its connection: %v\n", err)
+	}
+	t.conn.Release()
+}
@@ -0,0 +1,173 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pokt-network/pocket/persistence/types"
+	"github.com/pokt-network/pocket/shared/modules"
+)
+
+const defaultPruningIntervalMsec = 60000
+
+// pruningStrategy determines the heights whose historical state is kept. The state at the heights below
+// `cutoffHeight` is pruned, except at the multiples of `keepEvery` if it is not zero, but the latest version of
+// every record is always kept. Queries at the pruned heights are not supported.
+type pruningStrategy struct {
+	keepRecent int64 // The number of recent heights whose state is kept
+	keepEvery  int64 // The interval of the heights whose state is kept below the recent ones; none if zero
+}
+
+// newPruningStrategy returns nil if nothing is pruned.
+func newPruningStrategy(cfg modules.PersistenceConfig) (*pruningStrategy, error) {
+	keepRecent, keepEvery := int64(cfg.GetPruningKeepRecent()), int64(cfg.GetPruningKeepEvery())
+	switch strategy := cfg.GetPruningStrategy(); strategy {
+	case "", types.PruneNothing:
+		return nil, nil
+	case types.PruneKeepRecent:
+		if keepRecent <= 0 {
+			return nil, fmt.Errorf("pruning_keep_recent must be positive with the %s pruning strategy", strategy)
+		}
+		return &pruningStrategy{keepRecent: keepRecent}, nil
+	case types.PruneKeepEvery:
+		if keepEvery <= 0 {
+			return nil, fmt.Errorf("pruning_keep_every must be positive with the %s pruning strategy", strategy)
+		}
+		// The state at the latest height is kept regardless
+		if keepRecent <= 0 {
+			keepRecent = 1
+		}
+		return &pruningStrategy{keepRecent: keepRecent, keepEvery: keepEvery}, nil
+	default:
+		return nil, fmt.Errorf("unknown pruning strategy: %s", strategy)
+	}
+}
+
+// cutoffHeight returns the lowest of the recent heights whose state is kept.
+func (s *pruningStrategy) cutoffHeight(latestHeight int64) int64 {
+	return latestHeight - s.keepRecent + 1
+}
+
+// runPruning prunes the historical state on an interval until the module is stopped.
+func (m *PersistenceModule) runPruning() {
+	ticker := time.NewTicker(m.pruningInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopped:
+			return
+		case <-ticker.C:
+		}
+
+		if err := m.Prune(); err != nil {
+			log.Printf("[ERROR] Unable to prune the historical state: %v\n", err)
+		}
+	}
+}
+
+// Prune deletes the historical state which the configured pruning strategy does not keep, as of the latest
+// committed height. It is run in the background once the module is started.
+//
+// Each table is pruned in its own transaction, rather than in the write context, so the commits of the latter are
+// not blocked by the pruning. This is safe since only the rows superseded at the committed heights are deleted,
+// while the write context only reads the latest version of the records it updates.
+//
+// NOTE: SQLite allows a single write transaction at a time, which the write context holds from when it is created
+// until it is committed or released. The pruning transactions wait for it up to the busy timeout (i.e. the
+// statement timeout) and, if it is still open, the pruning is deferred to the next run. Conversely, a write context
+// created while a table is being pruned waits for that table's transaction to complete.
+func (m *PersistenceModule) Prune() error {
+	if m.pruning == nil {
+		return nil
+	}
+	m.pruningMu.Lock()
+	defer m.pruningMu.Unlock()
+
+	checkContext, err := m.NewReadContext(-1)
+	if err != nil {
+		return err
+	}
+	latestHeight, err := checkContext.(PostgresContext).getMaxBlockHeight()
+	checkContext.Close()
+	if err != nil {
+		return err
+	}
+
+	cutoffHeight := m.pruning.cutoffHeight(latestHeight)
+	if cutoffHeight <= m.prunedHeight {
+		return nil
+	}
+
+	numPruned, err := m.prune(cutoffHeight)
+	if isSQLiteBusyError(err) {
+		log.Printf("[WARN] Deferring the pruning of the historical state below height %d to the next run since the database is locked by the write context: %v\n", cutoffHeight, err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	m.prunedHeight = cutoffHeight
+	if numPruned > 0 {
+		log.Printf("Pruned %d rows of the historical state below height %d\n", numPruned, cutoffHeight)
+	}
+	return nil
+}
+
+// prune deletes the historical state below the cutoff height which the pruning strategy does not keep, and returns
+// the number of rows deleted.
+func (m *PersistenceModule) prune(cutoffHeight int64) (numPruned int64, err error) {
+	for _, table := range types.PrunedTables {
+		query, args := types.PruneQuery(table, cutoffHeight, m.pruning.keepEvery)
+		n, err := m.execPruneQueries(pruneQuery{query, args})
+		if err != nil {
+			return numPruned, fmt.Errorf("unable to prune the %s table: %w", table.TableName, err)
+		}
+		numPruned += n
+	}
+	for _, actor := range protocolActorSchemas {
+		queries := make([]pruneQuery, 0, 2)
+		query, args := types.PruneQuery(types.PrunedTable{TableName: actor.GetTableName(), KeyCol: types.AddressCol}, cutoffHeight, m.pruning.keepEvery)
+		queries = append(queries, pruneQuery{query, args})
+		if actor.GetChainsTableName() != "" {
+			query, args = types.PruneChainsQuery(actor.GetTableName(), actor.GetChainsTableName(), cutoffHeight)
+			queries = append(queries, pruneQuery{query, args})
+		}
+		n, err := m.execPruneQueries(queries...)
+		if err != nil {
+			return numPruned, fmt.Errorf("unable to prune the %s table: %w", actor.GetTableName(), err)
+		}
+		numPruned += n
+	}
+	return numPruned, nil
+}
+
+type pruneQuery struct {
+	sql  string
+	args []any
+}
+
+// execPruneQueries runs the queries in a transaction of their own and returns the number of rows they deleted.
+func (m *PersistenceModule) execPruneQueries(queries ...pruneQuery) (numDeleted int64, err error) {
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if m.contextTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, m.contextTimeout)
+	}
+	defer cancel()
+
+	tx, err := m.db.beginTx(ctx, false)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.release()
+
+	for _, query := range queries {
+		n, err := tx.Exec(ctx, query.sql, query.args...)
+		if err != nil {
+			return 0, err
+		}
+		numDeleted += n
+	}
+	return numDeleted, tx.Commit(ctx)
+}
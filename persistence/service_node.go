@@ -24,6 +24,10 @@ func (p PostgresContext) GetServiceNode(address []byte, height int64) (operator,
 	return
 }
 
+func (p PostgresContext) GetServiceNodeWithProof(address []byte, height int64) (modules.Actor, []byte, error) {
+	return p.getActorWithProof(types.ServiceNodeMerkleTree, address, height)
+}
+
 func (p PostgresContext) InsertServiceNode(address []byte, publicKey []byte, output []byte, _ bool, _ int32, serviceURL string, stakedTokens string, chains []string, pausedHeight int64, unstakingHeight int64) error {
 	return p.InsertActor(types.ServiceNodeActor, types.BaseActor{
 		Address:            hex.EncodeToString(address),
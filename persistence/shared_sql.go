@@ -37,7 +37,8 @@ func (p *PostgresContext) GetExists(actorSchema types.ProtocolActorSchema, addre
 		return
 	}
 
-	if err = tx.QueryRow(ctx, actorSchema.GetExistsQuery(hex.EncodeToString(address), height)).Scan(&exists); err != nil {
+	query, args := actorSchema.GetExistsQuery(hex.EncodeToString(address), height)
+	if err = tx.QueryRow(ctx, query, args...).Scan(&exists); err != nil {
 		return
 	}
 
@@ -49,7 +50,8 @@ func (p *PostgresContext) GetActor(actorSchema types.ProtocolActorSchema, addres
 	if err != nil {
 		return
 	}
-	actor, height, err = p.GetActorFromRow(tx.QueryRow(ctx, actorSchema.GetQuery(hex.EncodeToString(address), height)))
+	query, args := actorSchema.GetQuery(hex.EncodeToString(address), height)
+	actor, height, err = p.GetActorFromRow(tx.QueryRow(ctx, query, args...))
 	if err != nil {
 		return
 	}
@@ -57,6 +59,24 @@ func (p *PostgresContext) GetActor(actorSchema types.ProtocolActorSchema, addres
 	return p.GetChainsForActor(ctx, tx, actorSchema, actor, height)
 }
 
+// getActorWithProof returns the actor, or nil if it does not exist, along with the proof of its leaf in the
+// merkle tree of its type.
+func (p PostgresContext) getActorWithProof(tree types.MerkleTree, address []byte, height int64) (actor modules.Actor, proof []byte, err error) {
+	actorSchema, actorType := actorMerkleTrees[tree].actorSchema, actorMerkleTrees[tree].actorType
+	baseActor, err := p.GetActor(actorSchema, address, height)
+	switch {
+	case err == pgx.ErrNoRows:
+		actor = nil
+	case err != nil:
+		return nil, nil, err
+	default:
+		actor = p.BaseActorToActor(baseActor, actorType)
+	}
+
+	proof, err = p.stateTrees.prove(tree, address, height)
+	return
+}
+
 func (p *PostgresContext) GetActorFromRow(row pgx.Row) (actor types.BaseActor, height int64, err error) {
 	err = row.Scan(
 		&actor.Address, &actor.PublicKey, &actor.StakedTokens, &actor.ActorSpecificParam,
@@ -67,14 +87,15 @@ func (p *PostgresContext) GetActorFromRow(row pgx.Row) (actor types.BaseActor, h
 
 func (p *PostgresContext) GetChainsForActor(
 	ctx context.Context,
-	tx pgx.Tx,
+	tx SQLTx,
 	actorSchema types.ProtocolActorSchema,
 	actor types.BaseActor,
 	height int64) (a types.BaseActor, err error) {
 	if actorSchema.GetChainsTableName() == "" {
 		return actor, nil
 	}
-	rows, err := tx.Query(ctx, actorSchema.GetChainsQuery(actor.Address, height))
+	query, args := actorSchema.GetChainsQuery(actor.Address, height)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return actor, err
 	}
@@ -107,10 +128,18 @@ func (p *PostgresContext) InsertActor(actorSchema types.ProtocolActorSchema, act
 		return err
 	}
 
-	_, err = tx.Exec(ctx, actorSchema.InsertQuery(
+	query, args := actorSchema.InsertQuery(
 		actor.Address, actor.PublicKey, actor.StakedTokens, actor.ActorSpecificParam,
-		actor.OutputAddress, actor.PausedHeight, actor.UnstakingHeight, actor.Chains,
-		height))
+		actor.OutputAddress, actor.PausedHeight, actor.UnstakingHeight,
+		height)
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+
+	if actorSchema.GetChainsTableName() != "" && len(actor.Chains) > 0 {
+		query, args = actorSchema.UpdateChainsQuery(actor.Address, actor.Chains, height)
+		_, err = tx.Exec(ctx, query, args...)
+	}
 	return err
 }
 
@@ -125,17 +154,22 @@ func (p *PostgresContext) UpdateActor(actorSchema types.ProtocolActorSchema, act
 		return err
 	}
 
-	if _, err = tx.Exec(ctx, actorSchema.UpdateQuery(actor.Address, actor.StakedTokens, actor.ActorSpecificParam, height)); err != nil {
+	query, args := actorSchema.UpdateQuery(actor.Address, actor.StakedTokens, actor.ActorSpecificParam, height)
+	if _, err = tx.Exec(ctx, query, args...); err != nil {
 		return err
 	}
 
 	chainsTableName := actorSchema.GetChainsTableName()
 	if chainsTableName != "" && actor.Chains != nil {
-		if _, err = tx.Exec(ctx, types.NullifyChains(actor.Address, height, chainsTableName)); err != nil {
+		query, args := types.NullifyChains(actor.Address, height, chainsTableName)
+		if _, err = tx.Exec(ctx, query, args...); err != nil {
 			return err
 		}
-		if _, err = tx.Exec(ctx, actorSchema.UpdateChainsQuery(actor.Address, actor.Chains, height)); err != nil {
-			return err
+		if len(actor.Chains) > 0 {
+			query, args = actorSchema.UpdateChainsQuery(actor.Address, actor.Chains, height)
+			if _, err = tx.Exec(ctx, query, args...); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -148,7 +182,8 @@ func (p *PostgresContext) GetActorsReadyToUnstake(actorSchema types.ProtocolActo
 		return nil, err
 	}
 
-	rows, err := tx.Query(ctx, actorSchema.GetReadyToUnstakeQuery(height))
+	query, args := actorSchema.GetReadyToUnstakeQuery(height)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -175,7 +210,8 @@ func (p *PostgresContext) GetActorStatus(actorSchema types.ProtocolActorSchema,
 		return UndefinedStakingStatus, err
 	}
 
-	if err := tx.QueryRow(ctx, actorSchema.GetUnstakingHeightQuery(hex.EncodeToString(address), height)).Scan(&unstakingHeight); err != nil {
+	query, args := actorSchema.GetUnstakingHeightQuery(hex.EncodeToString(address), height)
+	if err := tx.QueryRow(ctx, query, args...).Scan(&unstakingHeight); err != nil {
 		return UndefinedStakingStatus, err
 	}
 
@@ -200,7 +236,8 @@ func (p *PostgresContext) SetActorUnstakingHeightAndStatus(actorSchema types.Pro
 		return err
 	}
 
-	_, err = tx.Exec(ctx, actorSchema.UpdateUnstakingHeightQuery(hex.EncodeToString(address), unstakingHeight, height))
+	query, args := actorSchema.UpdateUnstakingHeightQuery(hex.EncodeToString(address), unstakingHeight, height)
+	_, err = tx.Exec(ctx, query, args...)
 	return err
 }
 
@@ -210,7 +247,8 @@ func (p *PostgresContext) GetActorPauseHeightIfExists(actorSchema types.Protocol
 		return types.DefaultBigInt, err
 	}
 
-	if err := tx.QueryRow(ctx, actorSchema.GetPausedHeightQuery(hex.EncodeToString(address), height)).Scan(&pausedHeight); err != nil {
+	query, args := actorSchema.GetPausedHeightQuery(hex.EncodeToString(address), height)
+	if err := tx.QueryRow(ctx, query, args...).Scan(&pausedHeight); err != nil {
 		return types.DefaultBigInt, err
 	}
 
@@ -228,7 +266,8 @@ func (p PostgresContext) SetActorStatusAndUnstakingHeightIfPausedBefore(actorSch
 		return err
 	}
 
-	_, err = tx.Exec(ctx, actorSchema.UpdateUnstakedHeightIfPausedBeforeQuery(pausedBeforeHeight, unstakingHeight, currentHeight))
+	query, args := actorSchema.UpdateUnstakedHeightIfPausedBeforeQuery(pausedBeforeHeight, unstakingHeight, currentHeight)
+	_, err = tx.Exec(ctx, query, args...)
 	return err
 }
 
@@ -243,7 +282,8 @@ func (p PostgresContext) SetActorPauseHeight(actorSchema types.ProtocolActorSche
 		return err
 	}
 
-	_, err = tx.Exec(ctx, actorSchema.UpdatePausedHeightQuery(hex.EncodeToString(address), pauseHeight, currentHeight))
+	query, args := actorSchema.UpdatePausedHeightQuery(hex.EncodeToString(address), pauseHeight, currentHeight)
+	_, err = tx.Exec(ctx, query, args...)
 	return err
 }
 
@@ -257,7 +297,8 @@ func (p PostgresContext) SetActorStakeAmount(actorSchema types.ProtocolActorSche
 	if err != nil {
 		return err
 	}
-	_, err = tx.Exec(ctx, actorSchema.SetStakeAmountQuery(hex.EncodeToString(address), stakeAmount, currentHeight))
+	query, args := actorSchema.SetStakeAmountQuery(hex.EncodeToString(address), stakeAmount, currentHeight)
+	_, err = tx.Exec(ctx, query, args...)
 	return err
 }
 
@@ -268,7 +309,8 @@ func (p PostgresContext) GetActorOutputAddress(actorSchema types.ProtocolActorSc
 	}
 
 	var outputAddr string
-	if err := tx.QueryRow(ctx, actorSchema.GetOutputAddressQuery(hex.EncodeToString(operatorAddr), height)).Scan(&outputAddr); err != nil {
+	query, args := actorSchema.GetOutputAddressQuery(hex.EncodeToString(operatorAddr), height)
+	if err := tx.QueryRow(ctx, query, args...).Scan(&outputAddr); err != nil {
 		return nil, err
 	}
 
@@ -282,7 +324,8 @@ func (p PostgresContext) GetActorStakeAmount(actorSchema types.ProtocolActorSche
 	}
 
 	var stakeAmount string
-	if err := tx.QueryRow(ctx, actorSchema.GetStakeAmountQuery(hex.EncodeToString(address), height)).Scan(&stakeAmount); err != nil {
+	query, args := actorSchema.GetStakeAmountQuery(hex.EncodeToString(address), height)
+	if err := tx.QueryRow(ctx, query, args...).Scan(&stakeAmount); err != nil {
 		return "", err
 	}
 	return stakeAmount, nil
@@ -0,0 +1,235 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pokt-network/pocket/persistence/types"
+	"github.com/pokt-network/pocket/shared/modules"
+	"modernc.org/sqlite" // registers the pure Go `sqlite` driver
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	sqliteDriverName   = "sqlite"
+	sqliteTempFileName = "persistence.db"
+)
+
+var _ database = &sqliteDatabase{}
+
+// sqliteDatabase backs the persistence module with an embedded SQLite database, so a node does not depend on a
+// Postgres instance. The database of a node is a file, which plays the role of the schema of the node in Postgres.
+//
+// SQLite allows a single writer at a time: write transactions lock the database as soon as they begin (i.e. they
+// are `IMMEDIATE`) while read transactions only see the writes committed before their first query.
+type sqliteDatabase struct {
+	db               *sql.DB
+	statementTimeout time.Duration
+	tempDir          string // Removed on close if the database is temporary
+}
+
+func openSQLite(ctx context.Context, cfg modules.PersistenceConfig) (database, error) {
+	path, tempDir := cfg.GetSqlitePath(), ""
+	if path == "" {
+		var err error
+		if tempDir, err = os.MkdirTemp("", "pocket-persistence-"); err != nil {
+			return nil, err
+		}
+		path = filepath.Join(tempDir, sqliteTempFileName)
+	}
+
+	statementTimeoutMsec := cfg.GetStatementTimeoutMsec()
+	if statementTimeoutMsec == 0 {
+		statementTimeoutMsec = defaultStatementTimeoutMsec
+	}
+	params := url.Values{}
+	// The write-ahead log lets read transactions proceed while the database is being written
+	params.Add("_pragma", "journal_mode(WAL)")
+	// Transactions wait for the database to be unlocked for as long as a statement can run
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", statementTimeoutMsec))
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open(sqliteDriverName, path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the sqlite database: %v", err)
+	}
+	maxConns := int(cfg.GetMaxConnsCount())
+	if maxConns <= 0 {
+		maxConns = defaultMaxConnsCount
+	}
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(maxConns)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to open the sqlite database: %v", err)
+	}
+
+	return &sqliteDatabase{
+		db:               db,
+		statementTimeout: time.Duration(statementTimeoutMsec) * time.Millisecond,
+		tempDir:          tempDir,
+	}, nil
+}
+
+func (db *sqliteDatabase) beginTx(ctx context.Context, readOnly bool) (SQLTx, error) {
+	tx, err := db.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: readOnly})
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteTx{tx: tx, statementTimeout: db.statementTimeout, savePointsCount: new(int)}, nil
+}
+
+// beginMigrationTx relies on write transactions locking the database, which already excludes other migrations.
+func (db *sqliteDatabase) beginMigrationTx(ctx context.Context) (SQLTx, error) {
+	tx, err := db.beginTx(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, types.CreateSchemaMigrationsTableQuery(db.backend())); err != nil {
+		tx.release()
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (db *sqliteDatabase) backend() string {
+	return types.SQLiteBackend
+}
+
+func (db *sqliteDatabase) stats() poolStats {
+	stat := db.db.Stats()
+	return poolStats{
+		acquiredConns:   int64(stat.InUse),
+		idleConns:       int64(stat.Idle),
+		totalConns:      int64(stat.OpenConnections),
+		emptyAcquires:   stat.WaitCount,
+		acquireDuration: stat.WaitDuration,
+	}
+}
+
+func (db *sqliteDatabase) close() {
+	db.db.Close()
+	if db.tempDir != "" {
+		os.RemoveAll(db.tempDir)
+	}
+}
+
+// isSQLiteBusyError returns whether the error is due to another write transaction locking the database for longer
+// than the busy timeout.
+func isSQLiteBusyError(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqlite3.SQLITE_BUSY
+}
+
+var _ SQLTx = &sqliteTx{}
+
+type sqliteTx struct {
+	tx *sql.Tx
+	// SQLite does not enforce a statement timeout, so every statement is bound to one instead
+	statementTimeout time.Duration
+	savePoint        string // The save point backing the transaction if it is pseudo nested
+	savePointsCount  *int   // Shared with the pseudo nested transactions to name their save points uniquely
+}
+
+func (t *sqliteTx) Exec(ctx context.Context, query string, args ...any) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.statementTimeout)
+	defer cancel()
+	result, err := t.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (t *sqliteTx) Query(ctx context.Context, query string, args ...any) (SQLRows, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.statementTimeout)
+	rows, err := t.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &sqliteRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (t *sqliteTx) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	rows, err := t.Query(ctx, query, args...)
+	return &sqliteRow{rows: rows, err: err}
+}
+
+func (t *sqliteTx) Begin(ctx context.Context) (SQLTx, error) {
+	*t.savePointsCount++
+	savePoint := fmt.Sprintf("nested_tx_%d", *t.savePointsCount)
+	if _, err := t.Exec(ctx, "SAVEPOINT "+savePoint); err != nil {
+		return nil, err
+	}
+	return &sqliteTx{
+		tx:               t.tx,
+		statementTimeout: t.statementTimeout,
+		savePoint:        savePoint,
+		savePointsCount:  t.savePointsCount,
+	}, nil
+}
+
+func (t *sqliteTx) Commit(ctx context.Context) error {
+	if t.savePoint == "" {
+		return t.tx.Commit()
+	}
+	_, err := t.Exec(ctx, "RELEASE SAVEPOINT "+t.savePoint)
+	return err
+}
+
+func (t *sqliteTx) Rollback(ctx context.Context) error {
+	if t.savePoint == "" {
+		return t.tx.Rollback()
+	}
+	if _, err := t.Exec(ctx, "ROLLBACK TO SAVEPOINT "+t.savePoint); err != nil {
+		return err
+	}
+	_, err := t.Exec(ctx, "RELEASE SAVEPOINT "+t.savePoint)
+	return err
+}
+
+// release rolls back the transaction, which returns its connection to the pool. Pseudo nested transactions do
+// not own their connection.
+func (t *sqliteTx) release() {
+	if t.savePoint == "" {
+		_ = t.tx.Rollback() // `sql.ErrTxDone` once committed or rolled back
+	}
+}
+
+type sqliteRows struct {
+	*sql.Rows
+	cancel context.CancelFunc
+}
+
+func (r *sqliteRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// sqliteRow mirrors `pgx.Row` so the callers handle a missing row the same way for both backends.
+type sqliteRow struct {
+	rows SQLRows
+	err  error
+}
+
+func (r *sqliteRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
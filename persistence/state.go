@@ -0,0 +1,455 @@
+package persistence
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/celestiaorg/smt"
+	"github.com/pokt-network/pocket/persistence/kvstore"
+	"github.com/pokt-network/pocket/persistence/types"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"google.golang.org/protobuf/proto"
+)
+
+var actorMerkleTrees = map[types.MerkleTree]struct {
+	actorSchema types.ProtocolActorSchema
+	actorType   types.ActorType
+}{
+	types.AppMerkleTree:         {types.ApplicationActor, types.ActorType_App},
+	types.ValMerkleTree:         {types.ValidatorActor, types.ActorType_Val},
+	types.FishMerkleTree:        {types.FishermanActor, types.ActorType_Fish},
+	types.ServiceNodeMerkleTree: {types.ServiceNodeActor, types.ActorType_Node},
+}
+
+var (
+	latestRootsHeightKey = []byte("latest_roots_height")
+	rootsKeyPrefix       = []byte("roots/")
+	// Written along with the roots so the stores committed by previous versions of the node, whose heights were
+	// little endian, can be told apart
+	bigEndianHeightsKey = []byte("big_endian_heights")
+)
+
+// stateTrees maintains the merkle trees of the state. The nodes written while updating the trees of the
+// write context are staged in memory until it is committed, so releasing the context leaves no trace.
+type stateTrees struct {
+	mu sync.Mutex
+
+	store *stagedKVStore
+	trees [types.NumMerkleTrees]*smt.SparseMerkleTree
+
+	emptyRoots     [types.NumMerkleTrees][]byte
+	committedRoots [types.NumMerkleTrees][]byte
+	updated        bool // Whether the trees reflect the state of the write context
+}
+
+func newStateTrees(treeStore kvstore.KVStore) (*stateTrees, error) {
+	if err := migrateLittleEndianHeights(treeStore); err != nil {
+		return nil, fmt.Errorf("failed to migrate the tree store: %w", err)
+	}
+
+	t := &stateTrees{
+		store: &stagedKVStore{store: treeStore, staged: make(map[string][]byte)},
+	}
+	for i := range t.trees {
+		name := types.MerkleTree(i).String()
+		nodes := &treeMapStore{store: t.store, prefix: []byte(name + "/nodes/"), isNodeStore: true}
+		values := &treeMapStore{store: t.store, prefix: []byte(name + "/values/")}
+		t.trees[i] = smt.NewSparseMerkleTree(nodes, values, types.NewMerkleTreeHasher())
+		t.emptyRoots[i] = t.trees[i].Root()
+	}
+	t.committedRoots = t.emptyRoots
+
+	height, err := t.getLatestRootsHeight()
+	if err != nil || height < 0 {
+		return t, err
+	}
+	roots, err := t.getRoots(height)
+	if err != nil {
+		return nil, err
+	}
+	t.committedRoots = roots
+	t.setRoots(roots)
+	return t, nil
+}
+
+// update recomputes the trees, on top of the committed ones, with the state written at the height of the
+// context and returns the resulting app hash. It can be called multiple times within the same context.
+func (t *stateTrees) update(p PostgresContext) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.setRoots(t.committedRoots)
+	for tree := types.MerkleTree(0); tree < types.NumMerkleTrees; tree++ {
+		var err error
+		switch tree {
+		case types.AccountMerkleTree:
+			query, args := types.SelectAccountsUpdatedAtHeight(p.Height)
+			err = p.updateAccountTree(t.trees[tree], query, args, true)
+		case types.PoolMerkleTree:
+			query, args := types.SelectPoolsUpdatedAtHeight(p.Height)
+			err = p.updateAccountTree(t.trees[tree], query, args, false)
+		case types.ParamsMerkleTree:
+			err = p.updateGovTree(t.trees[tree], types.ParamsTableName)
+		case types.FlagsMerkleTree:
+			err = p.updateGovTree(t.trees[tree], types.FlagsTableName)
+		default:
+			actor := actorMerkleTrees[tree]
+			err = p.updateActorTree(t.trees[tree], actor.actorSchema, actor.actorType)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to update the %s merkle tree: %w", tree, err)
+		}
+	}
+	t.updated = true
+
+	roots := t.getCurrentRoots()
+	return types.GetAppHash(roots[:]), nil
+}
+
+// migrateLittleEndianHeights re-keys the roots committed by previous versions of the node by their big endian
+// height. The whole store is migrated at once since the keys of both layouts cannot be told apart.
+func migrateLittleEndianHeights(treeStore kvstore.KVStore) error {
+	if migrated, err := treeStore.Exists(bigEndianHeightsKey); err != nil || migrated {
+		return err
+	}
+	heightBz, err := treeStore.Get(latestRootsHeightKey)
+	if errors.Is(err, kvstore.ErrKeyNotFound) {
+		// Nothing was committed yet
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	it, err := treeStore.PrefixIterator(rootsKeyPrefix, false)
+	if err != nil {
+		return err
+	}
+	batch := treeStore.NewBatch()
+	var migratedKeys, migratedRoots [][]byte
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		batch.Delete(key)
+		height := int64(binary.LittleEndian.Uint64(key[len(rootsKeyPrefix):]))
+		migratedKeys = append(migratedKeys, getRootsKey(height))
+		migratedRoots = append(migratedRoots, it.Value())
+	}
+	it.Close()
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	// The keys are put after all the deletions since the key of a migrated height may be the legacy one of another
+	for i, key := range migratedKeys {
+		batch.Put(key, migratedRoots[i])
+	}
+	latestHeight := int64(binary.LittleEndian.Uint64(heightBz))
+	batch.Put(latestRootsHeightKey, heightToBytes(latestHeight))
+	batch.Put(bigEndianHeightsKey, []byte{1})
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Printf("Migrated the merkle roots of %d heights, up to height %d, to big endian heights\n", len(migratedKeys), latestHeight)
+	return nil
+}
+
+func (t *stateTrees) isUpdated() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.updated
+}
+
+// commit writes the staged nodes and the roots of the trees at that height to the tree store.
+func (t *stateTrees) commit(height int64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	roots := t.getCurrentRoots()
+	t.store.stage(getRootsKey(height), bytes.Join(roots[:], nil))
+	t.store.stage(latestRootsHeightKey, heightToBytes(height))
+	t.store.stage(bigEndianHeightsKey, []byte{1})
+	if err := t.store.flush(); err != nil {
+		return err
+	}
+	t.committedRoots = roots
+	t.updated = false
+	return nil
+}
+
+// clear empties the trees along with the tree store.
+func (t *stateTrees) clear() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.store.discard()
+	if err := t.store.store.ClearAll(); err != nil {
+		return err
+	}
+	t.committedRoots = t.emptyRoots
+	t.setRoots(t.emptyRoots)
+	t.updated = false
+	return nil
+}
+
+// discard drops the changes made since the last commit.
+func (t *stateTrees) discard() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.store.discard()
+	t.setRoots(t.committedRoots)
+	t.updated = false
+}
+
+// prove returns a serialized `StateProof` of the key in the tree against the roots committed at that height.
+func (t *stateTrees) prove(tree types.MerkleTree, key []byte, height int64) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	roots, err := t.getRoots(height)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := t.trees[tree].ProveForRoot(key, roots[tree])
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(&types.StateProof{
+		SideNodes:             proof.SideNodes,
+		NonMembershipLeafData: proof.NonMembershipLeafData,
+		SiblingData:           proof.SiblingData,
+		TreeRoots:             roots[:],
+	})
+}
+
+func (t *stateTrees) setRoots(roots [types.NumMerkleTrees][]byte) {
+	for i, tree := range t.trees {
+		tree.SetRoot(roots[i])
+	}
+}
+
+func (t *stateTrees) getCurrentRoots() (roots [types.NumMerkleTrees][]byte) {
+	for i, tree := range t.trees {
+		roots[i] = tree.Root()
+	}
+	return
+}
+
+func (t *stateTrees) getLatestRootsHeight() (int64, error) {
+	heightBz, err := t.store.get(latestRootsHeightKey)
+	if err != nil || heightBz == nil {
+		return -1, err
+	}
+	return bytesToHeight(heightBz), nil
+}
+
+func (t *stateTrees) getRoots(height int64) (roots [types.NumMerkleTrees][]byte, err error) {
+	rootsBz, err := t.store.get(getRootsKey(height))
+	if err != nil {
+		return
+	}
+	if len(rootsBz) != int(types.NumMerkleTrees)*cryptoPocket.SHA3HashLen {
+		return roots, fmt.Errorf("no merkle roots found at height %d", height)
+	}
+	for i := range roots {
+		roots[i] = rootsBz[i*cryptoPocket.SHA3HashLen : (i+1)*cryptoPocket.SHA3HashLen]
+	}
+	return
+}
+
+func getRootsKey(height int64) []byte {
+	return append(append([]byte{}, rootsKeyPrefix...), heightToBytes(height)...)
+}
+
+func (p PostgresContext) updateAccountTree(tree *smt.SparseMerkleTree, query string, args []any, isHexKey bool) error {
+	ctx, tx, err := p.GetCtxAndTx()
+	if err != nil {
+		return err
+	}
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, amount string
+		if err := rows.Scan(&key, &amount); err != nil {
+			return err
+		}
+		keyBz := []byte(key)
+		if isHexKey {
+			if keyBz, err = hex.DecodeString(key); err != nil {
+				return err
+			}
+		}
+		if _, err := tree.Update(keyBz, []byte(amount)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (p PostgresContext) updateActorTree(tree *smt.SparseMerkleTree, actorSchema types.ProtocolActorSchema, actorType types.ActorType) error {
+	ctx, tx, err := p.GetCtxAndTx()
+	if err != nil {
+		return err
+	}
+	query, args := actorSchema.GetUpdatedAtHeightQuery(p.Height)
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	var actors []types.BaseActor
+	for rows.Next() {
+		actor, _, err := p.GetActorFromRow(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		actors = append(actors, actor)
+	}
+	rows.Close()
+
+	for _, actor := range actors {
+		actor, err = p.GetChainsForActor(ctx, tx, actorSchema, actor, p.Height)
+		if err != nil {
+			return err
+		}
+		actorBz, err := types.GetActorMerkleValue(p.BaseActorToActor(actor, actorType))
+		if err != nil {
+			return err
+		}
+		addr, err := hex.DecodeString(actor.Address)
+		if err != nil {
+			return err
+		}
+		if _, err := tree.Update(addr, actorBz); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p PostgresContext) updateGovTree(tree *smt.SparseMerkleTree, tableName string) error {
+	ctx, tx, err := p.GetCtxAndTx()
+	if err != nil {
+		return err
+	}
+	query, args := types.GetParamsOrFlagsUpdatedAtHeightQuery(tableName, p.Height)
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, value string
+		var enabled bool
+		if tableName == types.FlagsTableName {
+			err = rows.Scan(&name, &value, &enabled)
+			value = string(types.GetFlagMerkleValue(value, enabled))
+		} else {
+			err = rows.Scan(&name, &value)
+		}
+		if err != nil {
+			return err
+		}
+		// NOTE: An empty value removes the leaf, which is equivalent to the param not being set
+		if _, err := tree.Update([]byte(name), []byte(value)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// stagedKVStore buffers writes in memory until they are flushed to the underlying store.
+type stagedKVStore struct {
+	store  kvstore.KVStore
+	staged map[string][]byte // A nil value stages the deletion of the key
+}
+
+// get returns nil if the key does not exist.
+func (s *stagedKVStore) get(key []byte) ([]byte, error) {
+	if value, ok := s.staged[string(key)]; ok {
+		return value, nil
+	}
+	value, err := s.store.Get(key)
+	if errors.Is(err, kvstore.ErrKeyNotFound) {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (s *stagedKVStore) stage(key, value []byte) {
+	s.staged[string(key)] = append([]byte{}, value...)
+}
+
+func (s *stagedKVStore) stageDelete(key []byte) {
+	s.staged[string(key)] = nil
+}
+
+func (s *stagedKVStore) flush() error {
+	batch := s.store.NewBatch()
+	for key, value := range s.staged {
+		if value == nil {
+			batch.Delete([]byte(key))
+			continue
+		}
+		batch.Put([]byte(key), value)
+	}
+	if err := batch.Write(); err != nil {
+		log.Printf("[ERROR] Failed to flush the staged merkle tree entries: %v\n", err)
+		return err
+	}
+	s.discard()
+	return nil
+}
+
+func (s *stagedKVStore) discard() {
+	s.staged = make(map[string][]byte)
+}
+
+var _ smt.MapStore = &treeMapStore{}
+
+// treeMapStore exposes the nodes or the values of a merkle tree, namespaced by a prefix, as a `smt.MapStore`.
+type treeMapStore struct {
+	store       *stagedKVStore
+	prefix      []byte
+	isNodeStore bool
+}
+
+func (s *treeMapStore) Get(key []byte) ([]byte, error) {
+	value, err := s.store.get(s.prefixed(key))
+	if err != nil {
+		return nil, err
+	}
+	// Previous versions of the node wrote deleted values as empty ones
+	if len(value) == 0 {
+		return nil, &smt.InvalidKeyError{Key: key}
+	}
+	return value, nil
+}
+
+func (s *treeMapStore) Set(key []byte, value []byte) error {
+	s.store.stage(s.prefixed(key), value)
+	return nil
+}
+
+// Delete does not remove nodes: they are content addressed and kept so the roots of previous heights remain
+// provable.
+func (s *treeMapStore) Delete(key []byte) error {
+	if s.isNodeStore {
+		return nil
+	}
+	s.store.stageDelete(s.prefixed(key))
+	return nil
+}
+
+func (s *treeMapStore) prefixed(key []byte) []byte {
+	return append(append([]byte{}, s.prefix...), key...)
+}
@@ -0,0 +1,298 @@
+package test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	typesCons "github.com/pokt-network/pocket/consensus/types"
+	"github.com/pokt-network/pocket/persistence"
+	"github.com/pokt-network/pocket/persistence/kvstore"
+	"github.com/pokt-network/pocket/persistence/types"
+	"github.com/pokt-network/pocket/shared/codec"
+	"github.com/pokt-network/pocket/shared/crypto"
+	"github.com/pokt-network/pocket/shared/indexer"
+	"github.com/pokt-network/pocket/shared/modules"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreTransaction_IndexedOnCommit(t *testing.T) {
+	// Cleanup previous contexts
+	testPersistenceMod.ResetContext()
+	t.Cleanup(func() {
+		testPersistenceMod.ResetContext()
+	})
+
+	releasedTxResult := newTestTxResult("released")
+	committedTxResult := newTestTxResult("committed")
+
+	// a transaction stored by a released context is not indexed
+	db, err := testPersistenceMod.NewRWContext(1)
+	require.NoError(t, err)
+	require.NoError(t, db.StoreTransaction(releasedTxResult))
+	requireTransactionExists(t, db, releasedTxResult, true) // the write context sees what it stored
+	require.NoError(t, db.Release())
+
+	db, err = testPersistenceMod.NewRWContext(1)
+	require.NoError(t, err)
+	requireTransactionExists(t, db, releasedTxResult, false)
+
+	// a transaction stored by a committed context is indexed
+	require.NoError(t, db.StoreTransaction(committedTxResult))
+	readContext, err := testPersistenceMod.NewReadContext(1)
+	require.NoError(t, err)
+	requireTransactionExists(t, readContext, committedTxResult, false) // not until the context is committed
+	require.NoError(t, readContext.Close())
+	require.NoError(t, db.Commit())
+
+	readContext, err = testPersistenceMod.NewReadContext(1)
+	require.NoError(t, err)
+	defer readContext.Close()
+	requireTransactionExists(t, readContext, committedTxResult, true)
+	requireTransactionExists(t, readContext, releasedTxResult, false)
+
+	// read contexts cannot store transactions
+	require.Error(t, readContext.(persistence.PostgresContext).StoreTransaction(releasedTxResult))
+}
+
+func newTestTxResult(tx string) *indexer.DefaultTxResult {
+	return &indexer.DefaultTxResult{
+		Tx:          []byte(tx),
+		Height:      1,
+		Index:       0,
+		SignerAddr:  hex.EncodeToString([]byte("signer")),
+		MessageType: "MessageSend",
+	}
+}
+
+func requireTransactionExists(t *testing.T, db modules.PersistenceReadContext, txResult indexer.TxResult, expected bool) {
+	txExists, err := db.TransactionExists(hex.EncodeToString(crypto.SHA3Hash(txResult.GetTx())))
+	require.NoError(t, err)
+	require.Equal(t, expected, txExists)
+}
+
+func TestBlockStore_Queries(t *testing.T) {
+	// Cleanup previous contexts
+	testPersistenceMod.ResetContext()
+	t.Cleanup(func() {
+		testPersistenceMod.ResetContext()
+		testPersistenceMod.GetBlockStore().ClearAll()
+	})
+	require.NoError(t, testPersistenceMod.GetBlockStore().ClearAll())
+
+	// store the blocks at heights 1 to 3, and then at height 256 whose little endian encoding would sort first
+	heights := []uint64{1, 2, 3, 256}
+	for _, height := range heights {
+		db, err := testPersistenceMod.NewRWContext(int64(height))
+		require.NoError(t, err)
+		require.NoError(t, db.StoreBlock(testBlockBytes(height), testBlockHash(height), testQuorumCert(height)))
+		require.NoError(t, db.Commit())
+	}
+
+	block, err := testPersistenceMod.GetBlockByHeight(2)
+	require.NoError(t, err)
+	require.Equal(t, testBlockBytes(2), block)
+
+	block, err = testPersistenceMod.GetBlockByHash(testBlockHash(3))
+	require.NoError(t, err)
+	require.Equal(t, testBlockBytes(3), block)
+
+	_, err = testPersistenceMod.GetBlockByHash(testBlockHash(4))
+	require.Error(t, err)
+
+	latestHeight, block, quorumCert, err := testPersistenceMod.GetLatestBlock()
+	require.NoError(t, err)
+	require.Equal(t, uint64(256), latestHeight)
+	require.Equal(t, testBlockBytes(256), block)
+	require.Equal(t, testQuorumCert(256), quorumCert)
+
+	var iteratedHeights []uint64
+	require.NoError(t, testPersistenceMod.IterateBlocks(2, 256, func(height uint64, blockProtoBytes []byte) bool {
+		require.Equal(t, testBlockBytes(height), blockProtoBytes)
+		iteratedHeights = append(iteratedHeights, height)
+		return true
+	}))
+	require.Equal(t, []uint64{2, 3, 256}, iteratedHeights)
+
+	// the iteration stops once the callback returns false
+	iteratedHeights = nil
+	require.NoError(t, testPersistenceMod.IterateBlocks(0, 1000, func(height uint64, _ []byte) bool {
+		iteratedHeights = append(iteratedHeights, height)
+		return height < 2
+	}))
+	require.Equal(t, []uint64{1, 2}, iteratedHeights)
+}
+
+func TestStoreBlock_WrittenOnCommit(t *testing.T) {
+	// Cleanup previous contexts
+	testPersistenceMod.ResetContext()
+	t.Cleanup(func() {
+		testPersistenceMod.ResetContext()
+		testPersistenceMod.GetBlockStore().ClearAll()
+	})
+	require.NoError(t, testPersistenceMod.GetBlockStore().ClearAll())
+
+	// a block stored by a released context is not written
+	db, err := testPersistenceMod.NewRWContext(1)
+	require.NoError(t, err)
+	require.NoError(t, db.StoreBlock(testBlockBytes(1), testBlockHash(1), testQuorumCert(1)))
+	_, err = testPersistenceMod.GetBlockByHeight(1)
+	require.Error(t, err) // not until the context is committed
+	require.NoError(t, db.Release())
+	_, err = testPersistenceMod.GetBlockByHash(testBlockHash(1))
+	require.Error(t, err)
+
+	// a block stored by a committed context is written
+	db, err = testPersistenceMod.NewRWContext(1)
+	require.NoError(t, err)
+	require.NoError(t, db.StoreBlock(testBlockBytes(1), testBlockHash(1), testQuorumCert(1)))
+	require.NoError(t, db.Commit())
+	block, err := testPersistenceMod.GetBlockByHash(testBlockHash(1))
+	require.NoError(t, err)
+	require.Equal(t, testBlockBytes(1), block)
+
+	// read contexts cannot store blocks
+	readContext, err := testPersistenceMod.NewReadContext(1)
+	require.NoError(t, err)
+	defer readContext.Close()
+	require.Error(t, readContext.(persistence.PostgresContext).StoreBlock(testBlockBytes(2), testBlockHash(2), testQuorumCert(2)))
+}
+
+func TestBlockStore_ReconciledOnStartup(t *testing.T) {
+	configFilePath := newTestConfigFile(t, "block_store_test_schema", nil)
+	persistenceMod, err := persistence.Create(configFilePath, testingGenesisFilePath)
+	require.NoError(t, err)
+	// a block committed along with its state
+	db, err := persistenceMod.NewRWContext(1)
+	require.NoError(t, err)
+	require.NoError(t, db.StoreBlock(testBlockBytes(1), testBlockHash(1), testQuorumCert(1)))
+	require.NoError(t, db.InsertBlock(1, testBlockHash(1), []byte("proposer"), testQuorumCert(1)))
+	require.NoError(t, db.Commit())
+	// a block without its state in the database (e.g. written by a previous version of the node)
+	db, err = persistenceMod.NewRWContext(2)
+	require.NoError(t, err)
+	require.NoError(t, db.StoreBlock(testBlockBytes(2), testBlockHash(2), testQuorumCert(2)))
+	require.NoError(t, db.Commit())
+	require.NoError(t, persistenceMod.Stop())
+
+	// restarting the module deletes the block without state
+	persistenceMod, err = persistence.Create(configFilePath, testingGenesisFilePath)
+	require.NoError(t, err)
+
+	latestHeight, block, _, err := persistenceMod.GetLatestBlock()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), latestHeight)
+	require.Equal(t, testBlockBytes(1), block)
+	_, err = persistenceMod.GetBlockByHeight(2)
+	require.Error(t, err)
+	_, err = persistenceMod.GetBlockByHash(testBlockHash(2))
+	require.Error(t, err)
+	require.NoError(t, persistenceMod.Stop())
+
+	// a block committed to the database without being written to the block store
+	persistenceMod, err = persistence.Create(configFilePath, testingGenesisFilePath)
+	require.NoError(t, err)
+	db, err = persistenceMod.NewRWContext(2)
+	require.NoError(t, err)
+	require.NoError(t, db.InsertBlock(2, testBlockHash(2), []byte("proposer"), testQuorumCert(2)))
+	require.NoError(t, db.Commit())
+	require.NoError(t, persistenceMod.Stop())
+
+	// the node refuses to start without it
+	_, err = persistence.Create(configFilePath, testingGenesisFilePath)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing from the block store")
+}
+
+func TestBlockStore_LegacyKeysMigratedOnStartup(t *testing.T) {
+	blockStorePath := filepath.Join(t.TempDir(), "blockstore")
+	configFilePath := newTestConfigFile(t, "block_store_migration_test_schema", func(cfg *types.PersistenceConfig) {
+		cfg.BlockStorePath = blockStorePath
+	})
+	persistenceMod, err := persistence.Create(configFilePath, testingGenesisFilePath)
+	require.NoError(t, err)
+	db, err := persistenceMod.NewRWContext(1)
+	require.NoError(t, err)
+	require.NoError(t, db.InsertBlock(1, testBlockHash(1), []byte("proposer"), testQuorumCert(1)))
+	require.NoError(t, db.Commit())
+	require.NoError(t, persistenceMod.Stop())
+
+	// a block stored by a previous version of the node, keyed by its little endian height
+	blockBz, err := codec.GetCodec().Marshal(&typesCons.Block{BlockHeader: &typesCons.BlockHeader{
+		Height:            1,
+		Hash:              testBlockHash(1),
+		QuorumCertificate: testQuorumCert(1),
+	}})
+	require.NoError(t, err)
+	legacyKey := make([]byte, 8)
+	binary.LittleEndian.PutUint64(legacyKey, 1)
+	// along with an unrelated unprefixed key, which is left as is
+	unrelatedKey := []byte("unrelate")
+	blockStore, err := kvstore.NewKVStore(blockStorePath)
+	require.NoError(t, err)
+	require.NoError(t, blockStore.Put(legacyKey, blockBz))
+	require.NoError(t, blockStore.Put(unrelatedKey, []byte("value")))
+	// previous versions of the node did not record that the migration was done
+	require.NoError(t, blockStore.Delete([]byte("legacy_keys_migrated")))
+	require.NoError(t, blockStore.Stop())
+
+	persistenceMod, err = persistence.Create(configFilePath, testingGenesisFilePath)
+	require.NoError(t, err)
+	defer persistenceMod.Stop()
+
+	block, err := persistenceMod.GetBlockByHash(testBlockHash(1))
+	require.NoError(t, err)
+	require.Equal(t, blockBz, block)
+	latestHeight, block, quorumCert, err := persistenceMod.GetLatestBlock()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), latestHeight)
+	require.Equal(t, blockBz, block)
+	require.Equal(t, testQuorumCert(1), quorumCert)
+	exists, err := persistenceMod.GetBlockStore().Exists(legacyKey)
+	require.NoError(t, err)
+	require.False(t, exists)
+	exists, err = persistenceMod.GetBlockStore().Exists(unrelatedKey)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func TestBlockStore_LegacyKeysOnlyMigratedOnce(t *testing.T) {
+	blockStorePath := filepath.Join(t.TempDir(), "blockstore")
+	configFilePath := newTestConfigFile(t, "block_store_migrated_once_test_schema", func(cfg *types.PersistenceConfig) {
+		cfg.BlockStorePath = blockStorePath
+	})
+	persistenceMod, err := persistence.Create(configFilePath, testingGenesisFilePath)
+	require.NoError(t, err)
+	require.NoError(t, persistenceMod.Stop())
+
+	// the block store is not scanned for legacy keys again once migrated
+	blockBz, err := codec.GetCodec().Marshal(&typesCons.Block{BlockHeader: &typesCons.BlockHeader{Height: 1}})
+	require.NoError(t, err)
+	legacyKey := make([]byte, 8)
+	binary.LittleEndian.PutUint64(legacyKey, 1)
+	blockStore, err := kvstore.NewKVStore(blockStorePath)
+	require.NoError(t, err)
+	require.NoError(t, blockStore.Put(legacyKey, blockBz))
+	require.NoError(t, blockStore.Stop())
+
+	persistenceMod, err = persistence.Create(configFilePath, testingGenesisFilePath)
+	require.NoError(t, err)
+	defer persistenceMod.Stop()
+	exists, err := persistenceMod.GetBlockStore().Exists(legacyKey)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func testBlockBytes(height uint64) []byte {
+	return []byte(fmt.Sprintf("block %d", height))
+}
+
+func testBlockHash(height uint64) string {
+	return hex.EncodeToString(crypto.SHA3Hash(testBlockBytes(height)))
+}
+
+func testQuorumCert(height uint64) []byte {
+	return []byte(fmt.Sprintf("quorum certificate %d", height))
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pokt-network/pocket/persistence"
+	"github.com/pokt-network/pocket/persistence/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaMigrator(t *testing.T) {
+	// A separate schema is used so rolling it back does not affect the other tests
+	cfg, err := new(persistence.PersistenceModule).InitConfig(testingConfigFilePath)
+	require.NoError(t, err)
+	persistenceCfg := cfg.(*types.PersistenceConfig)
+	persistenceCfg.NodeSchema = "migrations_test_schema"
+	persistenceCfg.SqlitePath = filepath.Join(t.TempDir(), "migrations_test.db")
+
+	migrator, err := persistence.NewSchemaMigrator(persistenceCfg)
+	require.NoError(t, err)
+	defer migrator.Close()
+	ctx := context.Background()
+
+	// A new schema has no migration applied
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, len(types.Migrations))
+	for _, status := range statuses {
+		require.Nil(t, status.AppliedAt)
+	}
+
+	// Migrating is idempotent
+	require.NoError(t, migrator.Migrate(ctx, types.LatestSchemaVersion()))
+	require.NoError(t, migrator.Migrate(ctx, types.LatestSchemaVersion()))
+	statuses, err = migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		require.NotNil(t, status.AppliedAt)
+		require.WithinDuration(t, time.Now(), *status.AppliedAt, time.Minute)
+	}
+
+	// Unknown versions are refused
+	require.Error(t, migrator.Migrate(ctx, types.LatestSchemaVersion()+1))
+
+	// Rolling back every migration leaves nothing to roll back
+	for i := 0; i < len(types.Migrations); i++ {
+		require.NoError(t, migrator.Rollback(ctx))
+	}
+	require.Error(t, migrator.Rollback(ctx))
+	statuses, err = migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Nil(t, statuses[0].AppliedAt)
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -111,3 +112,60 @@ func TestPersistenceContextMultipleParallelReads(t *testing.T) {
 	require.NoError(t, readContext2.Close())
 	require.NoError(t, readContext3.Close())
 }
+
+func TestPersistenceContextSavePoints(t *testing.T) {
+	// Cleanup previous contexts
+	testPersistenceMod.ResetContext()
+	t.Cleanup(func() {
+		testPersistenceMod.ResetContext()
+	})
+
+	poolName := "fake"
+	context, err := testPersistenceMod.NewRWContext(0)
+	require.NoError(t, err)
+	require.NoError(t, context.InsertPool(poolName, []byte("address"), "10"))
+
+	// The writes made after a save point are reverted when rolling back to it
+	require.NoError(t, context.NewSavePoint([]byte("tx1")))
+	require.NoError(t, context.SetPoolAmount(poolName, "20"))
+	require.NoError(t, context.RollbackToSavePoint([]byte("tx1")))
+	amount, err := context.GetPoolAmount(poolName, 0)
+	require.NoError(t, err)
+	require.Equal(t, "10", amount)
+
+	// The writes made after a released save point are kept
+	require.NoError(t, context.NewSavePoint([]byte("tx3")))
+	require.NoError(t, context.SetPoolAmount(poolName, "30"))
+	require.NoError(t, context.ReleaseSavePoint([]byte("tx3")))
+	amount, err = context.GetPoolAmount(poolName, 0)
+	require.NoError(t, err)
+	require.Equal(t, "30", amount)
+
+	// Rolling back to an unknown or released save point fails
+	require.Error(t, context.RollbackToSavePoint([]byte("tx3")))
+	require.NoError(t, context.Release())
+}
+
+func TestPersistenceContextCancelledCtx(t *testing.T) {
+	// Cleanup previous contexts
+	testPersistenceMod.ResetContext()
+	t.Cleanup(func() {
+		testPersistenceMod.ResetContext()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	writeContext, err := testPersistenceMod.NewRWContextWithCtx(ctx, 0)
+	require.NoError(t, err)
+	_, err = writeContext.GetPoolAmount("fake", 0)
+	require.NoError(t, err)
+
+	// Queries fail once the ctx is cancelled
+	cancel()
+	_, err = writeContext.GetPoolAmount("fake", 0)
+	require.Error(t, err)
+
+	// The cancelled write context does not prevent opening a new one
+	writeContext, err = testPersistenceMod.NewRWContext(0)
+	require.NoError(t, err)
+	require.NoError(t, writeContext.Release())
+}
@@ -0,0 +1,182 @@
+package test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pokt-network/pocket/persistence"
+	"github.com/pokt-network/pocket/persistence/types"
+	"github.com/pokt-network/pocket/shared/modules"
+	"github.com/stretchr/testify/require"
+)
+
+const prunedTestHeights = 5
+
+func TestPrune_KeepRecent(t *testing.T) {
+	persistenceMod, app := newPrunedTestPersistenceModule(t, "prune_keep_recent_test_schema", func(cfg *types.PersistenceConfig) {
+		cfg.PruningStrategy = types.PruneKeepRecent
+		cfg.PruningKeepRecent = 2
+	})
+	require.NoError(t, persistenceMod.(*persistence.PersistenceModule).Prune())
+
+	readContext, err := persistenceMod.NewReadContext(prunedTestHeights)
+	require.NoError(t, err)
+	defer readContext.Close()
+	db := readContext.(persistence.PostgresContext)
+
+	// the state at the last 2 heights is kept
+	for _, height := range []int64{4, 5} {
+		requirePrunedTestState(t, db, app, height)
+	}
+	// while the versions superseded below them are pruned, along with the chains of the app
+	amount, err := db.GetPoolAmount(DefaultPoolName, 3)
+	require.NoError(t, err)
+	require.Equal(t, "0", amount)
+	_, err = db.GetIntParam(modules.BlocksPerSessionParamName, 3)
+	require.Error(t, err)
+	_, _, _, _, _, _, _, _, err = db.GetApp(app, 3)
+	require.Error(t, err)
+	require.Equal(t, 2, countAppChains(t, db, app))
+
+	// pruning again at the same height is a no-op
+	require.NoError(t, persistenceMod.(*persistence.PersistenceModule).Prune())
+	requirePrunedTestState(t, db, app, 4)
+}
+
+func TestPrune_KeepEvery(t *testing.T) {
+	persistenceMod, app := newPrunedTestPersistenceModule(t, "prune_keep_every_test_schema", func(cfg *types.PersistenceConfig) {
+		cfg.PruningStrategy = types.PruneKeepEvery
+		cfg.PruningKeepEvery = 2
+	})
+	require.NoError(t, persistenceMod.(*persistence.PersistenceModule).Prune())
+
+	readContext, err := persistenceMod.NewReadContext(prunedTestHeights)
+	require.NoError(t, err)
+	defer readContext.Close()
+	db := readContext.(persistence.PostgresContext)
+
+	// the state at every other height is kept along with the latest one
+	for _, height := range []int64{2, 4, 5} {
+		requirePrunedTestState(t, db, app, height)
+	}
+	// while the versions only read at the other heights are pruned
+	_, _, _, _, _, _, _, _, err = db.GetApp(app, 1)
+	require.Error(t, err)
+	require.Equal(t, 3, countAppChains(t, db, app))
+}
+
+func TestPrune_DeferredWhileWriteContextOpen(t *testing.T) {
+	persistenceMod, app := newPrunedTestPersistenceModule(t, "prune_deferred_test_schema", func(cfg *types.PersistenceConfig) {
+		cfg.PruningStrategy = types.PruneKeepRecent
+		cfg.PruningKeepRecent = 2
+		// Bounds how long the pruning waits for the lock held by the write context on SQLite
+		cfg.StatementTimeoutMsec = 100
+	})
+
+	// on SQLite the write context holds the lock of the database, so the pruning is deferred rather than failing
+	db, err := persistenceMod.NewRWContext(prunedTestHeights + 1)
+	require.NoError(t, err)
+	require.NoError(t, persistenceMod.(*persistence.PersistenceModule).Prune())
+	require.NoError(t, db.Release())
+
+	// and completed by the next run
+	require.NoError(t, persistenceMod.(*persistence.PersistenceModule).Prune())
+	readContext, err := persistenceMod.NewReadContext(prunedTestHeights)
+	require.NoError(t, err)
+	defer readContext.Close()
+	_, _, _, _, _, _, _, _, err = readContext.(persistence.PostgresContext).GetApp(app, 3)
+	require.Error(t, err)
+}
+
+func TestPrune_StoppedWhilePruning(t *testing.T) {
+	persistenceMod, _ := newPrunedTestPersistenceModule(t, "prune_stopped_test_schema", func(cfg *types.PersistenceConfig) {
+		cfg.PruningStrategy = types.PruneKeepRecent
+		cfg.PruningKeepRecent = 2
+		cfg.PruningIntervalMsec = 1
+	})
+	require.NoError(t, persistenceMod.Start())
+	time.Sleep(20 * time.Millisecond)
+
+	// the pruning goroutine is waited for before the stores are closed, and stopping the module again is a no-op
+	require.NoError(t, persistenceMod.Stop())
+	require.NoError(t, persistenceMod.Stop())
+}
+
+func TestPrune_InvalidStrategy(t *testing.T) {
+	for _, configure := range []func(cfg *types.PersistenceConfig){
+		func(cfg *types.PersistenceConfig) { cfg.PruningStrategy = "keep_nothing" },
+		func(cfg *types.PersistenceConfig) { cfg.PruningStrategy = types.PruneKeepRecent },
+		func(cfg *types.PersistenceConfig) { cfg.PruningStrategy = types.PruneKeepEvery },
+	} {
+		_, err := persistence.Create(newTestConfigFile(t, "prune_invalid_test_schema", configure), testingGenesisFilePath)
+		require.Error(t, err)
+	}
+}
+
+// newPrunedTestPersistenceModule creates a module with the pruning strategy configured by `configure`, and writes
+// a new version of a pool, a param and an app, whose chains change too, at every height up to `prunedTestHeights`.
+func newPrunedTestPersistenceModule(t *testing.T, nodeSchema string, configure func(cfg *types.PersistenceConfig)) (modules.PersistenceModule, []byte) {
+	persistenceMod, err := persistence.Create(newTestConfigFile(t, nodeSchema, configure), testingGenesisFilePath)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		persistenceMod.Stop()
+	})
+
+	app, err := newTestApp()
+	require.NoError(t, err)
+	addrBz, err := hex.DecodeString(app.Address)
+	require.NoError(t, err)
+	pubKeyBz, err := hex.DecodeString(app.PublicKey)
+	require.NoError(t, err)
+	outputBz, err := hex.DecodeString(app.Output)
+	require.NoError(t, err)
+
+	for height := int64(1); height <= prunedTestHeights; height++ {
+		db, err := persistenceMod.NewRWContext(height)
+		require.NoError(t, err)
+		if height == 1 {
+			require.NoError(t, db.InsertPool(DefaultPoolName, []byte(DefaultPoolName), prunedTestAmount(height)))
+			require.NoError(t, db.InsertApp(addrBz, pubKeyBz, outputBz, false, 0, DefaultMaxRelays, DefaultStake, prunedTestChains(height), DefaultPauseHeight, DefaultUnstakingHeight))
+		} else {
+			require.NoError(t, db.SetPoolAmount(DefaultPoolName, prunedTestAmount(height)))
+			require.NoError(t, db.UpdateApp(addrBz, DefaultMaxRelays, DefaultStake, prunedTestChains(height)))
+		}
+		require.NoError(t, db.SetParam(modules.BlocksPerSessionParamName, int(height)))
+		require.NoError(t, db.InsertBlock(uint64(height), testBlockHash(uint64(height)), []byte("proposer"), testQuorumCert(uint64(height))))
+		require.NoError(t, db.Commit())
+	}
+	return persistenceMod, addrBz
+}
+
+func requirePrunedTestState(t *testing.T, db persistence.PostgresContext, app []byte, height int64) {
+	amount, err := db.GetPoolAmount(DefaultPoolName, height)
+	require.NoError(t, err)
+	require.Equal(t, prunedTestAmount(height), amount)
+
+	blocksPerSession, err := db.GetIntParam(modules.BlocksPerSessionParamName, height)
+	require.NoError(t, err)
+	require.Equal(t, int(height), blocksPerSession)
+
+	_, _, _, _, _, _, _, chains, err := db.GetApp(app, height)
+	require.NoError(t, err)
+	require.Equal(t, prunedTestChains(height), chains)
+}
+
+func prunedTestAmount(height int64) string {
+	return fmt.Sprintf("%d", height)
+}
+
+func prunedTestChains(height int64) []string {
+	return []string{fmt.Sprintf("%04d", height)}
+}
+
+// countAppChains returns the number of chains rows of the app at every height.
+func countAppChains(t *testing.T, db persistence.PostgresContext, app []byte) (count int) {
+	ctx, tx, err := db.GetCtxAndTx()
+	require.NoError(t, err)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE address=$1", types.ApplicationActor.GetChainsTableName())
+	require.NoError(t, tx.QueryRow(ctx, query, hex.EncodeToString(app)).Scan(&count))
+	return count
+}
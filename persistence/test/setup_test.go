@@ -3,12 +3,14 @@ package test
 import (
 	"encoding/hex"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -50,9 +52,26 @@ var (
 )
 var testPersistenceMod modules.PersistenceModule // initialized in TestMain
 
+// The database backend the tests run against. The SQLite one does not require Docker.
+var databaseBackend string
+
+func init() {
+	flag.StringVar(&databaseBackend, "databaseBackend", types.PostgresBackend, "The database backend to run the tests against: postgres or sqlite")
+}
+
 // See https://github.com/ory/dockertest as reference for the template of this code
 // Postgres example can be found here: https://github.com/ory/dockertest/blob/v3/examples/PostgreSQL.md
 func TestMain(m *testing.M) {
+	flag.Parse()
+	if databaseBackend == types.SQLiteBackend {
+		testPersistenceMod = newTestPersistenceModule("")
+		exitCode := m.Run()
+		testPersistenceMod.Stop()
+		os.Remove(testingConfigFilePath)
+		os.Remove(testingGenesisFilePath)
+		os.Exit(exitCode)
+	}
+
 	pool, resource, dbUrl := sharedTest.SetupPostgresDocker()
 	testPersistenceMod = newTestPersistenceModule(dbUrl)
 	exitCode := m.Run()
@@ -104,9 +123,13 @@ func NewFuzzTestPostgresContext(f *testing.F, height int64) *persistence.Postgre
 func newTestPersistenceModule(databaseUrl string) modules.PersistenceModule {
 	cfg := modules.Config{
 		Persistence: &types.PersistenceConfig{
-			PostgresUrl:    databaseUrl,
-			NodeSchema:     testSchema,
-			BlockStorePath: "",
+			PostgresUrl:     databaseUrl,
+			NodeSchema:      testSchema,
+			BlockStorePath:  "",
+			TreeStorePath:   "",
+			TxIndexerPath:   "",
+			DatabaseBackend: databaseBackend,
+			SqlitePath:      "", // a temporary database
 		},
 	}
 	genesisState, _ := test_artifacts.NewGenesisState(5, 1, 1, 1)
@@ -118,6 +141,25 @@ func newTestPersistenceModule(databaseUrl string) modules.PersistenceModule {
 	return persistenceMod
 }
 
+// newTestConfigFile writes a config file for a persistence module with its own schema and stores, configured by
+// `configure`, so the module can be restarted or configured differently without affecting the other tests.
+func newTestConfigFile(t *testing.T, nodeSchema string, configure func(cfg *types.PersistenceConfig)) (configFilePath string) {
+	cfg, err := new(persistence.PersistenceModule).InitConfig(testingConfigFilePath)
+	require.NoError(t, err)
+	persistenceCfg := cfg.(*types.PersistenceConfig)
+	persistenceCfg.NodeSchema = nodeSchema
+	persistenceCfg.SqlitePath = filepath.Join(t.TempDir(), nodeSchema+".db")
+	persistenceCfg.BlockStorePath = filepath.Join(t.TempDir(), "blockstore")
+	if configure != nil {
+		configure(persistenceCfg)
+	}
+	configFileBz, err := json.Marshal(map[string]any{"persistence": persistenceCfg})
+	require.NoError(t, err)
+	configFilePath = filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(configFilePath, configFileBz, 0600))
+	return configFilePath
+}
+
 // IMPROVE(team): Extend this to more complex and variable test cases challenging & randomizing the state of persistence.
 func fuzzSingleProtocolActor(
 	f *testing.F,
@@ -0,0 +1,219 @@
+package test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/pokt-network/pocket/persistence"
+	"github.com/pokt-network/pocket/persistence/kvstore"
+	"github.com/pokt-network/pocket/persistence/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateHash_DeterministicAndDiscardedOnRelease(t *testing.T) {
+	// Cleanup previous contexts
+	testPersistenceMod.ResetContext()
+	t.Cleanup(func() {
+		testPersistenceMod.ResetContext()
+	})
+
+	height := int64(100)
+	poolName := "state_hash_pool"
+
+	// The app hash is the same for the same writes, no matter how many times it is computed
+	context, err := testPersistenceMod.NewRWContext(height)
+	require.NoError(t, err)
+	require.NoError(t, context.InsertPool(poolName, []byte("address"), "10"))
+	appHash, err := context.AppHash()
+	require.NoError(t, err)
+	appHashAgain, err := context.AppHash()
+	require.NoError(t, err)
+	require.Equal(t, appHash, appHashAgain)
+	require.NoError(t, context.Release())
+
+	// Releasing a context discards its changes to the merkle trees
+	context, err = testPersistenceMod.NewRWContext(height)
+	require.NoError(t, err)
+	require.NoError(t, context.InsertPool(poolName, []byte("address"), "10"))
+	appHashAfterRelease, err := context.AppHash()
+	require.NoError(t, err)
+	require.Equal(t, appHash, appHashAfterRelease)
+
+	// Different writes result in a different app hash
+	require.NoError(t, context.SetPoolAmount(poolName, "20"))
+	modifiedAppHash, err := context.AppHash()
+	require.NoError(t, err)
+	require.NotEqual(t, appHash, modifiedAppHash)
+	require.NoError(t, context.Commit())
+
+	// The committed state is the starting point of the next height
+	context, err = testPersistenceMod.NewRWContext(height + 1)
+	require.NoError(t, err)
+	nextAppHash, err := context.AppHash()
+	require.NoError(t, err)
+	require.Equal(t, modifiedAppHash, nextAppHash)
+	require.NoError(t, context.Release())
+}
+
+// Restarting the module, which hydrates the genesis state again, must not change the state the app hash commits to.
+func TestStateHash_UnchangedOnRestart(t *testing.T) {
+	configFilePath := newTestConfigFile(t, "state_hash_restart_test_schema", func(cfg *types.PersistenceConfig) {
+		cfg.TreeStorePath = filepath.Join(t.TempDir(), "treestore")
+	})
+	persistenceMod, err := persistence.Create(configFilePath, testingGenesisFilePath)
+	require.NoError(t, err)
+
+	readContext, err := persistenceMod.NewReadContext(0)
+	require.NoError(t, err)
+	accounts, err := readContext.GetAllAccounts(0)
+	require.NoError(t, err)
+	require.NoError(t, readContext.Close())
+	require.NotEmpty(t, accounts)
+	genesisAccount, err := hex.DecodeString(accounts[0].GetAddress())
+	require.NoError(t, err)
+
+	// The genesis account is changed at height 1
+	context, err := persistenceMod.NewRWContext(1)
+	require.NoError(t, err)
+	require.NoError(t, context.SetAccountAmount(genesisAccount, "1"))
+	require.NoError(t, context.StoreBlock(testBlockBytes(1), testBlockHash(1), testQuorumCert(1)))
+	require.NoError(t, context.InsertBlock(1, testBlockHash(1), []byte("proposer"), testQuorumCert(1)))
+	appHash, err := context.AppHash()
+	require.NoError(t, err)
+	require.NoError(t, context.Commit())
+	require.NoError(t, persistenceMod.Stop())
+
+	persistenceMod, err = persistence.Create(configFilePath, testingGenesisFilePath)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		persistenceMod.Stop()
+	})
+
+	// Nothing is written at height 2, so it starts from the state committed at height 1
+	context, err = persistenceMod.NewRWContext(2)
+	require.NoError(t, err)
+	nextAppHash, err := context.AppHash()
+	require.NoError(t, err)
+	require.Equal(t, appHash, nextAppHash)
+	require.NoError(t, context.Release())
+}
+
+func TestStateTrees_LittleEndianHeightsMigratedOnStartup(t *testing.T) {
+	treeStorePath := filepath.Join(t.TempDir(), "treestore")
+	configFilePath := newTestConfigFile(t, "state_trees_migration_test_schema", func(cfg *types.PersistenceConfig) {
+		cfg.TreeStorePath = treeStorePath
+	})
+	persistenceMod, err := persistence.Create(configFilePath, testingGenesisFilePath)
+	require.NoError(t, err)
+	app, err := newTestApp()
+	require.NoError(t, err)
+	address, err := hex.DecodeString(app.Address)
+	require.NoError(t, err)
+
+	appHashes := make(map[int64][]byte)
+	for height := int64(1); height <= 2; height++ {
+		context, err := persistenceMod.NewRWContext(height)
+		require.NoError(t, err)
+		require.NoError(t, context.SetAccountAmount(address, fmt.Sprint(height)))
+		require.NoError(t, context.StoreBlock(testBlockBytes(uint64(height)), testBlockHash(uint64(height)), testQuorumCert(uint64(height))))
+		require.NoError(t, context.InsertBlock(uint64(height), testBlockHash(uint64(height)), []byte("proposer"), testQuorumCert(uint64(height))))
+		appHashes[height], err = context.AppHash()
+		require.NoError(t, err)
+		require.NoError(t, context.Commit())
+	}
+	require.NoError(t, persistenceMod.Stop())
+
+	// the tree store as committed by a previous version of the node, whose heights were little endian
+	treeStore, err := kvstore.NewKVStore(treeStorePath)
+	require.NoError(t, err)
+	for height := int64(1); height <= 2; height++ {
+		rootsKey := append([]byte("roots/"), bigEndianHeight(height)...)
+		roots, err := treeStore.Get(rootsKey)
+		require.NoError(t, err)
+		require.NoError(t, treeStore.Delete(rootsKey))
+		require.NoError(t, treeStore.Put(append([]byte("roots/"), littleEndianHeight(height)...), roots))
+	}
+	require.NoError(t, treeStore.Put([]byte("latest_roots_height"), littleEndianHeight(2)))
+	require.NoError(t, treeStore.Delete([]byte("big_endian_heights")))
+	require.NoError(t, treeStore.Stop())
+
+	persistenceMod, err = persistence.Create(configFilePath, testingGenesisFilePath)
+	require.NoError(t, err)
+	defer persistenceMod.Stop()
+
+	// the state committed before the latest height can still be proven
+	readContext, err := persistenceMod.NewReadContext(1)
+	require.NoError(t, err)
+	defer readContext.Close()
+	amount, proof, err := readContext.GetAccountAmountWithProof(address, 1)
+	require.NoError(t, err)
+	require.NoError(t, types.VerifyAccountAmountProof(appHashes[1], address, amount, proof))
+
+	// the trees carry on from the latest committed roots
+	context, err := persistenceMod.NewRWContext(3)
+	require.NoError(t, err)
+	appHash, err := context.AppHash()
+	require.NoError(t, err)
+	require.Equal(t, appHashes[2], appHash)
+	require.NoError(t, context.Release())
+}
+
+func TestStateProofs(t *testing.T) {
+	// Cleanup previous contexts
+	testPersistenceMod.ResetContext()
+	t.Cleanup(func() {
+		testPersistenceMod.ResetContext()
+	})
+
+	height := int64(200)
+	app, err := newTestApp()
+	require.NoError(t, err)
+	address, err := hex.DecodeString(app.Address)
+	require.NoError(t, err)
+
+	context, err := testPersistenceMod.NewRWContext(height)
+	require.NoError(t, err)
+	require.NoError(t, context.SetAccountAmount(address, "100"))
+	require.NoError(t, context.InsertApp(address, []byte("pk"), address, false, 0, "10", "1000", []string{"0001"}, -1, -1))
+	appHash, err := context.AppHash()
+	require.NoError(t, err)
+	require.NoError(t, context.Commit())
+
+	readContext, err := testPersistenceMod.NewReadContext(height)
+	require.NoError(t, err)
+	defer readContext.Close()
+
+	amount, proof, err := readContext.GetAccountAmountWithProof(address, height)
+	require.NoError(t, err)
+	require.Equal(t, "100", amount)
+	require.NoError(t, types.VerifyAccountAmountProof(appHash, address, amount, proof))
+	require.Error(t, types.VerifyAccountAmountProof(appHash, address, "101", proof))
+
+	// Pools that do not exist are proven to not be in the state
+	amount, proof, err = readContext.GetPoolAmountWithProof("state_proof_unknown_pool", height)
+	require.NoError(t, err)
+	require.NoError(t, types.VerifyPoolAmountProof(appHash, "state_proof_unknown_pool", amount, proof))
+
+	actor, proof, err := readContext.GetAppWithProof(address, height)
+	require.NoError(t, err)
+	require.NoError(t, types.VerifyActorProof(appHash, actor.(*types.Actor), proof))
+
+	// Proofs are only available for committed heights
+	_, _, err = readContext.GetAccountAmountWithProof(address, height+1)
+	require.Error(t, err)
+}
+
+func bigEndianHeight(height int64) []byte {
+	heightBz := make([]byte, 8)
+	binary.BigEndian.PutUint64(heightBz, uint64(height))
+	return heightBz
+}
+
+func littleEndianHeight(height int64) []byte {
+	heightBz := make([]byte, 8)
+	binary.LittleEndian.PutUint64(heightBz, uint64(height))
+	return heightBz
+}
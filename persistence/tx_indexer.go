@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/pokt-network/pocket/persistence/kvstore"
+	"github.com/pokt-network/pocket/shared/indexer"
+)
+
+// txIndexer indexes the results of the transactions applied by the write context. The results are staged in
+// memory until the context is committed, so releasing the context leaves no trace in the index.
+type txIndexer struct {
+	mu sync.Mutex
+
+	store   kvstore.KVStore
+	indexer indexer.TxIndexer
+	staged  []indexer.TxResult
+}
+
+func newTxIndexer(store kvstore.KVStore) *txIndexer {
+	return &txIndexer{
+		store:   store,
+		indexer: indexer.NewTxIndexerFromKVStore(store),
+	}
+}
+
+func (t *txIndexer) stage(result indexer.TxResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.staged = append(t.staged, result)
+}
+
+// exists returns whether a transaction with that hash has been indexed or, if `includeStaged`, staged.
+func (t *txIndexer) exists(hash []byte, includeStaged bool) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if includeStaged {
+		for _, result := range t.staged {
+			stagedHash, err := result.Hash()
+			if err != nil {
+				return false, err
+			}
+			if bytes.Equal(stagedHash, hash) {
+				return true, nil
+			}
+		}
+	}
+
+	if _, err := t.indexer.GetByHash(hash); err != nil {
+		if errors.Is(err, kvstore.ErrKeyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// getByHash returns the result of the indexed transaction with that hash, which excludes the staged ones.
+func (t *txIndexer) getByHash(hash []byte) (indexer.TxResult, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.indexer.GetByHash(hash)
+}
+
+// commit indexes the staged results.
+func (t *txIndexer) commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, result := range t.staged {
+		if err := t.indexer.Index(result); err != nil {
+			return err
+		}
+	}
+	t.staged = nil
+	return nil
+}
+
+// discard drops the results staged since the last commit.
+func (t *txIndexer) discard() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.staged = nil
+}
+
+// clear drops the staged results along with the whole index.
+func (t *txIndexer) clear() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.staged = nil
+	return t.store.ClearAll()
+}
+
+func (t *txIndexer) close() error {
+	return t.indexer.Close()
+}
@@ -22,20 +22,20 @@ var (
 	PoolTableSchema    = AccountOrPoolSchema(NameCol, PoolHeightConstraint)
 )
 
-func GetAccountAmountQuery(address string, height int64) string {
+func GetAccountAmountQuery(address string, height int64) (string, []any) {
 	return SelectBalance(AddressCol, address, height, AccountTableName)
 }
 
-func InsertAccountAmountQuery(address, amount string, height int64) string {
-	return InsertAcc(AddressCol, address, amount, height, AccountTableName, AccountHeightConstraint)
+func InsertAccountAmountQuery(address, amount string, height int64) (string, []any) {
+	return InsertAcc(AddressCol, address, amount, height, AccountTableName)
 }
 
-func GetPoolAmountQuery(name string, height int64) string {
+func GetPoolAmountQuery(name string, height int64) (string, []any) {
 	return SelectBalance(NameCol, name, height, PoolTableName)
 }
 
-func InsertPoolAmountQuery(name, amount string, height int64) string {
-	return InsertAcc(NameCol, name, amount, height, PoolTableName, PoolHeightConstraint)
+func InsertPoolAmountQuery(name, amount string, height int64) (string, []any) {
+	return InsertAcc(NameCol, name, amount, height, PoolTableName)
 }
 
 func AccountOrPoolSchema(mainColName, constraintName string) string {
@@ -48,34 +48,42 @@ func AccountOrPoolSchema(mainColName, constraintName string) string {
 		)`, mainColName, BalanceCol, HeightCol, constraintName, mainColName, HeightCol)
 }
 
-func InsertAcc(actorSpecificParam, actorSpecificParamValue, amount string, height int64, tableName, constraintName string) string {
+func InsertAcc(actorSpecificParam, actorSpecificParamValue, amount string, height int64, tableName string) (string, []any) {
 	return fmt.Sprintf(`
 		INSERT INTO %s (%s, balance, height)
-			VALUES ('%s','%s',%d)
-			ON CONFLICT ON CONSTRAINT %s
+			VALUES ($1, $2, $3)
+			ON CONFLICT (%s, height)
 			DO UPDATE SET balance=EXCLUDED.balance, height=EXCLUDED.height
-		`, tableName, actorSpecificParam, actorSpecificParamValue, amount, height, constraintName)
+		`, tableName, actorSpecificParam, actorSpecificParam), []any{actorSpecificParamValue, amount, height}
 }
 
-func SelectBalance(actorSpecificParam, actorSpecificParamValue string, height int64, tableName string) string {
-	return fmt.Sprintf(`SELECT balance FROM %s WHERE %s='%s' AND height<=%d ORDER BY height DESC LIMIT 1`,
-		tableName, actorSpecificParam, actorSpecificParamValue, height)
+func SelectBalance(actorSpecificParam, actorSpecificParamValue string, height int64, tableName string) (string, []any) {
+	return fmt.Sprintf(`SELECT balance FROM %s WHERE %s=$1 AND height<=$2 ORDER BY height DESC LIMIT 1`,
+		tableName, actorSpecificParam), []any{actorSpecificParamValue, height}
 }
 
-func SelectAccounts(height int64, tableName string) string {
+func SelectAccounts(height int64, tableName string) (string, []any) {
 	return fmt.Sprintf(`
-			SELECT DISTINCT ON (address) address, balance, height
+			SELECT address, balance, height
 			FROM %s
-			WHERE height<=%d
-			ORDER BY address, height DESC
-       `, tableName, height)
+			WHERE (address, height) IN (SELECT address, MAX(height) FROM %s WHERE height<=$1 GROUP BY address)
+			ORDER BY address
+       `, tableName, tableName), []any{height}
 }
 
-func SelectPools(height int64, tableName string) string {
+func SelectPools(height int64, tableName string) (string, []any) {
 	return fmt.Sprintf(`
-			SELECT DISTINCT ON (name) name, balance, height
+			SELECT name, balance, height
 			FROM %s
-			WHERE height<=%d
-			ORDER BY name, height DESC
-       `, tableName, height)
+			WHERE (name, height) IN (SELECT name, MAX(height) FROM %s WHERE height<=$1 GROUP BY name)
+			ORDER BY name
+       `, tableName, tableName), []any{height}
+}
+
+func SelectAccountsUpdatedAtHeight(height int64) (string, []any) {
+	return fmt.Sprintf(`SELECT address, balance FROM %s WHERE height=$1`, AccountTableName), []any{height}
+}
+
+func SelectPoolsUpdatedAtHeight(height int64) (string, []any) {
+	return fmt.Sprintf(`SELECT name, balance FROM %s WHERE height=$1`, PoolTableName), []any{height}
 }
@@ -60,43 +60,47 @@ func (actor *BaseProtocolActorSchema) GetChainsTableSchema() string {
 	return ProtocolActorChainsTableSchema(actor.chainsHeightConstraintName)
 }
 
-func (actor *BaseProtocolActorSchema) GetQuery(address string, height int64) string {
+func (actor *BaseProtocolActorSchema) GetQuery(address string, height int64) (string, []any) {
 	return Select(AllColsSelector, address, height, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) GetAllQuery(height int64) string {
+func (actor *BaseProtocolActorSchema) GetAllQuery(height int64) (string, []any) {
 	return SelectActors(actor.GetActorSpecificColName(), height, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) GetExistsQuery(address string, height int64) string {
+func (actor *BaseProtocolActorSchema) GetUpdatedAtHeightQuery(height int64) (string, []any) {
+	return SelectActorsUpdatedAtHeight(actor.GetActorSpecificColName(), height, actor.tableName)
+}
+
+func (actor *BaseProtocolActorSchema) GetExistsQuery(address string, height int64) (string, []any) {
 	return Exists(address, height, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) GetReadyToUnstakeQuery(unstakingHeight int64) string {
+func (actor *BaseProtocolActorSchema) GetReadyToUnstakeQuery(unstakingHeight int64) (string, []any) {
 	return ReadyToUnstake(unstakingHeight, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) GetOutputAddressQuery(operatorAddress string, height int64) string {
+func (actor *BaseProtocolActorSchema) GetOutputAddressQuery(operatorAddress string, height int64) (string, []any) {
 	return Select(OutputAddressCol, operatorAddress, height, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) GetStakeAmountQuery(address string, height int64) string {
+func (actor *BaseProtocolActorSchema) GetStakeAmountQuery(address string, height int64) (string, []any) {
 	return Select(StakedTokensCol, address, height, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) GetPausedHeightQuery(address string, height int64) string {
+func (actor *BaseProtocolActorSchema) GetPausedHeightQuery(address string, height int64) (string, []any) {
 	return Select(PausedHeightCol, address, height, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) GetUnstakingHeightQuery(address string, height int64) string {
+func (actor *BaseProtocolActorSchema) GetUnstakingHeightQuery(address string, height int64) (string, []any) {
 	return Select(UnstakingHeightCol, address, height, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) GetChainsQuery(address string, height int64) string {
+func (actor *BaseProtocolActorSchema) GetChainsQuery(address string, height int64) (string, []any) {
 	return SelectChains(AllColsSelector, address, height, actor.tableName, actor.chainsTableName)
 }
 
-func (actor *BaseProtocolActorSchema) InsertQuery(address, publicKey, stakedTokens, maxRelays, outputAddress string, pausedHeight, unstakingHeight int64, chains []string, height int64) string {
+func (actor *BaseProtocolActorSchema) InsertQuery(address, publicKey, stakedTokens, maxRelays, outputAddress string, pausedHeight, unstakingHeight int64, height int64) (string, []any) {
 	return Insert(BaseActor{
 		Address:         address,
 		PublicKey:       publicKey,
@@ -104,36 +108,34 @@ func (actor *BaseProtocolActorSchema) InsertQuery(address, publicKey, stakedToke
 		OutputAddress:   outputAddress,
 		PausedHeight:    pausedHeight,
 		UnstakingHeight: unstakingHeight,
-		Chains:          chains,
 	},
 		actor.actorSpecificColName, maxRelays,
-		actor.heightConstraintName, actor.chainsHeightConstraintName,
-		actor.tableName, actor.chainsTableName,
+		actor.tableName,
 		height)
 }
 
-func (actor *BaseProtocolActorSchema) UpdateQuery(address, stakedTokens, generic string, height int64) string {
-	return Update(address, stakedTokens, actor.actorSpecificColName, generic, height, actor.tableName, actor.heightConstraintName)
+func (actor *BaseProtocolActorSchema) UpdateQuery(address, stakedTokens, generic string, height int64) (string, []any) {
+	return Update(address, stakedTokens, actor.actorSpecificColName, generic, height, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) UpdateChainsQuery(address string, chains []string, height int64) string {
-	return InsertChains(address, chains, height, actor.chainsTableName, actor.chainsHeightConstraintName)
+func (actor *BaseProtocolActorSchema) UpdateChainsQuery(address string, chains []string, height int64) (string, []any) {
+	return InsertChains(address, chains, height, actor.chainsTableName)
 }
 
-func (actor *BaseProtocolActorSchema) UpdateUnstakingHeightQuery(address string, unstakingHeight, height int64) string {
-	return UpdateUnstakingHeight(address, actor.actorSpecificColName, unstakingHeight, height, actor.tableName, actor.heightConstraintName)
+func (actor *BaseProtocolActorSchema) UpdateUnstakingHeightQuery(address string, unstakingHeight, height int64) (string, []any) {
+	return UpdateUnstakingHeight(address, actor.actorSpecificColName, unstakingHeight, height, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) UpdatePausedHeightQuery(address string, pausedHeight, height int64) string {
-	return UpdatePausedHeight(address, actor.actorSpecificColName, pausedHeight, height, actor.tableName, actor.heightConstraintName)
+func (actor *BaseProtocolActorSchema) UpdatePausedHeightQuery(address string, pausedHeight, height int64) (string, []any) {
+	return UpdatePausedHeight(address, actor.actorSpecificColName, pausedHeight, height, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) UpdateUnstakedHeightIfPausedBeforeQuery(pauseBeforeHeight, unstakingHeight, height int64) string {
-	return UpdateUnstakedHeightIfPausedBefore(actor.actorSpecificColName, unstakingHeight, pauseBeforeHeight, height, actor.tableName, actor.heightConstraintName)
+func (actor *BaseProtocolActorSchema) UpdateUnstakedHeightIfPausedBeforeQuery(pauseBeforeHeight, unstakingHeight, height int64) (string, []any) {
+	return UpdateUnstakedHeightIfPausedBefore(actor.actorSpecificColName, unstakingHeight, pauseBeforeHeight, height, actor.tableName)
 }
 
-func (actor *BaseProtocolActorSchema) SetStakeAmountQuery(address string, stakedTokens string, height int64) string {
-	return UpdateStakeAmount(address, actor.actorSpecificColName, stakedTokens, height, actor.tableName, actor.heightConstraintName)
+func (actor *BaseProtocolActorSchema) SetStakeAmountQuery(address string, stakedTokens string, height int64) (string, []any) {
+	return UpdateStakeAmount(address, actor.actorSpecificColName, stakedTokens, height, actor.tableName)
 }
 
 func (actor *BaseProtocolActorSchema) ClearAllQuery() string {
@@ -13,22 +13,26 @@ const (
 		)`
 )
 
-func InsertBlockQuery(height uint64, hashString string, proposerAddr []byte, quorumCert []byte) string {
+func InsertBlockQuery(height uint64, hashString string, proposerAddr []byte, quorumCert []byte) (string, []any) {
 	return fmt.Sprintf(
 		`INSERT INTO %s(height, hash, proposer_address, quorum_certificate)
-			VALUES(%d, '%s', '%b', '%b')`,
-		BlockTableName,
-		height, hashString, proposerAddr, quorumCert)
+			VALUES($1, $2, $3, $4)`,
+		BlockTableName), []any{int64(height), hashString, proposerAddr, quorumCert}
 }
 
-func GetBlockHashQuery(height int64) string {
-	return fmt.Sprintf(`SELECT hash FROM %s WHERE height=%d`, BlockTableName, height)
+func GetBlockHashQuery(height int64) (string, []any) {
+	return fmt.Sprintf(`SELECT hash FROM %s WHERE height=$1`, BlockTableName), []any{height}
 }
 
 func GetLatestBlockHeightQuery() string {
 	return fmt.Sprintf(`SELECT MAX(height) FROM %s`, BlockTableName)
 }
 
+// GetMaxBlockHeightQuery returns -1, rather than NULL, if there are no blocks.
+func GetMaxBlockHeightQuery() string {
+	return fmt.Sprintf(`SELECT COALESCE(MAX(height), -1) FROM %s`, BlockTableName)
+}
+
 func ClearAllBlocksQuery() string {
 	return fmt.Sprintf(`DELETE FROM %s`, BlockTableName)
 }
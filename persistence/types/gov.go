@@ -58,57 +58,70 @@ var (
 	)
 )
 
-// InsertParams generates the SQL INSERT statement given a *genesis.Params
+// InsertParams generates the SQL INSERT statement given a *genesis.Params, along with its arguments
 //
 // It leverages metadata in the form of struct tags (see `parseGovProto` for more information).
 //
 // WARNING: reflections in prod
-func InsertParams(params modules.Params, height int64) string {
+func InsertParams(params modules.Params, height int64) (string, []any) {
 	val := reflect.ValueOf(params)
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("INSERT INTO %s VALUES ", ParamsTableName))
 
+	// The height is shared by all the rows
+	args := []any{height}
 	l := len(govParamMetadataKeys)
 	for i, k := range govParamMetadataKeys {
 		pnt := govParamMetadataMap[k]
-		sb.WriteString(fmt.Sprintf("('%s', %d, '%s', ", k, height, pnt.PropertyType))
 		pVal := val.Elem().FieldByName(pnt.PropertyName)
 		pType := govParamMetadataMap[k].PropertyType
+		var value string
 		switch pType {
 		case ValTypeString:
 			switch vt := pVal.Interface().(type) {
 			case []byte:
-				fmt.Fprintf(&sb, "'%s')", hex.EncodeToString(vt))
+				value = hex.EncodeToString(vt)
 			case string:
-				fmt.Fprintf(&sb, "'%s')", vt)
+				value = vt
 			default:
 				log.Fatalf("unhandled type for param: expected []byte or string, got %T", vt)
 			}
 
 		case ValTypeSmallInt, ValTypeBigInt:
-			fmt.Fprintf(&sb, "%d)", pVal.Interface())
+			value = fmt.Sprintf("%d", pVal.Interface())
 		default:
 			log.Fatalf("unhandled PropertyType: %s.", pType)
 		}
+		args = append(args, k, pnt.PropertyType, value)
+		fmt.Fprintf(&sb, "($%d, $1, $%d, $%d)", len(args)-2, len(args)-1, len(args))
 
 		if i < l-1 {
 			sb.WriteString(",")
 		}
 	}
 
-	constraint := fmt.Sprintf("%s_pkey", ParamsTableName)
-	fmt.Fprintf(&sb, " ON CONFLICT ON CONSTRAINT %s DO UPDATE SET value=EXCLUDED.value, type=EXCLUDED.type", constraint)
+	sb.WriteString(" ON CONFLICT (name, height) DO UPDATE SET value=EXCLUDED.value, type=EXCLUDED.type")
 
-	return sb.String()
+	return sb.String(), args
 }
 
-func GetParamOrFlagQuery(tableName, flagName string, height int64) string {
+func GetParamOrFlagQuery(tableName, flagName string, height int64) (string, []any) {
 	fields := "value"
 	if tableName == FlagsTableName {
 		fields += ",enabled"
 	}
-	return fmt.Sprintf(`SELECT %s FROM %s WHERE name='%s' AND height<=%d ORDER BY height DESC LIMIT 1`, fields, tableName, flagName, height)
+	return fmt.Sprintf(`SELECT %s FROM %s WHERE name=$1 AND height<=$2 ORDER BY height DESC LIMIT 1`, fields, tableName),
+		[]any{flagName, height}
+}
+
+// GetParamsOrFlagsUpdatedAtHeightQuery returns the params/flags set at exactly that height
+func GetParamsOrFlagsUpdatedAtHeightQuery(tableName string, height int64) (string, []any) {
+	fields := "name,value"
+	if tableName == FlagsTableName {
+		fields += ",enabled"
+	}
+	return fmt.Sprintf(`SELECT %s FROM %s WHERE height=$1`, fields, tableName), []any{height}
 }
 
 // SupportedParamTypes represents the types currently supported for the `value` property in params and flags
@@ -116,40 +129,38 @@ type SupportedParamTypes interface {
 	int | int32 | int64 | []byte | string
 }
 
-// InsertParamOrFlag returns the SQL SQL INSERT (with conflict handling so that it's effectively an "upsert") required to set a parameter/flag
-func InsertParamOrFlag[T SupportedParamTypes](tableName, name string, height int64, value T, enabled *bool) string {
+// InsertParamOrFlag returns the SQL SQL INSERT (with conflict handling so that it's effectively an "upsert") required to set a parameter/flag,
+// along with its arguments
+func InsertParamOrFlag[T SupportedParamTypes](tableName, name string, height int64, value T, enabled *bool) (string, []any) {
 	fields := "name,height,type,value"
+	placeholders := "$1, $2, $3, $4"
 	upsertFields := "type=EXCLUDED.type,value=EXCLUDED.value"
 	if tableName == FlagsTableName {
 		fields += ",enabled"
+		placeholders += ", $5"
 		upsertFields += ",enabled=EXCLUDED.enabled"
 	}
 
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("INSERT INTO %s(%s) VALUES ('%s', %d, ", tableName, fields, name, height))
-
+	args := []any{name, height}
 	switch tp := any(value).(type) {
 	case int, int32:
-		sb.WriteString(fmt.Sprintf("'%s', %d", ValTypeSmallInt, tp))
+		args = append(args, ValTypeSmallInt, fmt.Sprintf("%d", tp))
 	case int64:
-		sb.WriteString(fmt.Sprintf("'%s', %d", ValTypeBigInt, tp))
+		args = append(args, ValTypeBigInt, fmt.Sprintf("%d", tp))
 	case []byte:
-		sb.WriteString(fmt.Sprintf("'%s', '%s'", ValTypeBigInt, hex.EncodeToString(tp)))
+		args = append(args, ValTypeBigInt, hex.EncodeToString(tp))
 	case string:
-		sb.WriteString(fmt.Sprintf("'%s', '%s'", ValTypeString, tp))
+		args = append(args, ValTypeString, tp)
 	default:
 		log.Fatalf("unhandled type for paramValue %T", tp)
 	}
 
 	if enabled != nil {
-		sb.WriteString(fmt.Sprintf(",%t", *enabled))
+		args = append(args, *enabled)
 	}
 
-	sb.WriteString(")")
-
-	constraint := fmt.Sprintf("%s_pkey", tableName)
-	sb.WriteString(fmt.Sprintf("ON CONFLICT ON CONSTRAINT %s DO UPDATE SET %s", constraint, upsertFields))
-	return sb.String()
+	return fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s) ON CONFLICT (name, height) DO UPDATE SET %s",
+		tableName, fields, placeholders, upsertFields), args
 }
 
 func ClearAllGovParamsQuery() string {
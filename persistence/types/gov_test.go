@@ -1,7 +1,11 @@
 package types
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestInsertParams(t *testing.T) {
@@ -9,10 +13,13 @@ func TestInsertParams(t *testing.T) {
 		params *Params
 		height int64
 	}
+	type row struct {
+		name, valType, value string
+	}
 	tests := []struct {
-		name string
-		args args
-		want string
+		name     string
+		args     args
+		wantRows []row
 	}{
 		{
 			name: "should insert genesis.DefaultParams() as expected",
@@ -20,124 +27,142 @@ func TestInsertParams(t *testing.T) {
 				params: DefaultParams(),
 				height: DefaultBigInt,
 			},
-			want: "INSERT INTO params VALUES ('blocks_per_session', -1, 'BIGINT', 4)," +
-				"('app_minimum_stake', -1, 'STRING', '15000000000')," +
-				"('app_max_chains', -1, 'SMALLINT', 15)," +
-				"('app_baseline_stake_rate', -1, 'BIGINT', 100)," +
-				"('app_staking_adjustment', -1, 'BIGINT', 0)," +
-				"('app_unstaking_blocks', -1, 'BIGINT', 2016)," +
-				"('app_minimum_pause_blocks', -1, 'SMALLINT', 4)," +
-				"('app_max_pause_blocks', -1, 'BIGINT', 672)," +
-				"('service_node_minimum_stake', -1, 'STRING', '15000000000')," +
-				"('service_node_max_chains', -1, 'SMALLINT', 15)," +
-				"('service_node_unstaking_blocks', -1, 'BIGINT', 2016)," +
-				"('service_node_minimum_pause_blocks', -1, 'SMALLINT', 4)," +
-				"('service_node_max_pause_blocks', -1, 'BIGINT', 672)," +
-				"('service_nodes_per_session', -1, 'SMALLINT', 24)," +
-				"('fisherman_minimum_stake', -1, 'STRING', '15000000000')," +
-				"('fisherman_max_chains', -1, 'SMALLINT', 15)," +
-				"('fisherman_unstaking_blocks', -1, 'BIGINT', 2016)," +
-				"('fisherman_minimum_pause_blocks', -1, 'SMALLINT', 4)," +
-				"('fisherman_max_pause_blocks', -1, 'SMALLINT', 672)," +
-				"('validator_minimum_stake', -1, 'STRING', '15000000000')," +
-				"('validator_unstaking_blocks', -1, 'BIGINT', 2016)," +
-				"('validator_minimum_pause_blocks', -1, 'SMALLINT', 4)," +
-				"('validator_max_pause_blocks', -1, 'SMALLINT', 672)," +
-				"('validator_maximum_missed_blocks', -1, 'SMALLINT', 5)," +
-				"('validator_max_evidence_age_in_blocks', -1, 'SMALLINT', 8)," +
-				"('proposer_percentage_of_fees', -1, 'SMALLINT', 10)," +
-				"('missed_blocks_burn_percentage', -1, 'SMALLINT', 1)," +
-				"('double_sign_burn_percentage', -1, 'SMALLINT', 5)," +
-				"('message_double_sign_fee', -1, 'STRING', '10000')," +
-				"('message_send_fee', -1, 'STRING', '10000')," +
-				"('message_stake_fisherman_fee', -1, 'STRING', '10000')," +
-				"('message_edit_stake_fisherman_fee', -1, 'STRING', '10000')," +
-				"('message_unstake_fisherman_fee', -1, 'STRING', '10000')," +
-				"('message_pause_fisherman_fee', -1, 'STRING', '10000')," +
-				"('message_unpause_fisherman_fee', -1, 'STRING', '10000')," +
-				"('message_fisherman_pause_service_node_fee', -1, 'STRING', '10000')," +
-				"('message_test_score_fee', -1, 'STRING', '10000')," +
-				"('message_prove_test_score_fee', -1, 'STRING', '10000')," +
-				"('message_stake_app_fee', -1, 'STRING', '10000')," +
-				"('message_edit_stake_app_fee', -1, 'STRING', '10000')," +
-				"('message_unstake_app_fee', -1, 'STRING', '10000')," +
-				"('message_pause_app_fee', -1, 'STRING', '10000')," +
-				"('message_unpause_app_fee', -1, 'STRING', '10000')," +
-				"('message_stake_validator_fee', -1, 'STRING', '10000')," +
-				"('message_edit_stake_validator_fee', -1, 'STRING', '10000')," +
-				"('message_unstake_validator_fee', -1, 'STRING', '10000')," +
-				"('message_pause_validator_fee', -1, 'STRING', '10000')," +
-				"('message_unpause_validator_fee', -1, 'STRING', '10000')," +
-				"('message_stake_service_node_fee', -1, 'STRING', '10000')," +
-				"('message_edit_stake_service_node_fee', -1, 'STRING', '10000')," +
-				"('message_unstake_service_node_fee', -1, 'STRING', '10000')," +
-				"('message_pause_service_node_fee', -1, 'STRING', '10000')," +
-				"('message_unpause_service_node_fee', -1, 'STRING', '10000')," +
-				"('message_change_parameter_fee', -1, 'STRING', '10000')," +
-				"('acl_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('blocks_per_session_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('app_minimum_stake_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('app_max_chains_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('app_baseline_stake_rate_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('app_staking_adjustment_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('app_unstaking_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('app_minimum_pause_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('app_max_paused_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('service_node_minimum_stake_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('service_node_max_chains_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('service_node_unstaking_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('service_node_minimum_pause_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('service_node_max_paused_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('service_nodes_per_session_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('fisherman_minimum_stake_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('fisherman_max_chains_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('fisherman_unstaking_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('fisherman_minimum_pause_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('fisherman_max_paused_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('validator_minimum_stake_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('validator_unstaking_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('validator_minimum_pause_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('validator_max_paused_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('validator_maximum_missed_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('validator_max_evidence_age_in_blocks_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('proposer_percentage_of_fees_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('missed_blocks_burn_percentage_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('double_sign_burn_percentage_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_double_sign_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_send_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_stake_fisherman_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_edit_stake_fisherman_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_unstake_fisherman_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_pause_fisherman_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_unpause_fisherman_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_fisherman_pause_service_node_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_test_score_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_prove_test_score_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_stake_app_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_edit_stake_app_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_unstake_app_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_pause_app_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_unpause_app_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_stake_validator_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_edit_stake_validator_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_unstake_validator_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_pause_validator_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_unpause_validator_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_stake_service_node_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_edit_stake_service_node_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_unstake_service_node_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_pause_service_node_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_unpause_service_node_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45')," +
-				"('message_change_parameter_fee_owner', -1, 'STRING', 'da034209758b78eaea06dd99c07909ab54c99b45') " +
-				"ON CONFLICT ON CONSTRAINT params_pkey DO UPDATE SET value=EXCLUDED.value, type=EXCLUDED.type",
+			wantRows: []row{
+				{"blocks_per_session", "BIGINT", "4"},
+				{"app_minimum_stake", "STRING", "15000000000"},
+				{"app_max_chains", "SMALLINT", "15"},
+				{"app_baseline_stake_rate", "BIGINT", "100"},
+				{"app_staking_adjustment", "BIGINT", "0"},
+				{"app_unstaking_blocks", "BIGINT", "2016"},
+				{"app_minimum_pause_blocks", "SMALLINT", "4"},
+				{"app_max_pause_blocks", "BIGINT", "672"},
+				{"service_node_minimum_stake", "STRING", "15000000000"},
+				{"service_node_max_chains", "SMALLINT", "15"},
+				{"service_node_unstaking_blocks", "BIGINT", "2016"},
+				{"service_node_minimum_pause_blocks", "SMALLINT", "4"},
+				{"service_node_max_pause_blocks", "BIGINT", "672"},
+				{"service_nodes_per_session", "SMALLINT", "24"},
+				{"fisherman_minimum_stake", "STRING", "15000000000"},
+				{"fisherman_max_chains", "SMALLINT", "15"},
+				{"fisherman_unstaking_blocks", "BIGINT", "2016"},
+				{"fisherman_minimum_pause_blocks", "SMALLINT", "4"},
+				{"fisherman_max_pause_blocks", "SMALLINT", "672"},
+				{"validator_minimum_stake", "STRING", "15000000000"},
+				{"validator_unstaking_blocks", "BIGINT", "2016"},
+				{"validator_minimum_pause_blocks", "SMALLINT", "4"},
+				{"validator_max_pause_blocks", "SMALLINT", "672"},
+				{"validator_maximum_missed_blocks", "SMALLINT", "5"},
+				{"validator_max_evidence_age_in_blocks", "SMALLINT", "8"},
+				{"proposer_percentage_of_fees", "SMALLINT", "10"},
+				{"missed_blocks_burn_percentage", "SMALLINT", "1"},
+				{"double_sign_burn_percentage", "SMALLINT", "5"},
+				{"message_double_sign_fee", "STRING", "10000"},
+				{"message_send_fee", "STRING", "10000"},
+				{"message_stake_fisherman_fee", "STRING", "10000"},
+				{"message_edit_stake_fisherman_fee", "STRING", "10000"},
+				{"message_unstake_fisherman_fee", "STRING", "10000"},
+				{"message_pause_fisherman_fee", "STRING", "10000"},
+				{"message_unpause_fisherman_fee", "STRING", "10000"},
+				{"message_fisherman_pause_service_node_fee", "STRING", "10000"},
+				{"message_test_score_fee", "STRING", "10000"},
+				{"message_prove_test_score_fee", "STRING", "10000"},
+				{"message_stake_app_fee", "STRING", "10000"},
+				{"message_edit_stake_app_fee", "STRING", "10000"},
+				{"message_unstake_app_fee", "STRING", "10000"},
+				{"message_pause_app_fee", "STRING", "10000"},
+				{"message_unpause_app_fee", "STRING", "10000"},
+				{"message_stake_validator_fee", "STRING", "10000"},
+				{"message_edit_stake_validator_fee", "STRING", "10000"},
+				{"message_unstake_validator_fee", "STRING", "10000"},
+				{"message_pause_validator_fee", "STRING", "10000"},
+				{"message_unpause_validator_fee", "STRING", "10000"},
+				{"message_stake_service_node_fee", "STRING", "10000"},
+				{"message_edit_stake_service_node_fee", "STRING", "10000"},
+				{"message_unstake_service_node_fee", "STRING", "10000"},
+				{"message_pause_service_node_fee", "STRING", "10000"},
+				{"message_unpause_service_node_fee", "STRING", "10000"},
+				{"message_change_parameter_fee", "STRING", "10000"},
+				{"acl_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"blocks_per_session_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"app_minimum_stake_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"app_max_chains_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"app_baseline_stake_rate_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"app_staking_adjustment_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"app_unstaking_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"app_minimum_pause_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"app_max_paused_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"service_node_minimum_stake_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"service_node_max_chains_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"service_node_unstaking_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"service_node_minimum_pause_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"service_node_max_paused_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"service_nodes_per_session_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"fisherman_minimum_stake_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"fisherman_max_chains_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"fisherman_unstaking_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"fisherman_minimum_pause_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"fisherman_max_paused_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"validator_minimum_stake_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"validator_unstaking_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"validator_minimum_pause_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"validator_max_paused_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"validator_maximum_missed_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"validator_max_evidence_age_in_blocks_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"proposer_percentage_of_fees_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"missed_blocks_burn_percentage_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"double_sign_burn_percentage_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_double_sign_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_send_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_stake_fisherman_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_edit_stake_fisherman_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_unstake_fisherman_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_pause_fisherman_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_unpause_fisherman_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_fisherman_pause_service_node_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_test_score_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_prove_test_score_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_stake_app_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_edit_stake_app_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_unstake_app_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_pause_app_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_unpause_app_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_stake_validator_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_edit_stake_validator_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_unstake_validator_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_pause_validator_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_unpause_validator_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_stake_service_node_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_edit_stake_service_node_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_unstake_service_node_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_pause_service_node_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_unpause_service_node_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+				{"message_change_parameter_fee_owner", "STRING", "da034209758b78eaea06dd99c07909ab54c99b45"},
+			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := InsertParams(tt.args.params, tt.args.height); got != tt.want {
-				t.Errorf("InsertParams() = %v, want %v", got, tt.want)
+			wantPlaceholders := make([]string, 0, len(tt.wantRows))
+			wantArgs := []any{tt.args.height}
+			for _, r := range tt.wantRows {
+				wantArgs = append(wantArgs, r.name, r.valType, r.value)
+				wantPlaceholders = append(wantPlaceholders, fmt.Sprintf("($%d, $1, $%d, $%d)", len(wantArgs)-2, len(wantArgs)-1, len(wantArgs)))
 			}
+			wantQuery := "INSERT INTO params VALUES " + strings.Join(wantPlaceholders, ",") +
+				" ON CONFLICT (name, height) DO UPDATE SET value=EXCLUDED.value, type=EXCLUDED.type"
+
+			gotQuery, gotArgs := InsertParams(tt.args.params, tt.args.height)
+			require.Equal(t, wantQuery, gotQuery)
+			require.Equal(t, wantArgs, gotArgs)
 		})
 	}
 
 }
+
+func TestInsertParamOrFlag_BindsValues(t *testing.T) {
+	enabled := true
+	query, args := InsertParamOrFlag(FlagsTableName, "flag'); DROP TABLE flags; --", 1, "value'", &enabled)
+	require.Equal(t, "INSERT INTO flags(name,height,type,value,enabled) VALUES ($1, $2, $3, $4, $5) "+
+		"ON CONFLICT (name, height) DO UPDATE SET type=EXCLUDED.type,value=EXCLUDED.value,enabled=EXCLUDED.enabled", query)
+	require.Equal(t, []any{"flag'); DROP TABLE flags; --", int64(1), ValTypeString, "value'", true}, args)
+}
@@ -0,0 +1,173 @@
+package types
+
+import "fmt"
+
+const (
+	SchemaMigrationsTableName = "schema_migrations"
+	// The schema of the table as first released, which the Postgres databases of running nodes may already have.
+	// Its `applied_at` column is converted to seconds since the unix epoch by the migration at
+	// `UnixAppliedAtSchemaVersion`.
+	SchemaMigrationsTableSchema = `(
+			version     BIGINT PRIMARY KEY,
+			description TEXT NOT NULL,
+			applied_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`
+	// SQLite has no timestamp type, so its databases are created with the converted `applied_at` column
+	SQLiteSchemaMigrationsTableSchema = `(
+			version     BIGINT PRIMARY KEY,
+			description TEXT NOT NULL,
+			applied_at  BIGINT NOT NULL
+		)`
+
+	UnixAppliedAtSchemaVersion = 2
+)
+
+// Migration is a versioned change of the schema of a node. Its `Up` statements apply it and its `Down` statements
+// revert it. Migrations are applied in the order of their versions, which must be consecutive starting at 1.
+//
+// The statements are run against every database backend, so they must be written in the subset of SQL supported by
+// both Postgres and SQLite. Those that only apply to Postgres (e.g. creating types, which SQLite does not have) go
+// in `PostgresUp`, which runs before `Up`, and `PostgresDown`, which runs after `Down`.
+//
+// IMPORTANT: Migrations that have been released must never be modified since they may already be applied to the
+// databases of running nodes. Changes to the schema must be made by appending a new migration instead.
+type Migration struct {
+	Version     int64
+	Description string
+	Up          []string
+	Down        []string
+
+	PostgresUp   []string
+	PostgresDown []string
+}
+
+// Migrations lists every migration of the schema, in order.
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "create the account, pool, gov, block and protocol actor tables",
+		// NOTE: The statements are idempotent since these tables were created, without being versioned, before
+		// migrations were introduced.
+		PostgresUp: []string{
+			fmt.Sprintf(`DO $$ BEGIN
+				CREATE TYPE %s AS ENUM %s;
+			EXCEPTION
+				WHEN duplicate_object THEN NULL;
+			END $$`, ValTypeName, ValTypeEnumTypes),
+		},
+		Up: []string{
+			createTable(AccountTableName, AccountTableSchema),
+			createTable(PoolTableName, PoolTableSchema),
+			createTable(ParamsTableName, ParamsTableSchema),
+			createTable(FlagsTableName, FlagsTableSchema),
+			createTable(BlockTableName, BlockTableSchema),
+			createTable(AppTableName, ApplicationActor.GetTableSchema()),
+			createTable(AppChainsTableName, ApplicationActor.GetChainsTableSchema()),
+			createTable(FishermanTableName, FishermanActor.GetTableSchema()),
+			createTable(FishermanChainsTableName, FishermanActor.GetChainsTableSchema()),
+			createTable(ServiceNodeTableName, ServiceNodeActor.GetTableSchema()),
+			createTable(ServiceNodeChainsTableName, ServiceNodeActor.GetChainsTableSchema()),
+			createTable(ValidatorTableName, ValidatorActor.GetTableSchema()),
+		},
+		Down: []string{
+			dropTable(ValidatorTableName),
+			dropTable(ServiceNodeChainsTableName),
+			dropTable(ServiceNodeTableName),
+			dropTable(FishermanChainsTableName),
+			dropTable(FishermanTableName),
+			dropTable(AppChainsTableName),
+			dropTable(AppTableName),
+			dropTable(BlockTableName),
+			dropTable(FlagsTableName),
+			dropTable(ParamsTableName),
+			dropTable(PoolTableName),
+			dropTable(AccountTableName),
+		},
+		PostgresDown: []string{
+			fmt.Sprintf(`DROP TYPE IF EXISTS %s`, ValTypeName),
+		},
+	},
+	{
+		Version:     UnixAppliedAtSchemaVersion,
+		Description: "record when the migrations were applied in seconds since the unix epoch",
+		// NOTE: SQLite databases are created with the converted column (see `SQLiteSchemaMigrationsTableSchema`)
+		PostgresUp: []string{
+			fmt.Sprintf(`ALTER TABLE %s
+				ALTER COLUMN applied_at DROP DEFAULT,
+				ALTER COLUMN applied_at TYPE BIGINT USING CAST(EXTRACT(EPOCH FROM applied_at) AS BIGINT)`,
+				SchemaMigrationsTableName),
+		},
+		PostgresDown: []string{
+			fmt.Sprintf(`ALTER TABLE %s
+				ALTER COLUMN applied_at TYPE TIMESTAMPTZ USING TO_TIMESTAMP(applied_at),
+				ALTER COLUMN applied_at SET DEFAULT NOW()`,
+				SchemaMigrationsTableName),
+		},
+	},
+}
+
+// UpStatements returns the statements applying the migration to a database of that backend.
+func (m Migration) UpStatements(backend string) []string {
+	if backend != PostgresBackend {
+		return m.Up
+	}
+	return append(append([]string{}, m.PostgresUp...), m.Up...)
+}
+
+// DownStatements returns the statements reverting the migration from a database of that backend.
+func (m Migration) DownStatements(backend string) []string {
+	if backend != PostgresBackend {
+		return m.Down
+	}
+	return append(append([]string{}, m.Down...), m.PostgresDown...)
+}
+
+// LatestSchemaVersion returns the version of the schema once all the migrations are applied.
+func LatestSchemaVersion() int64 {
+	return Migrations[len(Migrations)-1].Version
+}
+
+func CreateSchemaMigrationsTableQuery(backend string) string {
+	if backend != PostgresBackend {
+		return createTable(SchemaMigrationsTableName, SQLiteSchemaMigrationsTableSchema)
+	}
+	return createTable(SchemaMigrationsTableName, SchemaMigrationsTableSchema)
+}
+
+// LockSchemaMigrationsTableQuery prevents multiple processes from migrating the same Postgres schema concurrently.
+// The lock is held until the end of the transaction.
+func LockSchemaMigrationsTableQuery() string {
+	return fmt.Sprintf(`LOCK TABLE %s IN ACCESS EXCLUSIVE MODE`, SchemaMigrationsTableName)
+}
+
+func GetSchemaVersionQuery() string {
+	return fmt.Sprintf(`SELECT COALESCE(MAX(version), 0) FROM %s`, SchemaMigrationsTableName)
+}
+
+func GetAppliedMigrationsQuery() string {
+	return fmt.Sprintf(`SELECT version, description, applied_at FROM %s ORDER BY version`, SchemaMigrationsTableName)
+}
+
+// InsertSchemaMigrationQuery records the migration as applied at `appliedAt`, in seconds since the unix epoch. The
+// `applied_at` column of Postgres databases is only converted to seconds since the unix epoch once the migration
+// at `UnixAppliedAtSchemaVersion` is applied.
+func InsertSchemaMigrationQuery(version int64, description string, appliedAt int64, backend string) (string, []any) {
+	appliedAtValue := "$3"
+	if backend == PostgresBackend && version < UnixAppliedAtSchemaVersion {
+		appliedAtValue = "TO_TIMESTAMP($3)"
+	}
+	return fmt.Sprintf(`INSERT INTO %s (version, description, applied_at) VALUES ($1, $2, %s)`, SchemaMigrationsTableName, appliedAtValue),
+		[]any{version, description, appliedAt}
+}
+
+func DeleteSchemaMigrationQuery(version int64) (string, []any) {
+	return fmt.Sprintf(`DELETE FROM %s WHERE version=$1`, SchemaMigrationsTableName), []any{version}
+}
+
+func createTable(tableName, tableSchema string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s %s`, tableName, tableSchema)
+}
+
+func dropTable(tableName string) string {
+	return fmt.Sprintf(`DROP TABLE IF EXISTS %s`, tableName)
+}
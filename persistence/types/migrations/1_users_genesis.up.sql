@@ -1,4 +0,0 @@
-CREATE TABLE IF NOT EXISTS users (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    value TEXT NOT NULL,
-);
\ No newline at end of file
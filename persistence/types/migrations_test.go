@@ -0,0 +1,18 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations_ConsecutiveVersions(t *testing.T) {
+	for i, migration := range Migrations {
+		require.Equal(t, int64(i+1), migration.Version)
+		require.NotEmpty(t, migration.Description)
+		// Some migrations only apply to Postgres (e.g. converting a column SQLite databases are created with)
+		require.NotEmpty(t, migration.UpStatements(PostgresBackend))
+		require.NotEmpty(t, migration.DownStatements(PostgresBackend))
+	}
+	require.Equal(t, int64(len(Migrations)), LatestSchemaVersion())
+}
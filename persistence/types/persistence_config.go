@@ -0,0 +1,8 @@
+package types
+
+// The database backends the persistence contexts can be backed by, as configured in `database_backend`.
+// Postgres is used if none is configured.
+const (
+	PostgresBackend = "postgres"
+	SQLiteBackend   = "sqlite"
+)
@@ -15,45 +15,49 @@ type ProtocolActorSchema interface {
 	GetActorSpecificColName() string
 
 	/*** Read/Get Queries ***/
+	// NOTE: The queries are returned along with the arguments bound to their placeholders.
+
 	// Returns a query to retrieve all of a single Actor's attributes.
-	GetQuery(address string, height int64) string
+	GetQuery(address string, height int64) (string, []any)
 	// Returns all actors at that height
-	GetAllQuery(height int64) string
+	GetAllQuery(height int64) (string, []any)
+	// Returns the actors inserted or updated at that height
+	GetUpdatedAtHeightQuery(height int64) (string, []any)
 	// Returns a query for the existence of an Actor given its address.
-	GetExistsQuery(address string, height int64) string
+	GetExistsQuery(address string, height int64) (string, []any)
 	// Returns a query to retrieve data associated with all the apps ready to unstake.
-	GetReadyToUnstakeQuery(unstakingHeight int64) string
+	GetReadyToUnstakeQuery(unstakingHeight int64) (string, []any)
 	// Returns a query to retrieve the output address of an Actor given its operator address.
 	// DISCUSS(drewsky): Why/how we even need this. What is an output & operator for an app?
-	GetOutputAddressQuery(operatorAddress string, height int64) string
+	GetOutputAddressQuery(operatorAddress string, height int64) (string, []any)
 	// Returns a query to retrieve the stake amount of an actor
-	GetStakeAmountQuery(address string, height int64) string
+	GetStakeAmountQuery(address string, height int64) (string, []any)
 	// Returns a query to retrieve the height at which an Actor was paused.
-	GetPausedHeightQuery(address string, height int64) string
+	GetPausedHeightQuery(address string, height int64) (string, []any)
 	// Returns a query to retrieve the height at which an Actor started unstaking.
 	// DISCUSS(team): if current_height == unstaking_height - is the Actor unstaking or unstaked (i.e. did we process the block yet => yes if you're a replica and no if you're a proposer)?
-	GetUnstakingHeightQuery(address string, height int64) string
+	GetUnstakingHeightQuery(address string, height int64) (string, []any)
 	// Returns a query to retrieve all the data associated with the chains an Actor is staked for.
-	GetChainsQuery(address string, height int64) string
+	GetChainsQuery(address string, height int64) (string, []any)
 
 	/*** Create/Insert Queries ***/
 
-	// Returns a query to create a new Actor with all of the necessary data.
-	InsertQuery(address, publicKey, stakedTokens, maxRelays, outputAddress string, pausedHeight, unstakingHeight int64, chains []string, height int64) string
+	// Returns a query to create a new Actor with all of the necessary data but its chains (see `UpdateChainsQuery`).
+	InsertQuery(address, publicKey, stakedTokens, maxRelays, outputAddress string, pausedHeight, unstakingHeight int64, height int64) (string, []any)
 
 	/*** Update Queries ***/
 	// Returns a query to update an Actor's stake and/or max relays.
-	UpdateQuery(address, stakedTokens, maxRelays string, height int64) string
-	// Returns a query to update the chains an Actor is staked for.
-	UpdateChainsQuery(address string, chains []string, height int64) string
+	UpdateQuery(address, stakedTokens, maxRelays string, height int64) (string, []any)
+	// Returns a query to insert the chains an Actor is staked for. There must be at least one chain.
+	UpdateChainsQuery(address string, chains []string, height int64) (string, []any)
 	// Returns a query to update the height at which an Actor is unstaking.
-	UpdateUnstakingHeightQuery(address string, unstakingHeight, height int64) string
+	UpdateUnstakingHeightQuery(address string, unstakingHeight, height int64) (string, []any)
 	// Returns a query to update the height at which an Actor is paused.
-	UpdatePausedHeightQuery(address string, pausedHeight, height int64) string
+	UpdatePausedHeightQuery(address string, pausedHeight, height int64) (string, []any)
 	// Returns a query to start unstaking Actors which have been paused.
-	UpdateUnstakedHeightIfPausedBeforeQuery(pauseBeforeHeight, unstakingHeight, height int64) string
+	UpdateUnstakedHeightIfPausedBeforeQuery(pauseBeforeHeight, unstakingHeight, height int64) (string, []any)
 	// Returns a query to update the actor's stake amount
-	SetStakeAmountQuery(address string, stakeAmount string, height int64) string
+	SetStakeAmountQuery(address string, stakeAmount string, height int64) (string, []any)
 
 	/*** Debug Queries Only /***/
 
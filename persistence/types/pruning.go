@@ -0,0 +1,62 @@
+package types
+
+import "fmt"
+
+// The pruning strategies of the historical state, as configured in `pruning_strategy`. Nothing is pruned if none
+// is configured.
+const (
+	PruneNothing    = "nothing"     // Keeps the state at every height (i.e. archive nodes)
+	PruneKeepRecent = "keep_recent" // Keeps the state at the last `pruning_keep_recent` heights
+	PruneKeepEvery  = "keep_every"  // Also keeps the state at every `pruning_keep_every`th height
+)
+
+// PrunedTable is a table whose rows are versions of a record, keyed by `keyCol`, inserted at the height of the
+// update and read with `height<=X ORDER BY height DESC LIMIT 1`.
+type PrunedTable struct {
+	TableName string
+	KeyCol    string
+}
+
+// PrunedTables are the tables of the historical state besides the actor tables, which are pruned along with their
+// chains.
+var PrunedTables = []PrunedTable{
+	{AccountTableName, AddressCol},
+	{PoolTableName, NameCol},
+	{ParamsTableName, NameCol},
+	{FlagsTableName, NameCol},
+}
+
+// Explainer:
+//
+//	LEAD(height) OVER (PARTITION BY key ORDER BY height) ->
+//	    returns the height of the next version of the record, up to `cutoffHeight`, which supersedes the row
+//	next_height IS NOT NULL ->
+//	    the last version up to `cutoffHeight` is kept since it is the one read at `cutoffHeight`, as are the
+//	    versions above it
+//	(next_height - 1) / keepEvery * keepEvery < height ->
+//	    the row is only read at the heights from its own up to `next_height - 1`, neither of which is a multiple
+//	    of `keepEvery` (if not zero)
+//
+// PruneQuery deletes the versions of the records which are not read at `cutoffHeight` and above, nor at the
+// multiples of `keepEvery` if it is not zero, so the latest version of every record is always kept.
+func PruneQuery(table PrunedTable, cutoffHeight, keepEvery int64) (string, []any) {
+	return fmt.Sprintf(`
+		DELETE FROM %[1]s WHERE (%[2]s, height) IN (
+			SELECT %[2]s, height FROM (
+				SELECT %[2]s, height, LEAD(height) OVER (PARTITION BY %[2]s ORDER BY height) AS next_height
+				FROM %[1]s WHERE height<=CAST($1 AS BIGINT)
+			) AS versions
+			WHERE next_height IS NOT NULL
+				AND (CAST($2 AS BIGINT)=0 OR (next_height - 1) / CAST($2 AS BIGINT) * CAST($2 AS BIGINT) < height)
+		)`,
+		table.TableName, table.KeyCol), []any{cutoffHeight, keepEvery}
+}
+
+// PruneChainsQuery deletes the chains of the pruned versions of the actors, since the chains of an actor are
+// read at the height of its version.
+func PruneChainsQuery(actorTableName, chainsTableName string, cutoffHeight int64) (string, []any) {
+	return fmt.Sprintf(`
+		DELETE FROM %[1]s WHERE height<=CAST($1 AS BIGINT)
+			AND (address, height) NOT IN (SELECT address, height FROM %[2]s WHERE height<=CAST($1 AS BIGINT))`,
+		chainsTableName, actorTableName), []any{cutoffHeight}
+}
@@ -1,8 +1,8 @@
 package types
 
 import (
-	"bytes"
 	"fmt"
+	"strings"
 )
 
 const (
@@ -69,166 +69,162 @@ func ProtocolActorChainsTableSchema(constraintName string) string {
 		)`, AddressCol, ChainIDCol, HeightCol, DefaultBigInt, constraintName, AddressCol, ChainIDCol, HeightCol)
 }
 
-func Select(selector, address string, height int64, tableName string) string {
-	return fmt.Sprintf(`SELECT %s FROM %s WHERE address='%s' AND height<=%d ORDER BY height DESC LIMIT 1`,
-		selector, tableName, address, height)
+// The query builders return the SQL along with the arguments bound to its `$n` placeholders, so values
+// (which may come from user transactions) are never interpolated into the SQL. Only table and column names,
+// which are constants, are. This also keeps the SQL of a given query constant so it is only prepared once
+// per connection by the statement cache.
+//
+// The queries are written in the subset of SQL supported by both Postgres and SQLite so they are shared by the
+// database backends.
+
+func Select(selector, address string, height int64, tableName string) (string, []any) {
+	return fmt.Sprintf(`SELECT %s FROM %s WHERE address=$1 AND height<=$2 ORDER BY height DESC LIMIT 1`,
+		selector, tableName), []any{address, height}
+}
+
+func SelectActors(actorSpecificParam string, height int64, tableName string) (string, []any) {
+	return fmt.Sprintf(`
+			SELECT address, public_key, staked_tokens, %s, output_address, paused_height, unstaking_height, height
+			FROM %s
+			WHERE (address, height) IN (SELECT address, MAX(height) FROM %s WHERE height<=$1 GROUP BY address)
+			ORDER BY address
+       `, actorSpecificParam, tableName, tableName), []any{height}
 }
 
-func SelectActors(actorSpecificParam string, height int64, tableName string) string {
+func SelectActorsUpdatedAtHeight(actorSpecificParam string, height int64, tableName string) (string, []any) {
 	return fmt.Sprintf(`
-			SELECT DISTINCT ON (address) address, public_key, staked_tokens, %s, output_address, paused_height, unstaking_height, height
+			SELECT address, public_key, staked_tokens, %s, output_address, paused_height, unstaking_height, height
 			FROM %s
-			WHERE height<=%d
-			ORDER BY address, height DESC
-       `, actorSpecificParam, tableName, height)
+			WHERE height=$1
+       `, actorSpecificParam, tableName), []any{height}
 }
 
-func SelectChains(selector, address string, height int64, actorTableName, chainsTableName string) string {
-	return fmt.Sprintf(`SELECT %s FROM %s WHERE address='%s' AND height=(%s);`,
-		selector, chainsTableName, address, Select(HeightCol, address, height, actorTableName))
+func SelectChains(selector, address string, height int64, actorTableName, chainsTableName string) (string, []any) {
+	// The subquery shares the `$1` and `$2` arguments of the query
+	selectHeight, args := Select(HeightCol, address, height, actorTableName)
+	return fmt.Sprintf(`SELECT %s FROM %s WHERE address=$1 AND height=(%s);`,
+		selector, chainsTableName, selectHeight), args
 }
 
-func Exists(address string, height int64, tableName string) string {
-	return fmt.Sprintf(`SELECT EXISTS(%s)`, Select(AnyValueSelector, address, height, tableName))
+func Exists(address string, height int64, tableName string) (string, []any) {
+	selectAny, args := Select(AnyValueSelector, address, height, tableName)
+	return fmt.Sprintf(`SELECT EXISTS(%s)`, selectAny), args
 }
 
 // Explainer:
-//   (SELECT MAX(height), address FROM %s GROUP BY address) ->
-//       returns latest/max height for each address
-//   (height, address) IN (SELECT MAX(height), address FROM %s GROUP BY address) ->
-//       ensures the query is acting on max height for the addresses
-func ReadyToUnstake(unstakingHeight int64, tableName string) string {
+//
+//	(SELECT MAX(height), address FROM %s GROUP BY address) ->
+//	    returns latest/max height for each address
+//	(height, address) IN (SELECT MAX(height), address FROM %s GROUP BY address) ->
+//	    ensures the query is acting on max height for the addresses
+func ReadyToUnstake(unstakingHeight int64, tableName string) (string, []any) {
 	return fmt.Sprintf(`
 		SELECT address, staked_tokens, output_address
-		FROM %s WHERE unstaking_height=%d
+		FROM %s WHERE unstaking_height=$1
 			AND (height, address) IN (SELECT MAX(height), address FROM %s GROUP BY address)`,
-		tableName, unstakingHeight, tableName)
+		tableName, tableName), []any{unstakingHeight}
 }
 
+// NOTE: The chains of the actor are inserted separately (see `InsertChains`)
 func Insert(
 	actor BaseActor,
 	actorSpecificParam, actorSpecificParamValue,
-	constraintName, chainsConstraintName,
-	tableName, chainsTableName string,
-	height int64) string {
-	insertStatement := fmt.Sprintf(
-		`INSERT INTO %s (address, public_key, staked_tokens, %s, output_address, paused_height, unstaking_height, height)
-				VALUES('%s', '%s', '%s', '%s', '%s', %d, %d, %d)
-				ON CONFLICT ON CONSTRAINT %s
+	tableName string,
+	height int64) (string, []any) {
+	return fmt.Sprintf(
+			`INSERT INTO %s (address, public_key, staked_tokens, %s, output_address, paused_height, unstaking_height, height)
+				VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+				ON CONFLICT (address, height)
 				DO UPDATE SET staked_tokens=EXCLUDED.staked_tokens, %s=EXCLUDED.%s,
 							  paused_height=EXCLUDED.paused_height, unstaking_height=EXCLUDED.unstaking_height,
 							  height=EXCLUDED.height`,
-		tableName, actorSpecificParam,
-		actor.Address, actor.PublicKey, actor.StakedTokens, actorSpecificParamValue,
-		actor.OutputAddress, actor.PausedHeight, actor.UnstakingHeight, height,
-		constraintName,
-		actorSpecificParam, actorSpecificParam)
-
-	if actor.Chains == nil {
-		return insertStatement
-	}
-
-	return fmt.Sprintf("WITH baseTableInsert AS (%s)\n%s",
-		insertStatement, InsertChains(actor.Address, actor.Chains, height, chainsTableName, chainsConstraintName))
+			tableName, actorSpecificParam,
+			actorSpecificParam, actorSpecificParam),
+		[]any{
+			actor.Address, actor.PublicKey, actor.StakedTokens, actorSpecificParamValue,
+			actor.OutputAddress, actor.PausedHeight, actor.UnstakingHeight, height,
+		}
 }
 
-func InsertChains(address string, chains []string, height int64, tableName, constraintName string) string {
-	var buffer bytes.Buffer
-
-	buffer.WriteString(fmt.Sprintf("INSERT INTO %s (address, chain_id, height) VALUES", tableName))
-
-	maxIndex := len(chains) - 1
-	for i, chain := range chains {
-		buffer.WriteString(fmt.Sprintf("\n('%s', '%s', %d)", address, chain, height))
-		if i < maxIndex {
-			buffer.WriteString(",")
-		}
+// InsertChains inserts a row per chain, so it must not be called without chains.
+func InsertChains(address string, chains []string, height int64, tableName string) (string, []any) {
+	args := []any{address, height}
+	rows := make([]string, 0, len(chains))
+	for _, chain := range chains {
+		args = append(args, chain)
+		rows = append(rows, fmt.Sprintf("($1, $%d, $2)", len(args)))
 	}
-
-	buffer.WriteString(fmt.Sprintf("\nON CONFLICT ON CONSTRAINT %s DO NOTHING", constraintName))
-
-	return buffer.String()
+	return fmt.Sprintf(`INSERT INTO %s (address, chain_id, height) VALUES %s
+			ON CONFLICT (address, chain_id, height) DO NOTHING`,
+		tableName, strings.Join(rows, ", ")), args
 }
 
-func Update(address, stakedTokens, actorSpecificParam, actorSpecificParamValue string, height int64, tableName, constraintName string) string {
+// NOTE: The arguments in the select list of the `INSERT ... SELECT` queries below are cast explicitly since
+// their types cannot be inferred from the columns they are inserted into.
+
+func Update(address, stakedTokens, actorSpecificParam, actorSpecificParamValue string, height int64, tableName string) (string, []any) {
 	return fmt.Sprintf(
 		`INSERT INTO %s(address, public_key, staked_tokens, %s, output_address, paused_height, unstaking_height, height)
-			(
-				SELECT address, public_key, '%s', '%s', output_address, paused_height, unstaking_height, %d
-				FROM %s WHERE address='%s' AND height<=%d ORDER BY height DESC LIMIT 1
-			)
-		    ON CONFLICT ON CONSTRAINT %s
+			SELECT address, public_key, CAST($2 AS TEXT), CAST($3 AS TEXT), output_address, paused_height, unstaking_height, CAST($4 AS BIGINT)
+			FROM %s WHERE address=$1 AND height<=$4 ORDER BY height DESC LIMIT 1
+			ON CONFLICT (address, height)
 			DO UPDATE SET staked_tokens=EXCLUDED.staked_tokens, %s=EXCLUDED.%s, height=EXCLUDED.height`,
 		tableName, actorSpecificParam,
-		stakedTokens, actorSpecificParamValue, height,
-		tableName, address, height,
-		constraintName,
-		actorSpecificParam, actorSpecificParam)
+		tableName,
+		actorSpecificParam, actorSpecificParam), []any{address, stakedTokens, actorSpecificParamValue, height}
 }
 
-func UpdateUnstakingHeight(address, actorSpecificParam string, unstakingHeight, height int64, tableName, constraintName string) string {
+func UpdateUnstakingHeight(address, actorSpecificParam string, unstakingHeight, height int64, tableName string) (string, []any) {
 	return fmt.Sprintf(`
 		INSERT INTO %s(address, public_key, staked_tokens, %s, output_address, paused_height, unstaking_height, height)
-		(
-			SELECT address, public_key, staked_tokens, %s, output_address, paused_height, %d, %d
-			FROM %s WHERE address='%s' AND height<=%d ORDER BY height DESC LIMIT 1
-		)
-		ON CONFLICT ON CONSTRAINT %s
+		SELECT address, public_key, staked_tokens, %s, output_address, paused_height, CAST($2 AS BIGINT), CAST($3 AS BIGINT)
+		FROM %s WHERE address=$1 AND height<=$3 ORDER BY height DESC LIMIT 1
+		ON CONFLICT (address, height)
 			DO UPDATE SET unstaking_height=EXCLUDED.unstaking_height, height=EXCLUDED.height`,
 		tableName, actorSpecificParam,
-		actorSpecificParam, unstakingHeight, height,
-		tableName, address, height,
-		constraintName)
+		actorSpecificParam,
+		tableName), []any{address, unstakingHeight, height}
 }
 
-func UpdateStakeAmount(address, actorSpecificParam, stakeAmount string, height int64, tableName, constraintName string) string {
+func UpdateStakeAmount(address, actorSpecificParam, stakeAmount string, height int64, tableName string) (string, []any) {
 	return fmt.Sprintf(`
 		INSERT INTO %s(address, public_key, staked_tokens, %s, output_address, paused_height, unstaking_height, height)
-		(
-			SELECT address, public_key, '%s', %s, output_address, paused_height, unstaking_height, %d
-			FROM %s WHERE address='%s' AND height<=%d ORDER BY height DESC LIMIT 1
-		)
-		ON CONFLICT ON CONSTRAINT %s
+		SELECT address, public_key, CAST($2 AS TEXT), %s, output_address, paused_height, unstaking_height, CAST($3 AS BIGINT)
+		FROM %s WHERE address=$1 AND height<=$3 ORDER BY height DESC LIMIT 1
+		ON CONFLICT (address, height)
 			DO UPDATE SET staked_tokens=EXCLUDED.staked_tokens, height=EXCLUDED.height`,
 		tableName, actorSpecificParam,
-		stakeAmount, actorSpecificParam, height,
-		tableName, address, height,
-		constraintName)
+		actorSpecificParam,
+		tableName), []any{address, stakeAmount, height}
 }
 
-func UpdatePausedHeight(address, actorSpecificParam string, pausedHeight, height int64, tableName, constraintName string) string {
+func UpdatePausedHeight(address, actorSpecificParam string, pausedHeight, height int64, tableName string) (string, []any) {
 	return fmt.Sprintf(`
 		INSERT INTO %s(address, public_key, staked_tokens, %s, output_address, paused_height, unstaking_height, height)
-		(
-			SELECT address, public_key, staked_tokens, %s, output_address, %d, unstaking_height, %d
-			FROM %s WHERE address='%s' AND height<=%d ORDER BY height DESC LIMIT 1
-		)
-		ON CONFLICT ON CONSTRAINT %s
+		SELECT address, public_key, staked_tokens, %s, output_address, CAST($2 AS BIGINT), unstaking_height, CAST($3 AS BIGINT)
+		FROM %s WHERE address=$1 AND height<=$3 ORDER BY height DESC LIMIT 1
+		ON CONFLICT (address, height)
 			DO UPDATE SET paused_height=EXCLUDED.paused_height, height=EXCLUDED.height`,
 		tableName, actorSpecificParam, actorSpecificParam,
-		pausedHeight, height,
-		tableName, address, height, constraintName)
+		tableName), []any{address, pausedHeight, height}
 }
 
-func UpdateUnstakedHeightIfPausedBefore(actorSpecificParam string, unstakingHeight, pausedBeforeHeight, height int64, tableName, constraintName string) string {
+func UpdateUnstakedHeightIfPausedBefore(actorSpecificParam string, unstakingHeight, pausedBeforeHeight, height int64, tableName string) (string, []any) {
 	return fmt.Sprintf(`
 		INSERT INTO %s (address, public_key, staked_tokens, %s, output_address, paused_height, unstaking_height, height)
-		(
-			SELECT address, public_key, staked_tokens, %s, output_address, paused_height, %d, %d
-			FROM %s WHERE paused_height<%d
-				AND (height,address) IN (SELECT MAX(height),address from %s GROUP BY address)
-        )
-		ON CONFLICT ON CONSTRAINT %s
+		SELECT address, public_key, staked_tokens, %s, output_address, paused_height, CAST($1 AS BIGINT), CAST($2 AS BIGINT)
+		FROM %s WHERE paused_height<$3
+			AND (height,address) IN (SELECT MAX(height),address from %s GROUP BY address)
+		ON CONFLICT (address, height)
 			DO UPDATE SET unstaking_height=EXCLUDED.unstaking_height`,
 		tableName, actorSpecificParam,
-		actorSpecificParam, unstakingHeight, height,
-		tableName, pausedBeforeHeight,
+		actorSpecificParam,
 		tableName,
-		constraintName)
+		tableName), []any{unstakingHeight, height, pausedBeforeHeight}
 }
 
-func NullifyChains(address string, height int64, tableName string) string {
-	return fmt.Sprintf("DELETE FROM %s WHERE address='%s' AND height=%d", tableName, address, height)
+func NullifyChains(address string, height int64, tableName string) (string, []any) {
+	return fmt.Sprintf("DELETE FROM %s WHERE address=$1 AND height=$2", tableName), []any{address, height}
 }
 
 // Exposed for debugging purposes only
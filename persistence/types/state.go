@@ -0,0 +1,145 @@
+package types
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+
+	"github.com/celestiaorg/smt"
+	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
+	"google.golang.org/protobuf/proto"
+)
+
+// The state is committed to by a sparse merkle tree per type of state. The app hash is the hash of their
+// roots, concatenated in the order below, so the order must never change.
+type MerkleTree int
+
+const (
+	AppMerkleTree MerkleTree = iota
+	ValMerkleTree
+	FishMerkleTree
+	ServiceNodeMerkleTree
+	AccountMerkleTree
+	PoolMerkleTree
+	ParamsMerkleTree
+	FlagsMerkleTree
+
+	NumMerkleTrees
+)
+
+var merkleTreeNames = [NumMerkleTrees]string{
+	AppMerkleTree:         "app",
+	ValMerkleTree:         "val",
+	FishMerkleTree:        "fish",
+	ServiceNodeMerkleTree: "service_node",
+	AccountMerkleTree:     "account",
+	PoolMerkleTree:        "pool",
+	ParamsMerkleTree:      "params",
+	FlagsMerkleTree:       "flags",
+}
+
+func (tree MerkleTree) String() string {
+	if tree < 0 || tree >= NumMerkleTrees {
+		return fmt.Sprintf("unknown_merkle_tree_%d", int(tree))
+	}
+	return merkleTreeNames[tree]
+}
+
+func NewMerkleTreeHasher() hash.Hash {
+	return crypto.SHA3_256.New()
+}
+
+func GetAppHash(treeRoots [][]byte) []byte {
+	return cryptoPocket.SHA3Hash(bytes.Join(treeRoots, nil))
+}
+
+// GetActorMerkleValue returns the value of the actor's leaf in the merkle tree of its type.
+func GetActorMerkleValue(actor *Actor) ([]byte, error) {
+	// The chains are not stored in a deterministic order
+	sortedActor := proto.Clone(actor).(*Actor)
+	sort.Strings(sortedActor.Chains)
+	return proto.MarshalOptions{Deterministic: true}.Marshal(sortedActor)
+}
+
+// GetFlagMerkleValue returns the value of the flag's leaf in the flags merkle tree.
+func GetFlagMerkleValue(value string, enabled bool) []byte {
+	return []byte(fmt.Sprintf("%s,%t", value, enabled))
+}
+
+// VerifyStateProof verifies that `key` maps to `value` in the `tree` of the state committed to by `appHash`.
+// An empty value verifies that the key is not in the tree. It only depends on the proof so light clients can
+// verify the values returned by a node against the app hash of a block they trust.
+func VerifyStateProof(appHash []byte, tree MerkleTree, key, value, proofBz []byte) error {
+	if tree < 0 || tree >= NumMerkleTrees {
+		return fmt.Errorf("unknown merkle tree: %d", tree)
+	}
+	proof := new(StateProof)
+	if err := proto.Unmarshal(proofBz, proof); err != nil {
+		return err
+	}
+	if len(proof.TreeRoots) != int(NumMerkleTrees) {
+		return fmt.Errorf("expected %d merkle tree roots in the proof, got %d", NumMerkleTrees, len(proof.TreeRoots))
+	}
+	if !bytes.Equal(GetAppHash(proof.TreeRoots), appHash) {
+		return fmt.Errorf("the merkle tree roots of the proof do not hash to the app hash %s", hex.EncodeToString(appHash))
+	}
+	smtProof := smt.SparseMerkleProof{
+		SideNodes:             proof.SideNodes,
+		NonMembershipLeafData: proof.NonMembershipLeafData,
+		SiblingData:           proof.SiblingData,
+	}
+	if !smt.VerifyProof(smtProof, proof.TreeRoots[tree], key, value, NewMerkleTreeHasher()) {
+		return fmt.Errorf("invalid proof of key %s in the %s merkle tree", hex.EncodeToString(key), tree)
+	}
+	return nil
+}
+
+// VerifyAccountAmountProof verifies the amount of an account returned by `GetAccountAmountWithProof`.
+func VerifyAccountAmountProof(appHash, address []byte, amount string, proofBz []byte) error {
+	return verifyAmountProof(appHash, AccountMerkleTree, address, amount, proofBz)
+}
+
+// VerifyPoolAmountProof verifies the amount of a pool returned by `GetPoolAmountWithProof`.
+func VerifyPoolAmountProof(appHash []byte, name string, amount string, proofBz []byte) error {
+	return verifyAmountProof(appHash, PoolMerkleTree, []byte(name), amount, proofBz)
+}
+
+// Accounts and pools that do not exist have an amount of "0", which is proven by a non-membership proof.
+func verifyAmountProof(appHash []byte, tree MerkleTree, key []byte, amount string, proofBz []byte) error {
+	err := VerifyStateProof(appHash, tree, key, []byte(amount), proofBz)
+	if err != nil && amount == "0" {
+		if nonMembershipErr := VerifyStateProof(appHash, tree, key, nil, proofBz); nonMembershipErr == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// VerifyActorProof verifies an actor returned by `Get<Actor>WithProof`.
+func VerifyActorProof(appHash []byte, actor *Actor, proofBz []byte) error {
+	var tree MerkleTree
+	switch actor.ActorType {
+	case ActorType_App:
+		tree = AppMerkleTree
+	case ActorType_Val:
+		tree = ValMerkleTree
+	case ActorType_Fish:
+		tree = FishMerkleTree
+	case ActorType_Node:
+		tree = ServiceNodeMerkleTree
+	default:
+		return fmt.Errorf("unknown actor type: %s", actor.ActorType)
+	}
+	address, err := hex.DecodeString(actor.Address)
+	if err != nil {
+		return err
+	}
+	value, err := GetActorMerkleValue(actor)
+	if err != nil {
+		return err
+	}
+	return VerifyStateProof(appHash, tree, address, value, proofBz)
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/smt"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestVerifyStateProof(t *testing.T) {
+	trees := make([]*smt.SparseMerkleTree, NumMerkleTrees)
+	for i := range trees {
+		trees[i] = smt.NewSparseMerkleTree(smt.NewSimpleMap(), smt.NewSimpleMap(), NewMerkleTreeHasher())
+	}
+	_, err := trees[AccountMerkleTree].Update([]byte("address"), []byte("100"))
+	require.NoError(t, err)
+	_, err = trees[PoolMerkleTree].Update([]byte("pool"), []byte("200"))
+	require.NoError(t, err)
+
+	roots := make([][]byte, NumMerkleTrees)
+	for i, tree := range trees {
+		roots[i] = tree.Root()
+	}
+	appHash := GetAppHash(roots)
+	prove := func(tree MerkleTree, key []byte) []byte {
+		proof, err := trees[tree].Prove(key)
+		require.NoError(t, err)
+		proofBz, err := proto.Marshal(&StateProof{
+			SideNodes:             proof.SideNodes,
+			NonMembershipLeafData: proof.NonMembershipLeafData,
+			SiblingData:           proof.SiblingData,
+			TreeRoots:             roots,
+		})
+		require.NoError(t, err)
+		return proofBz
+	}
+
+	proof := prove(AccountMerkleTree, []byte("address"))
+	require.NoError(t, VerifyAccountAmountProof(appHash, []byte("address"), "100", proof))
+	require.Error(t, VerifyAccountAmountProof(appHash, []byte("address"), "101", proof))
+	require.Error(t, VerifyAccountAmountProof(appHash, []byte("other"), "100", proof))
+	require.Error(t, VerifyAccountAmountProof([]byte("wrong app hash"), []byte("address"), "100", proof))
+	// The proof is only valid for the tree it was generated for
+	require.Error(t, VerifyPoolAmountProof(appHash, "address", "100", proof))
+
+	require.NoError(t, VerifyPoolAmountProof(appHash, "pool", "200", prove(PoolMerkleTree, []byte("pool"))))
+
+	// Accounts that do not exist have an amount of "0"
+	nonMembershipProof := prove(AccountMerkleTree, []byte("unknown"))
+	require.NoError(t, VerifyAccountAmountProof(appHash, []byte("unknown"), "0", nonMembershipProof))
+	require.Error(t, VerifyAccountAmountProof(appHash, []byte("unknown"), "1", nonMembershipProof))
+}
+
+func TestVerifyActorProof(t *testing.T) {
+	tree := smt.NewSparseMerkleTree(smt.NewSimpleMap(), smt.NewSimpleMap(), NewMerkleTreeHasher())
+	actor := &Actor{
+		ActorType:    ActorType_App,
+		Address:      "0a1b",
+		StakedAmount: "1000",
+		Chains:       []string{"0002", "0001"},
+	}
+	value, err := GetActorMerkleValue(actor)
+	require.NoError(t, err)
+	_, err = tree.Update([]byte{0x0a, 0x1b}, value)
+	require.NoError(t, err)
+
+	roots := make([][]byte, NumMerkleTrees)
+	for i := range roots {
+		roots[i] = smt.NewSparseMerkleTree(smt.NewSimpleMap(), smt.NewSimpleMap(), NewMerkleTreeHasher()).Root()
+	}
+	roots[AppMerkleTree] = tree.Root()
+	proof, err := tree.Prove([]byte{0x0a, 0x1b})
+	require.NoError(t, err)
+	proofBz, err := proto.Marshal(&StateProof{SideNodes: proof.SideNodes, SiblingData: proof.SiblingData, TreeRoots: roots})
+	require.NoError(t, err)
+
+	// The order of the chains does not matter
+	actor.Chains = []string{"0001", "0002"}
+	require.NoError(t, VerifyActorProof(GetAppHash(roots), actor, proofBz))
+
+	actor.StakedAmount = "1001"
+	require.Error(t, VerifyActorProof(GetAppHash(roots), actor, proofBz))
+}
@@ -25,7 +25,7 @@ var ValidatorActor ProtocolActorSchema = &ValidatorSchema{
 	},
 }
 
-func (actor *ValidatorSchema) InsertQuery(address, publicKey, stakedTokens, maxRelays, outputAddress string, pausedHeight, unstakingHeight int64, _ []string, height int64) string {
+func (actor *ValidatorSchema) InsertQuery(address, publicKey, stakedTokens, maxRelays, outputAddress string, pausedHeight, unstakingHeight int64, height int64) (string, []any) {
 	return Insert(BaseActor{
 		Address:         address,
 		PublicKey:       publicKey,
@@ -35,14 +35,15 @@ func (actor *ValidatorSchema) InsertQuery(address, publicKey, stakedTokens, maxR
 		UnstakingHeight: unstakingHeight,
 	},
 		actor.actorSpecificColName, maxRelays,
-		actor.heightConstraintName, NullString,
-		actor.tableName, NullString,
+		actor.tableName,
 		height)
 }
 
-func (actor *ValidatorSchema) UpdateChainsQuery(_ string, _ []string, _ int64) string {
+func (actor *ValidatorSchema) UpdateChainsQuery(_ string, _ []string, _ int64) (string, []any) {
 	panic(ValidatorPanicMsg)
 }
-func (actor *ValidatorSchema) GetChainsTableSchema() string            { panic(ValidatorPanicMsg) }
-func (actor *ValidatorSchema) GetChainsQuery(_ string, _ int64) string { panic(ValidatorPanicMsg) }
-func (actor *ValidatorSchema) ClearAllChainsQuery() string             { panic(ValidatorPanicMsg) }
+func (actor *ValidatorSchema) GetChainsTableSchema() string { panic(ValidatorPanicMsg) }
+func (actor *ValidatorSchema) GetChainsQuery(_ string, _ int64) (string, []any) {
+	panic(ValidatorPanicMsg)
+}
+func (actor *ValidatorSchema) ClearAllChainsQuery() string { panic(ValidatorPanicMsg) }
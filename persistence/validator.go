@@ -23,6 +23,10 @@ func (p PostgresContext) GetValidator(address []byte, height int64) (operator, p
 	return
 }
 
+func (p PostgresContext) GetValidatorWithProof(address []byte, height int64) (modules.Actor, []byte, error) {
+	return p.getActorWithProof(types.ValMerkleTree, address, height)
+}
+
 func (p PostgresContext) InsertValidator(address []byte, publicKey []byte, output []byte, _ bool, _ int32, serviceURL string, stakedTokens string, pausedHeight int64, unstakingHeight int64) error {
 	return p.InsertActor(types.ValidatorActor, types.BaseActor{
 		Address:            hex.EncodeToString(address),
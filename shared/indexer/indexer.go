@@ -3,6 +3,7 @@
 package indexer
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"github.com/pokt-network/pocket/shared/codec"
@@ -33,6 +34,15 @@ type TxIndexer interface {
 	// GetByRecipient returns all transactions *sent to address*; may be ordered descending/ascending
 	GetByRecipient(recipient string, descending bool) ([]TxResult, error)
 
+	// `GetByMessageType` returns a page of the transactions of that message type
+	GetByMessageType(messageType string, pagination *Pagination) (*TxPage, error)
+
+	// `GetByHeightRange` returns a page of the transactions between both heights (inclusive)
+	GetByHeightRange(fromHeight, toHeight int64, pagination *Pagination) (*TxPage, error)
+
+	// `Search` returns a page of the transactions matching all the criteria of the filter
+	Search(filter *TxFilter, pagination *Pagination) (*TxPage, error)
+
 	// Close stops the underlying db connection
 	Close() error
 }
@@ -47,12 +57,39 @@ type TxResult interface {
 	GetSignerAddr() string                // get the address of who signed (i.e. sent) the transaction
 	GetRecipientAddr() string             // get the address of who received the transaction; may be empty
 	GetMessageType() string               // corresponds to type of message (validator-stake, app-unjail, node-stake, etc) // IMPROVE: Add an enum for message types
-	Hash() ([]byte, error)                // the hash of the tx bytes
-	HashFromBytes([]byte) ([]byte, error) // same operation as `Hash`, but avoid re-serializing the tx
+	Hash() ([]byte, error)                // the hash of the tx bytes, which identifies the transaction (e.g. for replay protection)
+	HashFromBytes([]byte) ([]byte, error) // same operation as `Hash`, but from the given tx bytes
 	Bytes() ([]byte, error)               // returns the serialized transaction bytes
 	FromBytes([]byte) (TxResult, error)   // returns the deserialized transaction result
 }
 
+// `TxFilter` selects the transactions matching all its criteria; the empty ones match any transaction
+type TxFilter struct {
+	Sender      string
+	Recipient   string
+	MessageType string
+	HeightRange *HeightRange
+}
+
+// `HeightRange` selects the transactions between both heights (inclusive)
+type HeightRange struct {
+	FromHeight int64
+	ToHeight   int64
+}
+
+// `Pagination` selects a page of the results of a query, which are ordered by height and index. A nil
+// `Pagination` selects all the results in ascending order.
+type Pagination struct {
+	Cursor     []byte // the `NextCursor` of the previous page, or nil for the first page
+	Limit      int    // the maximum number of results in the page; unlimited if zero
+	Descending bool
+}
+
+type TxPage struct {
+	Results    []TxResult
+	NextCursor []byte // resumes the query after the last result of the page; nil if it is the last page
+}
+
 // Implementation
 
 var _ TxResult = &DefaultTxResult{}
@@ -63,10 +100,14 @@ var _ TxIndexer = &txIndexer{}
 // `txIndexer` implementation uses a `KVStore` (interface) to index the transactions
 //
 // The transaction is indexed in the following formats:
-// - HASHKEY:      "h/SHA3(TxResultProtoBytes)"  VAL: TxResultProtoBytes     // store value by hash (the key here is equivalent to the VALs below)
-// - HEIGHTKEY:    "b/height/index"              VAL: HASHKEY                // store hashKey by height
-// - SENDERKEY:    "s/senderAddr"                VAL: HASHKEY                // store hashKey by sender
-// - RECIPIENTKEY: "r/recipientAddr"             VAL: HASHKEY                // store hashKey by recipient (if not empty)
+// - HASHKEY:      "h/SHA3(TxProtoBytes)"                 VAL: TxResultProtoBytes     // store value by hash (the key here is equivalent to the VALs below)
+// - HEIGHTKEY:    "b/height/index"                       VAL: HASHKEY                // store hashKey by height
+// - SENDERKEY:    "s/senderAddr/height/index"            VAL: HASHKEY                // store hashKey by sender
+// - RECIPIENTKEY: "r/recipientAddr/height/index"         VAL: HASHKEY                // store hashKey by recipient (if not empty)
+// - MSGTYPEKEY:   "m/messageType/height/index"           VAL: HASHKEY                // store hashKey by message type
+//
+// Every transaction of an address or message type is retained, ordered by its position (i.e. height and index).
+// The cursor of a page is the position of its last result.
 //
 // FOOTNOTE: the height/index store is using [ELEN](https://github.com/jordanorelli/lexnum/blob/master/elen.pdf)
 // This is to ensure the results are stored sorted (assuming the `KVStore`` uses a byte-wise lexicographical sorting)
@@ -76,6 +117,7 @@ const (
 	heightPrefix    = 'b' // b for block
 	senderPrefix    = 's'
 	recipientPrefix = 'r'
+	msgTypePrefix   = 'm'
 )
 
 // =,- are the default parameters in the [example repository](https://github.com/jordanorelli/lexnum#example)
@@ -95,11 +137,7 @@ func (*DefaultTxResult) FromBytes(bz []byte) (TxResult, error) {
 }
 
 func (x *DefaultTxResult) Hash() ([]byte, error) {
-	bz, err := x.Bytes()
-	if err != nil {
-		return nil, err
-	}
-	return x.HashFromBytes(bz)
+	return x.HashFromBytes(x.GetTx())
 }
 
 func (x *DefaultTxResult) HashFromBytes(bz []byte) ([]byte, error) {
@@ -123,12 +161,19 @@ func NewMemTxIndexer() (TxIndexer, error) {
 	}, nil
 }
 
+// NewTxIndexerFromKVStore indexes the transactions in a store owned by the caller, which may clear it.
+func NewTxIndexerFromKVStore(db kvstore.KVStore) TxIndexer {
+	return &txIndexer{
+		db: db,
+	}
+}
+
 func (indexer *txIndexer) Index(result TxResult) error {
 	bz, err := result.Bytes()
 	if err != nil {
 		return err
 	}
-	hash, err := result.HashFromBytes(bz)
+	hash, err := result.Hash()
 	if err != nil {
 		return err
 	}
@@ -139,10 +184,14 @@ func (indexer *txIndexer) Index(result TxResult) error {
 	if err := indexer.indexByHeightAndIndex(result.GetHeight(), result.GetIndex(), hashKey); err != nil {
 		return err
 	}
-	if err := indexer.indexBySender(result.GetSignerAddr(), hashKey); err != nil {
+	position := positionKey(result.GetHeight(), result.GetIndex())
+	if err := indexer.indexBySender(result.GetSignerAddr(), position, hashKey); err != nil {
 		return err
 	}
-	if err := indexer.indexByRecipient(result.GetRecipientAddr(), hashKey); err != nil {
+	if err := indexer.indexByRecipient(result.GetRecipientAddr(), position, hashKey); err != nil {
+		return err
+	}
+	if err := indexer.indexByMessageType(result.GetMessageType(), position, hashKey); err != nil {
 		return err
 	}
 	return nil
@@ -164,10 +213,104 @@ func (indexer *txIndexer) GetByRecipient(recipient string, descending bool) ([]T
 	return indexer.getAll(indexer.recipientKey(recipient), descending)
 }
 
+func (indexer *txIndexer) GetByMessageType(messageType string, pagination *Pagination) (*TxPage, error) {
+	return indexer.Search(&TxFilter{MessageType: messageType}, pagination)
+}
+
+func (indexer *txIndexer) GetByHeightRange(fromHeight, toHeight int64, pagination *Pagination) (*TxPage, error) {
+	return indexer.Search(&TxFilter{HeightRange: &HeightRange{FromHeight: fromHeight, ToHeight: toHeight}}, pagination)
+}
+
+func (indexer *txIndexer) Search(filter *TxFilter, pagination *Pagination) (*TxPage, error) {
+	if filter == nil {
+		filter = &TxFilter{}
+	}
+	if pagination == nil {
+		pagination = &Pagination{}
+	}
+	start, end := indexer.searchRange(filter, pagination)
+	it, err := indexer.db.Iterator(start, end, pagination.Descending)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	page := new(TxPage)
+	for ; it.Valid(); it.Next() {
+		txResult, err := indexer.get(it.Value())
+		if err != nil {
+			return nil, err
+		}
+		if !filter.matches(txResult) {
+			continue
+		}
+		if pagination.Limit > 0 && len(page.Results) == pagination.Limit {
+			last := page.Results[len(page.Results)-1]
+			page.NextCursor = positionKey(last.GetHeight(), last.GetIndex())
+			break
+		}
+		page.Results = append(page.Results, txResult)
+	}
+	return page, it.Error()
+}
+
 func (indexer *txIndexer) Close() error {
 	return indexer.db.Stop()
 }
 
+// searchRange returns the range of keys, within the most selective index the filter can be answered from, of the
+// transactions in the height range of the filter that come after the cursor of the pagination.
+func (indexer *txIndexer) searchRange(filter *TxFilter, pagination *Pagination) (start, end []byte) {
+	var prefix []byte
+	switch {
+	case filter.Sender != "":
+		prefix = indexer.senderKey(filter.Sender)
+	case filter.Recipient != "":
+		prefix = indexer.recipientKey(filter.Recipient)
+	case filter.MessageType != "":
+		prefix = indexer.msgTypeKey(filter.MessageType)
+	default:
+		prefix = indexer.key(heightPrefix, "")
+	}
+	// The keys of every index are the prefix followed by the position of the transaction
+	positionedKey := func(position []byte) []byte {
+		return append(append([]byte{}, prefix...), position...)
+	}
+
+	start, end = prefix, kvstore.PrefixEndBytes(prefix)
+	if r := filter.HeightRange; r != nil {
+		start = positionedKey([]byte(elenEncoder.EncodeInt(int(r.FromHeight)) + "/"))
+		end = positionedKey([]byte(elenEncoder.EncodeInt(int(r.ToHeight+1)) + "/"))
+	}
+	if pagination.Cursor != nil {
+		cursorKey := positionedKey(pagination.Cursor)
+		if pagination.Descending && bytes.Compare(cursorKey, end) < 0 {
+			end = cursorKey
+		}
+		// The smallest key following the cursor's
+		if afterCursorKey := append(cursorKey, 0); !pagination.Descending && bytes.Compare(afterCursorKey, start) > 0 {
+			start = afterCursorKey
+		}
+	}
+	return start, end
+}
+
+func (filter *TxFilter) matches(txResult TxResult) bool {
+	if filter.Sender != "" && txResult.GetSignerAddr() != filter.Sender {
+		return false
+	}
+	if filter.Recipient != "" && txResult.GetRecipientAddr() != filter.Recipient {
+		return false
+	}
+	if filter.MessageType != "" && txResult.GetMessageType() != filter.MessageType {
+		return false
+	}
+	if r := filter.HeightRange; r != nil && (txResult.GetHeight() < r.FromHeight || txResult.GetHeight() > r.ToHeight) {
+		return false
+	}
+	return true
+}
+
 // kv helper functions
 
 func (indexer *txIndexer) getAll(prefix []byte, descending bool) (result []TxResult, err error) {
@@ -205,15 +348,22 @@ func (indexer *txIndexer) indexByHeightAndIndex(height int64, index int32, bz []
 	return indexer.db.Put(indexer.heightAndIndexKey(height, index), bz)
 }
 
-func (indexer *txIndexer) indexBySender(sender string, bz []byte) error {
-	return indexer.db.Put(indexer.senderKey(sender), bz)
+func (indexer *txIndexer) indexBySender(sender string, position, bz []byte) error {
+	return indexer.db.Put(append(indexer.senderKey(sender), position...), bz)
 }
 
-func (indexer *txIndexer) indexByRecipient(recipient string, bz []byte) error {
+func (indexer *txIndexer) indexByRecipient(recipient string, position, bz []byte) error {
 	if recipient == "" {
 		return nil
 	}
-	return indexer.db.Put(indexer.recipientKey(recipient), bz)
+	return indexer.db.Put(append(indexer.recipientKey(recipient), position...), bz)
+}
+
+func (indexer *txIndexer) indexByMessageType(messageType string, position, bz []byte) error {
+	if messageType == "" {
+		return nil
+	}
+	return indexer.db.Put(append(indexer.msgTypeKey(messageType), position...), bz)
 }
 
 // key helper functions
@@ -223,19 +373,29 @@ func (indexer *txIndexer) hashKey(hash []byte) []byte {
 }
 
 func (indexer *txIndexer) heightAndIndexKey(height int64, index int32) []byte {
-	return append(indexer.heightKey(height), []byte(elenEncoder.EncodeInt(int(index)))...)
+	return indexer.key(heightPrefix, string(positionKey(height, index)))
 }
 
 func (indexer *txIndexer) heightKey(height int64) []byte {
 	return indexer.key(heightPrefix, elenEncoder.EncodeInt(int(height))+"/")
 }
 
+// The sender, recipient and message type keys end with a separator so they are not prefixes of one another
 func (indexer *txIndexer) senderKey(address string) []byte {
-	return indexer.key(senderPrefix, address)
+	return indexer.key(senderPrefix, address+"/")
 }
 
 func (indexer *txIndexer) recipientKey(address string) []byte {
-	return indexer.key(recipientPrefix, address)
+	return indexer.key(recipientPrefix, address+"/")
+}
+
+func (indexer *txIndexer) msgTypeKey(messageType string) []byte {
+	return indexer.key(msgTypePrefix, messageType+"/")
+}
+
+// positionKey orders the transactions by height and then index within the block.
+func positionKey(height int64, index int32) []byte {
+	return []byte(elenEncoder.EncodeInt(int(height)) + "/" + elenEncoder.EncodeInt(int(index)))
 }
 
 func (indexer *txIndexer) key(prefix rune, postfix string) []byte {
@@ -182,6 +182,132 @@ func TestGetByRecipient(t *testing.T) {
 	require.Equal(t, 0, len(txResultsFromSenderBad))
 }
 
+func TestGetBySender_RetainsEveryTransaction(t *testing.T) {
+	txIndexer, err := NewMemTxIndexer()
+	require.NoError(t, err)
+	defer txIndexer.Close()
+	// setup 3 transactions from the same sender, indexed out of order
+	txResult := NewTestingTransactionResult(t, 1, 1)
+	sender := txResult.GetSignerAddr()
+	txResult2 := newTestingTransactionResultFrom(t, sender, 2, 0)
+	txResult3 := newTestingTransactionResultFrom(t, sender, 1, 0)
+	require.NoError(t, txIndexer.Index(txResult))
+	require.NoError(t, txIndexer.Index(txResult2))
+	require.NoError(t, txIndexer.Index(txResult3))
+	// check every transaction is retained, ordered by height and index
+	txResultsFromSender, err := txIndexer.GetBySender(sender, false)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(txResultsFromSender))
+	requireTxResultsEqual(t, txResult3, txResultsFromSender[0])
+	requireTxResultsEqual(t, txResult, txResultsFromSender[1])
+	requireTxResultsEqual(t, txResult2, txResultsFromSender[2])
+	txResultsFromSender, err = txIndexer.GetBySender(sender, true)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(txResultsFromSender))
+	requireTxResultsEqual(t, txResult2, txResultsFromSender[0])
+	// check a sender whose address extends the first one is not mixed up with it
+	txResultsFromPrefix, err := txIndexer.GetBySender(sender[:len(sender)-1], false)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(txResultsFromPrefix))
+}
+
+func TestGetByMessageType(t *testing.T) {
+	txIndexer, err := NewMemTxIndexer()
+	require.NoError(t, err)
+	defer txIndexer.Close()
+	// setup 3 transactions, 2 of which are sends
+	txResults := []*DefaultTxResult{
+		NewTestingTransactionResult(t, 0, 0).(*DefaultTxResult),
+		NewTestingTransactionResult(t, 0, 1).(*DefaultTxResult),
+		NewTestingTransactionResult(t, 1, 0).(*DefaultTxResult),
+	}
+	txResults[0].MessageType = SendMessage.String()
+	txResults[1].MessageType = StakeMessage.String()
+	txResults[2].MessageType = SendMessage.String()
+	for _, txResult := range txResults {
+		require.NoError(t, txIndexer.Index(txResult))
+	}
+	// check indexing/get by message type
+	page, err := txIndexer.GetByMessageType(SendMessage.String(), nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(page.Results))
+	requireTxResultsEqual(t, txResults[0], page.Results[0])
+	requireTxResultsEqual(t, txResults[2], page.Results[1])
+	require.Nil(t, page.NextCursor)
+}
+
+func TestGetByHeightRange(t *testing.T) {
+	txIndexer, err := NewMemTxIndexer()
+	require.NoError(t, err)
+	defer txIndexer.Close()
+	// setup a transaction at each height from 0 to 9
+	for height := 0; height < 10; height++ {
+		require.NoError(t, txIndexer.Index(NewTestingTransactionResult(t, height, 0)))
+	}
+	// check the range is inclusive on both ends
+	page, err := txIndexer.GetByHeightRange(3, 5, nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(page.Results))
+	require.Equal(t, int64(3), page.Results[0].GetHeight())
+	require.Equal(t, int64(5), page.Results[2].GetHeight())
+	page, err = txIndexer.GetByHeightRange(3, 5, &Pagination{Descending: true})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(page.Results))
+	require.Equal(t, int64(5), page.Results[0].GetHeight())
+	// check a range of a single height
+	page, err = txIndexer.GetByHeightRange(7, 7, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(page.Results))
+	require.Equal(t, int64(7), page.Results[0].GetHeight())
+}
+
+func TestSearch_CombinedFiltersAndPagination(t *testing.T) {
+	txIndexer, err := NewMemTxIndexer()
+	require.NoError(t, err)
+	defer txIndexer.Close()
+	// setup 12 transactions from the same sender at heights 0 to 11, every other one being a send
+	sender := randomAddress(t)
+	for height := 0; height < 12; height++ {
+		txResult := newTestingTransactionResultFrom(t, sender, height, 0)
+		txResult.MessageType = StakeMessage.String()
+		if height%2 == 0 {
+			txResult.MessageType = SendMessage.String()
+		}
+		require.NoError(t, txIndexer.Index(txResult))
+	}
+	// an unrelated send
+	require.NoError(t, txIndexer.Index(NewTestingTransactionResult(t, 4, 1)))
+	filter := &TxFilter{
+		Sender:      sender,
+		MessageType: SendMessage.String(),
+		HeightRange: &HeightRange{FromHeight: 1, ToHeight: 10},
+	}
+	for _, descending := range []bool{false, true} {
+		// check the sends from the sender at heights 2, 4, 6, 8 and 10 are paged 2 by 2
+		expectedHeights := []int64{2, 4, 6, 8, 10}
+		if descending {
+			expectedHeights = []int64{10, 8, 6, 4, 2}
+		}
+		var heights []int64
+		pagination := &Pagination{Limit: 2, Descending: descending}
+		for numPages := 1; ; numPages++ {
+			page, err := txIndexer.Search(filter, pagination)
+			require.NoError(t, err)
+			for _, txResult := range page.Results {
+				require.Equal(t, sender, txResult.GetSignerAddr())
+				heights = append(heights, txResult.GetHeight())
+			}
+			if page.NextCursor == nil {
+				require.Equal(t, 3, numPages)
+				break
+			}
+			require.Equal(t, 2, len(page.Results))
+			pagination.Cursor = page.NextCursor
+		}
+		require.Equal(t, expectedHeights, heights)
+	}
+}
+
 func requireTxResultsEqual(t *testing.T, txR1, txR2 TxResult) {
 	bz, err := txR1.Bytes()
 	require.NoError(t, err)
@@ -207,6 +333,12 @@ func NewTestingTransactionResult(t *testing.T, height, index int) TxResult {
 	}
 }
 
+func newTestingTransactionResultFrom(t *testing.T, sender string, height, index int) *DefaultTxResult {
+	txResult := NewTestingTransactionResult(t, height, index).(*DefaultTxResult)
+	txResult.SignerAddr = sender
+	return txResult
+}
+
 type MessageType int
 
 const (
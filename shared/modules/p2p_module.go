@@ -5,6 +5,7 @@ package modules
 import (
 	cryptoPocket "github.com/pokt-network/pocket/shared/crypto"
 	"github.com/pokt-network/pocket/shared/debug"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
@@ -13,4 +14,35 @@ type P2PModule interface {
 	Broadcast(msg *anypb.Any, topic debug.PocketTopic) error                       // TECHDEBT: get rid of topic
 	Send(addr cryptoPocket.Address, msg *anypb.Any, topic debug.PocketTopic) error // TECHDEBT: get rid of topic
 	GetAddress() (cryptoPocket.Address, error)
+
+	// Publish sends the message to the network using the propagation strategy of the topic.
+	Publish(msg *anypb.Any, topic debug.PocketTopic) error
+	// Subscribe registers a handler for the messages received on `topic`. Inbound messages on topics
+	// with at least one subscriber are routed directly to them rather than being published to the bus.
+	Subscribe(topic debug.PocketTopic, handler P2PMessageHandler) error
+	// SetPropagationStrategy configures how the messages published on `topic` reach other nodes.
+	SetPropagationStrategy(topic debug.PocketTopic, strategy PropagationStrategy)
+
+	// Request sends `req` to a peer and blocks until it responds, retrying on errors and timeouts. If `addr`
+	// is nil, a different peer is picked at random from the address book for every attempt.
+	Request(addr cryptoPocket.Address, req *anypb.Any) (*anypb.Any, error)
+	// RegisterRequestHandler registers the handler of the requests whose data is of the same type as `req`.
+	RegisterRequestHandler(req proto.Message, handler P2PRequestHandler) error
+
+	HandleDebugMessage(*debug.DebugMessage) error
 }
+
+type P2PMessageHandler func(msg *anypb.Any) error
+
+type P2PRequestHandler func(req *anypb.Any) (*anypb.Any, error)
+
+type PropagationStrategy int
+
+const (
+	// Messages reach every node in the network (e.g. via RainTree). This is the default for all topics.
+	PropagationBroadcast PropagationStrategy = iota
+	// Messages reach a random subset of the peers of the node
+	PropagationGossip
+	// Messages can only be sent to a specific peer via `Send`
+	PropagationDirect
+)
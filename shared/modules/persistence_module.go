@@ -3,14 +3,20 @@ package modules
 //go:generate mockgen -source=$GOFILE -destination=./mocks/persistence_module_mock.go -aux_files=github.com/pokt-network/pocket/shared/modules=module.go
 
 import (
+	"context"
+
 	"github.com/pokt-network/pocket/persistence/kvstore"
 	"github.com/pokt-network/pocket/shared/debug"
+	"github.com/pokt-network/pocket/shared/indexer"
 )
 
 type PersistenceModule interface {
 	Module
 	NewRWContext(height int64) (PersistenceRWContext, error)
 	NewReadContext(height int64) (PersistenceReadContext, error)
+	// The queries of the contexts created with `ctx` are aborted once it is done
+	NewRWContextWithCtx(ctx context.Context, height int64) (PersistenceRWContext, error)
+	NewReadContextWithCtx(ctx context.Context, height int64) (PersistenceReadContext, error)
 
 	// TODO(drewsky): Make this a context function only and do not expose it at the module level.
 	//                The reason `Olshansky` originally made it a module level function is because
@@ -20,6 +26,18 @@ type PersistenceModule interface {
 	ResetContext() error
 	GetBlockStore() kvstore.KVStore
 
+	// Block Store Queries of the committed blocks
+	GetBlockByHeight(height uint64) (blockProtoBytes []byte, err error)
+	GetBlockByHash(hash string) (blockProtoBytes []byte, err error)
+	// Calls `fn` with the blocks from `fromHeight` to `toHeight` (inclusive), in ascending order, until it returns false
+	IterateBlocks(fromHeight, toHeight uint64, fn func(height uint64, blockProtoBytes []byte) bool) error
+	// Returns `kvstore.ErrKeyNotFound` if no block has been committed yet
+	GetLatestBlock() (height uint64, blockProtoBytes []byte, quorumCert []byte, err error)
+
+	// Transaction Index Queries of the committed transactions
+	// Returns the result of the transaction whose hex encoded hash is `hash`, or `kvstore.ErrKeyNotFound`
+	GetTransactionByHash(hash string) (txResult indexer.TxResult, err error)
+
 	// Debugging / development only
 	HandleDebugMessage(*debug.DebugMessage) error
 }
@@ -48,6 +66,7 @@ type PersistenceWriteContext interface {
 	// Context Operations
 	NewSavePoint([]byte) error
 	RollbackToSavePoint([]byte) error
+	ReleaseSavePoint([]byte) error
 
 	Reset() error
 	Commit() error
@@ -58,13 +77,14 @@ type PersistenceWriteContext interface {
 	// Block Operations
 
 	// Indexer Operations
-	StoreTransaction(transactionProtoBytes []byte) error
+	// Stages the result of an applied transaction, which is indexed once the context is committed
+	StoreTransaction(txResult indexer.TxResult) error
 
 	// Block Operations
 	// TODO_TEMPORARY: Including two functions for the SQL and KV Store as an interim solution
 	//                 until we include the schema as part of the SQL Store because persistence
 	//                 currently has no access to the protobuf schema which is the source of truth.
-	StoreBlock(blockProtoBytes []byte) error                                              // Store the block in the KV Store
+	StoreBlock(blockProtoBytes []byte, hash string, quorumCert []byte) error              // Store the block in the KV Store
 	InsertBlock(height uint64, hash string, proposerAddr []byte, quorumCert []byte) error // Writes the block in the SQL database
 
 	// Pool Operations
@@ -133,25 +153,34 @@ type PersistenceReadContext interface {
 	// Block Queries
 	GetLatestBlockHeight() (uint64, error)
 	GetBlockHash(height int64) ([]byte, error)
+	GetBlock(height int64) (blockProtoBytes []byte, err error)
 	GetBlocksPerSession(height int64) (int, error)
 
 	// Indexer Queries
 	TransactionExists(transactionHash string) (bool, error)
 
+	// Merkle Proofs
+	// The `WithProof` queries also return a serialized `StateProof` of the value against the app hash of the
+	// height, which can be verified with `VerifyStateProof` in `persistence/types`. Proofs are only available
+	// for committed heights.
+
 	// Pool Queries
 
 	// Returns "0" if the account does not exist
 	GetPoolAmount(name string, height int64) (amount string, err error)
+	GetPoolAmountWithProof(name string, height int64) (amount string, proof []byte, err error)
 	GetAllPools(height int64) ([]Account, error)
 
 	// Account Queries
 
 	// Returns "0" if the account does not exist
 	GetAccountAmount(address []byte, height int64) (string, error)
+	GetAccountAmountWithProof(address []byte, height int64) (amount string, proof []byte, err error)
 	GetAllAccounts(height int64) ([]Account, error)
 
 	// App Queries
 	GetAllApps(height int64) ([]Actor, error)
+	GetAppWithProof(address []byte, height int64) (actor Actor, proof []byte, err error) // Returns a nil actor if it does not exist
 	GetAppExists(address []byte, height int64) (exists bool, err error)
 	GetAppStakeAmount(height int64, address []byte) (string, error)
 	GetAppsReadyToUnstake(height int64, status int32) (apps []IUnstakingActor, err error)
@@ -161,6 +190,7 @@ type PersistenceReadContext interface {
 
 	// ServiceNode Queries
 	GetAllServiceNodes(height int64) ([]Actor, error)
+	GetServiceNodeWithProof(address []byte, height int64) (actor Actor, proof []byte, err error) // Returns a nil actor if it does not exist
 	GetServiceNodeExists(address []byte, height int64) (exists bool, err error)
 	GetServiceNodeStakeAmount(height int64, address []byte) (string, error)
 	GetServiceNodesReadyToUnstake(height int64, status int32) (serviceNodes []IUnstakingActor, err error)
@@ -172,6 +202,7 @@ type PersistenceReadContext interface {
 
 	// Fisherman Queries
 	GetAllFishermen(height int64) ([]Actor, error)
+	GetFishermanWithProof(address []byte, height int64) (actor Actor, proof []byte, err error) // Returns a nil actor if it does not exist
 	GetFishermanExists(address []byte, height int64) (exists bool, err error)
 	GetFishermanStakeAmount(height int64, address []byte) (string, error)
 	GetFishermenReadyToUnstake(height int64, status int32) (fishermen []IUnstakingActor, err error)
@@ -181,6 +212,7 @@ type PersistenceReadContext interface {
 
 	// Validator Queries
 	GetAllValidators(height int64) ([]Actor, error)
+	GetValidatorWithProof(address []byte, height int64) (actor Actor, proof []byte, err error) // Returns a nil actor if it does not exist
 	GetValidatorExists(address []byte, height int64) (exists bool, err error)
 	GetValidatorStakeAmount(height int64, address []byte) (string, error)
 	GetValidatorsReadyToUnstake(height int64, status int32) (validators []IUnstakingActor, err error)
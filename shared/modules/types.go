@@ -42,12 +42,27 @@ type PersistenceConfig interface {
 	GetPostgresUrl() string
 	GetNodeSchema() string
 	GetBlockStorePath() string
+	GetTreeStorePath() string
+	GetMaxConnsCount() int32
+	GetMinConnsCount() int32
+	GetStatementTimeoutMsec() uint64
+	GetContextTimeoutMsec() uint64
+	GetDatabaseBackend() string
+	GetSqlitePath() string
+	GetTxIndexerPath() string
+	GetPruningStrategy() string
+	GetPruningKeepRecent() uint64
+	GetPruningKeepEvery() uint64
+	GetPruningIntervalMsec() uint64
 }
 
 type P2PConfig interface {
 	GetConsensusPort() uint32
 	GetUseRainTree() bool
 	IsEmptyConnType() bool // TODO (team) make enum
+	GetMaxMessageSizeBytes() uint64
+	GetListenAddresses() []string
+	GetAdvertisedAddresses() []string
 }
 
 type TelemetryConfig interface {
@@ -6,6 +6,11 @@ type UtilityModule interface {
 	Module
 
 	NewContext(height int64) (UtilityContext, error)
+
+	// Mempool operations
+	GetMempoolTransaction(txHash string) (tx []byte, found bool)
+	// HandleTransaction validates a transaction submitted to the node, adds it to the mempool and gossips it to peers
+	HandleTransaction(tx []byte) error
 }
 
 // Interface defining the context within which the node can operate with the utility layer.
@@ -82,6 +82,10 @@ func (node *Node) Start() error {
 		return err
 	}
 
+	if err := node.subscribeToP2PTopics(); err != nil {
+		return err
+	}
+
 	if err := node.GetBus().GetP2PModule().Start(); err != nil {
 		return err
 	}
@@ -125,6 +129,27 @@ func (m *Node) GetBus() modules.Bus {
 	return m.bus
 }
 
+// Messages received from the network on these topics are handed over to the main loop rather than handled on the
+// P2P dispatcher, since consensus is not thread safe and is also driven by the events of the main loop (e.g. its
+// timers and debug events). `handleEvent` is the only entry point into consensus.
+func (node *Node) subscribeToP2PTopics() error {
+	p2pMod := node.GetBus().GetP2PModule()
+	for _, topic := range []debug.PocketTopic{debug.PocketTopic_CONSENSUS_MESSAGE_TOPIC, debug.PocketTopic_DEBUG_TOPIC} {
+		if err := p2pMod.Subscribe(topic, node.forwardToBus(topic)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forwardToBus returns a P2P message handler publishing the messages of the topic to the bus.
+func (node *Node) forwardToBus(topic debug.PocketTopic) modules.P2PMessageHandler {
+	return func(msg *anypb.Any) error {
+		node.GetBus().PublishEventToBus(&debug.PocketEvent{Topic: topic, Data: msg})
+		return nil
+	}
+}
+
 func (node *Node) handleEvent(event *debug.PocketEvent) error {
 	switch event.Topic {
 	case debug.PocketTopic_CONSENSUS_MESSAGE_TOPIC:
@@ -156,6 +181,10 @@ func (node *Node) handleDebugEvent(anyMessage *anypb.Any) error {
 		return node.GetBus().GetConsensusModule().HandleDebugMessage(&debugMessage)
 	case debug.DebugMessageAction_DEBUG_SHOW_LATEST_BLOCK_IN_STORE:
 		return node.GetBus().GetPersistenceModule().HandleDebugMessage(&debugMessage)
+	case debug.DebugMessageAction_DEBUG_P2P_USE_RAIN_TREE:
+		fallthrough
+	case debug.DebugMessageAction_DEBUG_P2P_USE_STD_NETWORK:
+		return node.GetBus().GetP2PModule().HandleDebugMessage(&debugMessage)
 	default:
 		log.Printf("Debug message: %s \n", debugMessage.Message)
 	}
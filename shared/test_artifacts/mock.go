@@ -153,9 +153,21 @@ var _ modules.PacemakerConfig = &MockPacemakerConfig{}
 var _ modules.PersistenceConfig = &MockPersistenceConfig{}
 
 type MockPersistenceConfig struct {
-	PostgresUrl    string `json:"postgres_url"`
-	NodeSchema     string `json:"node_schema"`
-	BlockStorePath string `json:"block_store_path"`
+	PostgresUrl          string `json:"postgres_url"`
+	NodeSchema           string `json:"node_schema"`
+	BlockStorePath       string `json:"block_store_path"`
+	TreeStorePath        string `json:"tree_store_path"`
+	MaxConnsCount        int32  `json:"max_conns_count"`
+	MinConnsCount        int32  `json:"min_conns_count"`
+	StatementTimeoutMsec uint64 `json:"statement_timeout_msec"`
+	ContextTimeoutMsec   uint64 `json:"context_timeout_msec"`
+	DatabaseBackend      string `json:"database_backend"`
+	SqlitePath           string `json:"sqlite_path"`
+	TxIndexerPath        string `json:"tx_indexer_path"`
+	PruningStrategy      string `json:"pruning_strategy"`
+	PruningKeepRecent    uint64 `json:"pruning_keep_recent"`
+	PruningKeepEvery     uint64 `json:"pruning_keep_every"`
+	PruningIntervalMsec  uint64 `json:"pruning_interval_msec"`
 }
 
 func (m *MockPersistenceConfig) GetPostgresUrl() string {
@@ -170,6 +182,54 @@ func (m *MockPersistenceConfig) GetBlockStorePath() string {
 	return m.BlockStorePath
 }
 
+func (m *MockPersistenceConfig) GetTreeStorePath() string {
+	return m.TreeStorePath
+}
+
+func (m *MockPersistenceConfig) GetMaxConnsCount() int32 {
+	return m.MaxConnsCount
+}
+
+func (m *MockPersistenceConfig) GetMinConnsCount() int32 {
+	return m.MinConnsCount
+}
+
+func (m *MockPersistenceConfig) GetStatementTimeoutMsec() uint64 {
+	return m.StatementTimeoutMsec
+}
+
+func (m *MockPersistenceConfig) GetContextTimeoutMsec() uint64 {
+	return m.ContextTimeoutMsec
+}
+
+func (m *MockPersistenceConfig) GetDatabaseBackend() string {
+	return m.DatabaseBackend
+}
+
+func (m *MockPersistenceConfig) GetSqlitePath() string {
+	return m.SqlitePath
+}
+
+func (m *MockPersistenceConfig) GetTxIndexerPath() string {
+	return m.TxIndexerPath
+}
+
+func (m *MockPersistenceConfig) GetPruningStrategy() string {
+	return m.PruningStrategy
+}
+
+func (m *MockPersistenceConfig) GetPruningKeepRecent() uint64 {
+	return m.PruningKeepRecent
+}
+
+func (m *MockPersistenceConfig) GetPruningKeepEvery() uint64 {
+	return m.PruningKeepEvery
+}
+
+func (m *MockPersistenceConfig) GetPruningIntervalMsec() uint64 {
+	return m.PruningIntervalMsec
+}
+
 type MockConsensusConfig struct {
 	MaxMempoolBytes uint64               `json:"max_mempool_bytes"`
 	PacemakerConfig *MockPacemakerConfig `json:"pacemaker_config"`
@@ -209,10 +269,13 @@ func (m *MockPacemakerConfig) GetDebugTimeBetweenStepsMsec() uint64 {
 var _ modules.P2PConfig = &MockP2PConfig{}
 
 type MockP2PConfig struct {
-	ConsensusPort         uint32 `json:"consensus_port"`
-	UseRainTree           bool   `json:"use_rain_tree"`
-	IsEmptyConnectionType bool   `json:"is_empty_connection_type"`
-	PrivateKey            string `json:"private_key"`
+	ConsensusPort         uint32   `json:"consensus_port"`
+	UseRainTree           bool     `json:"use_rain_tree"`
+	IsEmptyConnectionType bool     `json:"is_empty_connection_type"`
+	PrivateKey            string   `json:"private_key"`
+	MaxMessageSizeBytes   uint64   `json:"max_message_size_bytes"`
+	ListenAddresses       []string `json:"listen_addresses"`
+	AdvertisedAddresses   []string `json:"advertised_addresses"`
 }
 
 func (m *MockP2PConfig) GetConsensusPort() uint32 {
@@ -227,6 +290,18 @@ func (m *MockP2PConfig) IsEmptyConnType() bool {
 	return m.IsEmptyConnectionType
 }
 
+func (m *MockP2PConfig) GetMaxMessageSizeBytes() uint64 {
+	return m.MaxMessageSizeBytes
+}
+
+func (m *MockP2PConfig) GetListenAddresses() []string {
+	return m.ListenAddresses
+}
+
+func (m *MockP2PConfig) GetAdvertisedAddresses() []string {
+	return m.AdvertisedAddresses
+}
+
 var _ modules.TelemetryConfig = &MockTelemetryConfig{}
 
 type MockTelemetryConfig struct {
@@ -5,13 +5,22 @@ const (
 	P2P_NODE_STARTED_TIMESERIES_METRIC_NAME        = "p2p_nodes_started_counter"
 	P2P_NODE_STARTED_TIMESERIES_METRIC_DESCRIPTION = "the counter to track the number of nodes online"
 
+	// The compression ratio is the compressed bytes divided by the uncompressed bytes
+	P2P_UNCOMPRESSED_BYTES_SENT_TIMESERIES_METRIC_NAME        = "p2p_uncompressed_bytes_sent_gauge"
+	P2P_UNCOMPRESSED_BYTES_SENT_TIMESERIES_METRIC_DESCRIPTION = "the size of the payloads sent to peers before compression"
+	P2P_COMPRESSED_BYTES_SENT_TIMESERIES_METRIC_NAME          = "p2p_compressed_bytes_sent_gauge"
+	P2P_COMPRESSED_BYTES_SENT_TIMESERIES_METRIC_DESCRIPTION   = "the size of the payloads sent to peers after compression"
+
 	// Event Metrics
 	P2P_EVENT_METRICS_NAMESPACE = "event_metrics_namespace_p2p"
 
 	P2P_BROADCAST_MESSAGE_REDUNDANCY_PER_BLOCK_EVENT_METRIC_NAME = "broadcast_message_redundancy_per_block_event_metric"
 	P2P_RAINTREE_MESSAGE_EVENT_METRIC_NAME                       = "raintree_message_event_metric"
+	P2P_INBOUND_MESSAGE_DROPPED_EVENT_METRIC_NAME                = "inbound_message_dropped_event_metric"
 
 	// Attributes
 	P2P_RAINTREE_MESSAGE_EVENT_METRIC_HEIGHT_LABEL = "height"
 	P2P_RAINTREE_MESSAGE_EVENT_METRIC_NONCE_LABEL  = "nonce"
+
+	P2P_INBOUND_MESSAGE_DROPPED_EVENT_METRIC_REASON_LABEL = "reason"
 )
@@ -0,0 +1,17 @@
+package telemetry
+
+const (
+	// Time Series Metrics
+	PERSISTENCE_POOL_ACQUIRED_CONNS_TIMESERIES_METRIC_NAME        = "persistence_pool_acquired_conns_gauge"
+	PERSISTENCE_POOL_ACQUIRED_CONNS_TIMESERIES_METRIC_DESCRIPTION = "the number of database connections currently used by persistence contexts"
+	PERSISTENCE_POOL_IDLE_CONNS_TIMESERIES_METRIC_NAME            = "persistence_pool_idle_conns_gauge"
+	PERSISTENCE_POOL_IDLE_CONNS_TIMESERIES_METRIC_DESCRIPTION     = "the number of idle database connections in the pool"
+	PERSISTENCE_POOL_TOTAL_CONNS_TIMESERIES_METRIC_NAME           = "persistence_pool_total_conns_gauge"
+	PERSISTENCE_POOL_TOTAL_CONNS_TIMESERIES_METRIC_DESCRIPTION    = "the number of open database connections in the pool"
+
+	// An acquisition waits when every connection is in use, so these show whether the pool is large enough
+	PERSISTENCE_POOL_EMPTY_ACQUIRES_TIMESERIES_METRIC_NAME          = "persistence_pool_empty_acquires_gauge"
+	PERSISTENCE_POOL_EMPTY_ACQUIRES_TIMESERIES_METRIC_DESCRIPTION   = "the number of connection acquisitions that had to wait for a connection since startup"
+	PERSISTENCE_POOL_ACQUIRE_DURATION_TIMESERIES_METRIC_NAME        = "persistence_pool_acquire_duration_msec_gauge"
+	PERSISTENCE_POOL_ACQUIRE_DURATION_TIMESERIES_METRIC_DESCRIPTION = "the total time spent acquiring database connections since startup"
+)
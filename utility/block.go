@@ -1,6 +1,8 @@
 package utility
 
 import (
+	"log"
+
 	"github.com/pokt-network/pocket/shared/modules"
 	typesUtil "github.com/pokt-network/pocket/utility/types"
 	"math/big"
@@ -14,7 +16,7 @@ import (
 	Pocket Network adopt a Tendermint-like lifecycle of BeginBlock -> DeliverTx -> EndBlock in that
 	order. Like the name suggests, BeginBlock is an autonomous state operation that executes at the
 	beginning of every block DeliverTx individually applies each transaction against the state and
-	rolls it back (not yet implemented) if fails. Like BeginBlock, EndBlock is an autonomous state
+	rolls it back (using a save point) if it fails. Like BeginBlock, EndBlock is an autonomous state
 	operation that executes at the end of every block.
 */
 
@@ -25,7 +27,7 @@ func (u *UtilityContext) ApplyBlock(latestHeight int64, proposerAddress []byte,
 		return nil, err
 	}
 	// deliver txs lifecycle phase
-	for _, transactionProtoBytes := range transactions {
+	for index, transactionProtoBytes := range transactions {
 		tx, err := typesUtil.TransactionFromBytes(transactionProtoBytes)
 		if err != nil {
 			return nil, err
@@ -33,11 +35,30 @@ func (u *UtilityContext) ApplyBlock(latestHeight int64, proposerAddress []byte,
 		if err := tx.ValidateBasic(); err != nil {
 			return nil, err
 		}
+		txHash := typesUtil.TransactionHash(transactionProtoBytes)
+		// A replayed transaction is neither applied nor indexed, so the result of the original one is kept
+		txExists, er := u.GetPersistenceContext().TransactionExists(txHash)
+		if er != nil {
+			return nil, er
+		}
+		if txExists {
+			log.Printf("[WARN] Transaction %s was already committed and was skipped\n", txHash)
+			continue
+		}
 		// Validate and apply the transaction to the Postgres database
-		if err := u.ApplyTransaction(tx); err != nil {
+		txErr, err := u.ApplyTransactionInSavePoint(transactionProtoBytes, tx)
+		if err != nil {
+			return nil, err
+		}
+		if txErr != nil {
+			// Failed transactions are reverted but their result is still indexed, along with the error
+			log.Printf("[WARN] Transaction %s failed and was reverted: %v\n", txHash, txErr)
+		}
+		txResult, err := typesUtil.TxResult(transactionProtoBytes, tx, u.LatestHeight, index, txErr)
+		if err != nil {
 			return nil, err
 		}
-		if err := u.GetPersistenceContext().StoreTransaction(transactionProtoBytes); err != nil {
+		if err := u.GetPersistenceContext().StoreTransaction(txResult); err != nil {
 			return nil, err
 		}
 
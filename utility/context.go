@@ -74,27 +74,47 @@ func (u *UtilityContext) Codec() codec.Codec {
 }
 
 func (u *UtilityContext) RevertLastSavePoint() typesUtil.Error {
+	key, err := u.popLastSavePoint()
+	if err != nil {
+		return err
+	}
+	if err := u.Context.PersistenceRWContext.RollbackToSavePoint(key); err != nil {
+		return typesUtil.ErrRollbackSavePoint(err)
+	}
+	return nil
+}
+
+// ReleaseLastSavePoint keeps the writes made since the last save point was created.
+func (u *UtilityContext) ReleaseLastSavePoint() typesUtil.Error {
+	key, err := u.popLastSavePoint()
+	if err != nil {
+		return err
+	}
+	if err := u.Context.PersistenceRWContext.ReleaseSavePoint(key); err != nil {
+		return typesUtil.ErrReleaseSavePoint(err)
+	}
+	return nil
+}
+
+func (u *UtilityContext) popLastSavePoint() ([]byte, typesUtil.Error) {
 	if len(u.Context.SavePointsM) == typesUtil.ZeroInt {
-		return typesUtil.ErrEmptySavePoints()
+		return nil, typesUtil.ErrEmptySavePoints()
 	}
 	var key []byte
 	popIndex := len(u.Context.SavePoints) - 1
 	key, u.Context.SavePoints = u.Context.SavePoints[popIndex], u.Context.SavePoints[:popIndex]
 	delete(u.Context.SavePointsM, hex.EncodeToString(key))
-	if err := u.Context.PersistenceRWContext.RollbackToSavePoint(key); err != nil {
-		return typesUtil.ErrRollbackSavePoint(err)
-	}
-	return nil
+	return key, nil
 }
 
 func (u *UtilityContext) NewSavePoint(transactionHash []byte) typesUtil.Error {
-	if err := u.Context.PersistenceRWContext.NewSavePoint(transactionHash); err != nil {
-		return typesUtil.ErrNewSavePoint(err)
-	}
 	txHash := hex.EncodeToString(transactionHash)
 	if _, exists := u.Context.SavePointsM[txHash]; exists {
 		return typesUtil.ErrDuplicateSavePoint()
 	}
+	if err := u.Context.PersistenceRWContext.NewSavePoint(transactionHash); err != nil {
+		return typesUtil.ErrNewSavePoint(err)
+	}
 	u.Context.SavePoints = append(u.Context.SavePoints, transactionHash)
 	u.Context.SavePointsM[txHash] = struct{}{}
 	return nil
@@ -2,9 +2,11 @@ package utility
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"log"
 
+	"github.com/pokt-network/pocket/persistence/kvstore"
 	"github.com/pokt-network/pocket/utility/types"
 
 	"github.com/pokt-network/pocket/shared/modules"
@@ -16,6 +18,8 @@ var _ modules.UtilityConfig = &types.UtilityConfig{}
 type UtilityModule struct {
 	bus     modules.Bus
 	Mempool types.Mempool
+
+	txGossip *txGossip
 }
 
 const (
@@ -29,9 +33,9 @@ func Create(configPath, genesisPath string) (modules.UtilityModule, error) {
 		return nil, err
 	}
 	config := (c).(*types.UtilityConfig)
-	return &UtilityModule{
-		Mempool: types.NewMempool(config.MaxMempoolTransactionBytes, config.MaxMempoolTransactions),
-	}, nil
+	u.Mempool = types.NewMempool(config.MaxMempoolTransactionBytes, config.MaxMempoolTransactions)
+	u.txGossip = newTxGossip(u.Mempool, u.HandleTransaction)
+	return u, nil
 }
 
 func (u *UtilityModule) InitConfig(pathToConfigJSON string) (config modules.IConfig, err error) {
@@ -54,11 +58,38 @@ func (u *UtilityModule) InitGenesis(pathToGenesisJSON string) (genesis modules.I
 	return // No-op
 }
 
-func (u *UtilityModule) Start() error {
+func (u *UtilityModule) GetMempoolTransaction(txHash string) ([]byte, bool) {
+	return u.Mempool.GetTransaction(txHash)
+}
+
+// HandleTransaction is used for both the transactions submitted to the node and the ones fetched from peers by the
+// transaction gossip. The transaction is checked like `CheckTransaction` does but with a read context, which does
+// not contend with consensus applying a block.
+func (u *UtilityModule) HandleTransaction(tx []byte) error {
+	persistenceModule := u.GetBus().GetPersistenceModule()
+	// The height of consensus can only be read from its own goroutine, so the last committed block is used instead
+	height, _, _, err := persistenceModule.GetLatestBlock()
+	if err != nil && !errors.Is(err, kvstore.ErrKeyNotFound) {
+		return types.ErrNewPersistenceContext(err)
+	}
+	readContext, err := persistenceModule.NewReadContext(int64(height))
+	if err != nil {
+		return types.ErrNewPersistenceContext(err)
+	}
+	defer readContext.Close()
+	if err := checkTransaction(u.Mempool, readContext, tx); err != nil {
+		return err
+	}
+	u.txGossip.announce(types.TransactionHash(tx))
 	return nil
 }
 
+func (u *UtilityModule) Start() error {
+	return u.txGossip.start(u.GetBus())
+}
+
 func (u *UtilityModule) Stop() error {
+	u.txGossip.stopLoop()
 	return nil
 }
 
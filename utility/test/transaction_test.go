@@ -48,6 +48,44 @@ func TestUtilityContext_ApplyTransaction(t *testing.T) {
 	test_artifacts.CleanupTest(ctx)
 }
 
+func TestUtilityContext_ApplyTransactionInSavePoint(t *testing.T) {
+	ctx := NewTestingUtilityContext(t, 0)
+
+	// The signer can pay the fee but not the amount sent, so the transaction fails after the fee is deducted
+	tx, _, _, signer := newTestingTransaction(t, ctx)
+	feeBig, err := ctx.GetMessageSendFee()
+	require.NoError(t, err)
+	startingBalance := big.NewInt(0).Add(feeBig, big.NewInt(1))
+	require.NoError(t, ctx.SetAccountAmount(signer.Address(), startingBalance))
+	txBz, err := tx.Bytes()
+	require.NoError(t, err)
+
+	txErr, err := ctx.ApplyTransactionInSavePoint(txBz, tx)
+	require.NoError(t, err)
+	require.Error(t, txErr)
+
+	// Only the writes of the failed transaction are reverted
+	amount, err := ctx.GetAccountAmount(signer.Address())
+	require.NoError(t, err)
+	require.Equal(t, startingBalance, amount, "the fee should have been reverted")
+	require.Empty(t, ctx.Context.SavePoints)
+
+	// The context is still usable after a failed transaction
+	tx, startingBalance, sentAmount, signer := newTestingTransaction(t, ctx)
+	txBz, err = tx.Bytes()
+	require.NoError(t, err)
+	txErr, err = ctx.ApplyTransactionInSavePoint(txBz, tx)
+	require.NoError(t, err)
+	require.NoError(t, txErr)
+
+	expectedAfterBalance := big.NewInt(0).Sub(startingBalance, big.NewInt(0).Add(sentAmount, feeBig))
+	amount, err = ctx.GetAccountAmount(signer.Address())
+	require.NoError(t, err)
+	require.Equal(t, expectedAfterBalance, amount, "unexpected after balance")
+
+	test_artifacts.CleanupTest(ctx)
+}
+
 // TODO: (#168) Fix this test once txIndexer is implemented by postgres context
 func TestUtilityContext_CheckTransaction(t *testing.T) {
 	// ctx := NewTestingUtilityContext(t, 0)
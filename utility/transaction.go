@@ -3,6 +3,9 @@ package utility
 import (
 	"bytes"
 	"encoding/hex"
+	"log"
+
+	"github.com/pokt-network/pocket/shared/codec"
 	"github.com/pokt-network/pocket/shared/crypto"
 	typesUtil "github.com/pokt-network/pocket/utility/types"
 )
@@ -15,13 +18,38 @@ func (u *UtilityContext) ApplyTransaction(tx *typesUtil.Transaction) typesUtil.E
 	return u.HandleMessage(msg)
 }
 
+// ApplyTransactionInSavePoint applies the transaction in its own save point so, if it fails, only its own writes are
+// reverted rather than the ones of the whole block. The error of the transaction is returned as `txErr` while errors
+// managing the save point, which leave the context in an unknown state, are returned as `err`.
+func (u *UtilityContext) ApplyTransactionInSavePoint(transactionProtoBytes []byte, tx *typesUtil.Transaction) (txErr, err typesUtil.Error) {
+	if err := u.NewSavePoint(crypto.SHA3Hash(transactionProtoBytes)); err != nil {
+		return nil, err
+	}
+	if txErr := u.ApplyTransaction(tx); txErr != nil {
+		if err := u.RevertLastSavePoint(); err != nil {
+			return nil, err
+		}
+		return txErr, nil
+	}
+	return nil, u.ReleaseLastSavePoint()
+}
+
 func (u *UtilityContext) CheckTransaction(transactionProtoBytes []byte) error {
+	return checkTransaction(u.Mempool, u.Store(), transactionProtoBytes)
+}
+
+// txIndex is the part of a persistence context needed to know whether a transaction was already committed, so
+// transactions can also be checked with a read context (e.g. while consensus is applying a block).
+type txIndex interface {
+	TransactionExists(transactionHash string) (bool, error)
+}
+
+func checkTransaction(mempool typesUtil.Mempool, store txIndex, transactionProtoBytes []byte) error {
 	// validate transaction
 	txHash := typesUtil.TransactionHash(transactionProtoBytes)
-	if u.Mempool.Contains(txHash) {
+	if mempool.Contains(txHash) {
 		return typesUtil.ErrDuplicateTransaction()
 	}
-	store := u.Store()
 	txExists, err := store.TransactionExists(txHash)
 	if err != nil {
 		return err
@@ -30,7 +58,7 @@ func (u *UtilityContext) CheckTransaction(transactionProtoBytes []byte) error {
 	if txExists {
 		return typesUtil.ErrTransactionAlreadyCommitted()
 	}
-	cdc := u.Codec()
+	cdc := codec.GetCodec()
 	transaction := &typesUtil.Transaction{}
 	if err := cdc.Unmarshal(transactionProtoBytes, transaction); err != nil {
 		return typesUtil.ErrProtoUnmarshal(err)
@@ -39,7 +67,7 @@ func (u *UtilityContext) CheckTransaction(transactionProtoBytes []byte) error {
 		return err
 	}
 	// store in mempool
-	return u.Mempool.AddTransaction(transactionProtoBytes)
+	return mempool.AddTransaction(transactionProtoBytes)
 }
 
 func (u *UtilityContext) GetProposalTransactions(proposer []byte, maxTransactionBytes int, lastBlockByzantineValidators [][]byte) ([][]byte, error) {
@@ -67,13 +95,15 @@ func (u *UtilityContext) GetProposalTransactions(proposer []byte, maxTransaction
 			}
 			break // we've reached our max
 		}
-		err = u.ApplyTransaction(transaction)
+		txErr, err := u.ApplyTransactionInSavePoint(txBytes, transaction)
 		if err != nil {
-			// TODO: Properly implement 'unhappy path' for save points
-			if err := u.RevertLastSavePoint(); err != nil {
-				return nil, err
-			}
+			return nil, err
+		}
+		if txErr != nil {
+			// The failed transaction is left out of the block; only its own writes were reverted
+			log.Printf("[WARN] Leaving transaction %s out of the proposal: %v\n", typesUtil.TransactionHash(txBytes), txErr)
 			totalSizeInBytes -= txSizeInBytes
+			continue
 		}
 		transactions = append(transactions, txBytes)
 	}
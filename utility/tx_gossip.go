@@ -0,0 +1,371 @@
+package utility
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pokt-network/pocket/shared/crypto"
+	"github.com/pokt-network/pocket/shared/debug"
+	"github.com/pokt-network/pocket/shared/modules"
+	typesUtil "github.com/pokt-network/pocket/utility/types"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// Announcements are batched to amortize their overhead when many transactions are submitted at once
+	txAnnouncementInterval     = 100 * time.Millisecond
+	maxTxHashesPerAnnouncement = 500
+
+	// IMPROVE: Use set reconciliation (e.g. IBLTs) instead of sending every hash in the mempool once
+	// mempools get large enough for this to matter.
+	mempoolReconciliationInterval = 30 * time.Second
+	maxTxHashesPerReconciliation  = maxTxsPerResponse
+
+	maxTxsPerResponse       = 500
+	maxTxsBytesPerResponse  = 4 * 1024 * 1024 // 4MiB
+	maxConcurrentTxFetches  = 8
+	maxRecentlySeenTxHashes = 10000
+)
+
+// txGossip propagates the transactions added to the mempool without broadcasting them in full:
+//  1. The hashes of new transactions are announced to a few random peers (see `PropagationGossip`)
+//  2. Peers fetch the announced transactions they are missing from the announcer and announce them in turn
+//  3. Mempools are periodically reconciled with a random peer to recover from missed announcements
+type txGossip struct {
+	bus      modules.Bus
+	mempool  typesUtil.Mempool
+	handleTx func(tx []byte) error // Validates the transaction, adds it to the mempool and announces it
+
+	m                    sync.Mutex
+	pendingAnnouncements []string
+	fetching             map[string]struct{}
+	recentlySeen         map[string]struct{} // Transactions already handled (e.g. invalid or committed) that should not be fetched again
+	recentlySeenOrder    []string
+
+	fetchSlots chan struct{}
+	stop       chan struct{}
+}
+
+func newTxGossip(mempool typesUtil.Mempool, handleTx func(tx []byte) error) *txGossip {
+	return &txGossip{
+		mempool:      mempool,
+		handleTx:     handleTx,
+		fetching:     make(map[string]struct{}),
+		recentlySeen: make(map[string]struct{}),
+		fetchSlots:   make(chan struct{}, maxConcurrentTxFetches),
+		stop:         make(chan struct{}),
+	}
+}
+
+func (g *txGossip) start(bus modules.Bus) error {
+	g.bus = bus
+	p2pMod := bus.GetP2PModule()
+	p2pMod.SetPropagationStrategy(debug.PocketTopic_TX_GOSSIP_TOPIC, modules.PropagationGossip)
+	if err := p2pMod.Subscribe(debug.PocketTopic_TX_GOSSIP_TOPIC, g.handleAnnouncement); err != nil {
+		return err
+	}
+	if err := p2pMod.RegisterRequestHandler(&typesUtil.MempoolTxsRequest{}, g.handleMempoolTxsRequest); err != nil {
+		return err
+	}
+	if err := p2pMod.RegisterRequestHandler(&typesUtil.MempoolReconciliationRequest{}, g.handleReconciliationRequest); err != nil {
+		return err
+	}
+	go g.run()
+	return nil
+}
+
+func (g *txGossip) run() {
+	announceTicker := time.NewTicker(txAnnouncementInterval)
+	defer announceTicker.Stop()
+	reconcileTicker := time.NewTicker(mempoolReconciliationInterval)
+	defer reconcileTicker.Stop()
+	for {
+		select {
+		case <-announceTicker.C:
+			g.flushAnnouncements()
+		case <-reconcileTicker.C:
+			g.reconcile()
+		case <-g.stop:
+			return
+		}
+	}
+}
+
+// announce queues the hash of a transaction added to the mempool to be announced to peers.
+func (g *txGossip) announce(txHash string) {
+	g.m.Lock()
+	g.pendingAnnouncements = append(g.pendingAnnouncements, txHash)
+	isBatchFull := len(g.pendingAnnouncements) >= maxTxHashesPerAnnouncement
+	g.m.Unlock()
+
+	if isBatchFull {
+		g.flushAnnouncements()
+	}
+}
+
+func (g *txGossip) flushAnnouncements() {
+	g.m.Lock()
+	txHashes := g.pendingAnnouncements
+	g.pendingAnnouncements = nil
+	g.m.Unlock()
+
+	for len(txHashes) > 0 {
+		batchSize := len(txHashes)
+		if batchSize > maxTxHashesPerAnnouncement {
+			batchSize = maxTxHashesPerAnnouncement
+		}
+		g.publishAnnouncement(txHashes[:batchSize])
+		txHashes = txHashes[batchSize:]
+	}
+}
+
+func (g *txGossip) publishAnnouncement(txHashes []string) {
+	p2pMod := g.bus.GetP2PModule()
+	announcer, err := p2pMod.GetAddress()
+	if err != nil {
+		log.Println("[WARN] Error getting the address to announce transactions from: ", err)
+		return
+	}
+	anyAnnouncement, err := anypb.New(&typesUtil.TxAnnouncement{
+		Announcer: announcer,
+		TxHashes:  txHashes,
+	})
+	if err != nil {
+		log.Println("[WARN] Error creating the transaction announcement: ", err)
+		return
+	}
+	if err := p2pMod.Publish(anyAnnouncement, debug.PocketTopic_TX_GOSSIP_TOPIC); err != nil {
+		log.Println("[WARN] Error publishing the transaction announcement: ", err)
+	}
+}
+
+func (g *txGossip) handleAnnouncement(anyAnnouncement *anypb.Any) error {
+	announcement := &typesUtil.TxAnnouncement{}
+	if err := anypb.UnmarshalTo(anyAnnouncement, announcement, proto.UnmarshalOptions{}); err != nil {
+		return err
+	}
+
+	missingTxHashes := g.startFetching(announcement.TxHashes)
+	if len(missingTxHashes) == 0 {
+		return nil
+	}
+
+	// Announcements are handled by the P2P module which must not be blocked on the network, so the
+	// transactions are fetched asynchronously. If there are too many fetches in flight, the missing
+	// transactions are left for the next mempool reconciliation.
+	select {
+	case g.fetchSlots <- struct{}{}:
+	default:
+		g.doneFetching(missingTxHashes)
+		return nil
+	}
+	go func() {
+		defer func() { <-g.fetchSlots }()
+		defer g.doneFetching(missingTxHashes)
+		g.fetchTxs(announcement.Announcer, missingTxHashes)
+	}()
+	return nil
+}
+
+// startFetching returns the hashes of the transactions that are neither in the mempool, being fetched,
+// nor recently seen, and marks them as being fetched.
+func (g *txGossip) startFetching(txHashes []string) []string {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	missingTxHashes := make([]string, 0)
+	for _, txHash := range txHashes {
+		if _, ok := g.fetching[txHash]; ok {
+			continue
+		}
+		if _, ok := g.recentlySeen[txHash]; ok {
+			continue
+		}
+		if g.mempool.Contains(txHash) {
+			continue
+		}
+		g.fetching[txHash] = struct{}{}
+		missingTxHashes = append(missingTxHashes, txHash)
+	}
+	return missingTxHashes
+}
+
+func (g *txGossip) doneFetching(txHashes []string) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	for _, txHash := range txHashes {
+		delete(g.fetching, txHash)
+	}
+}
+
+// fetchTxs fetches the transactions from the announcer, falling back to any peer. The announcer of an announcement
+// is not signed, so it is only a hint of which peer has the transactions and is not trusted otherwise.
+func (g *txGossip) fetchTxs(announcer crypto.Address, txHashes []string) {
+	anyReq, err := anypb.New(&typesUtil.MempoolTxsRequest{TxHashes: txHashes})
+	if err != nil {
+		log.Println("[WARN] Error creating the mempool transactions request: ", err)
+		return
+	}
+	p2pMod := g.bus.GetP2PModule()
+	anyResp, err := p2pMod.Request(announcer, anyReq)
+	if err != nil && announcer != nil {
+		log.Printf("[WARN] Error fetching %d transactions from announcer %s, fetching them from any peer: %v\n", len(txHashes), announcer, err)
+		anyResp, err = p2pMod.Request(nil, anyReq)
+	}
+	if err != nil {
+		log.Printf("[WARN] Error fetching %d transactions: %v\n", len(txHashes), err)
+		return
+	}
+	resp := &typesUtil.MempoolTxsResponse{}
+	if err := anypb.UnmarshalTo(anyResp, resp, proto.UnmarshalOptions{}); err != nil {
+		log.Println("[WARN] Error decoding the mempool transactions response: ", err)
+		return
+	}
+	g.addTxs(resp.Txs)
+}
+
+// addTxs handles the transactions received from peers, which announces the ones added to the mempool.
+func (g *txGossip) addTxs(txs [][]byte) {
+	for _, tx := range txs {
+		txHash := typesUtil.TransactionHash(tx)
+		if g.mempool.Contains(txHash) {
+			continue
+		}
+		g.markRecentlySeen(txHash)
+		if err := g.handleTx(tx); err != nil {
+			log.Printf("[DEBUG] Discarding gossiped transaction %s: %v\n", txHash, err)
+		}
+	}
+}
+
+func (g *txGossip) markRecentlySeen(txHash string) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	if _, ok := g.recentlySeen[txHash]; ok {
+		return
+	}
+	g.recentlySeen[txHash] = struct{}{}
+	g.recentlySeenOrder = append(g.recentlySeenOrder, txHash)
+	if len(g.recentlySeenOrder) > maxRecentlySeenTxHashes {
+		delete(g.recentlySeen, g.recentlySeenOrder[0])
+		g.recentlySeenOrder = g.recentlySeenOrder[1:]
+	}
+}
+
+func (g *txGossip) handleMempoolTxsRequest(anyReq *anypb.Any) (*anypb.Any, error) {
+	req := &typesUtil.MempoolTxsRequest{}
+	if err := anypb.UnmarshalTo(anyReq, req, proto.UnmarshalOptions{}); err != nil {
+		return nil, err
+	}
+	txs := make([][]byte, 0)
+	txsBytes := 0
+	for _, txHash := range req.TxHashes {
+		tx, found := g.mempool.GetTransaction(txHash)
+		if !found {
+			continue
+		}
+		if len(txs) >= maxTxsPerResponse || txsBytes+len(tx) > maxTxsBytesPerResponse {
+			break
+		}
+		txs = append(txs, tx)
+		txsBytes += len(tx)
+	}
+	return anypb.New(&typesUtil.MempoolTxsResponse{Txs: txs})
+}
+
+// reconcile fetches the transactions in the mempool of a random peer that are missing from the local one. The
+// hashes of the local mempool are sorted and sent in pages, each reconciling the range of hashes it covers.
+func (g *txGossip) reconcile() {
+	txHashes := g.mempool.TxHashes()
+	sort.Strings(txHashes)
+
+	afterTxHash := ""
+	for {
+		page := txHashes
+		if len(page) > maxTxHashesPerReconciliation {
+			page = page[:maxTxHashesPerReconciliation]
+		}
+		txHashes = txHashes[len(page):]
+
+		// The last page also covers the hashes above the highest one in the local mempool
+		req := &typesUtil.MempoolReconciliationRequest{TxHashes: page, AfterTxHash: afterTxHash}
+		if len(txHashes) > 0 {
+			req.LastTxHash = page[len(page)-1]
+		}
+		if !g.reconcileRange(req) {
+			return
+		}
+		if len(txHashes) == 0 {
+			return
+		}
+		afterTxHash = req.LastTxHash
+	}
+}
+
+// reconcileRange reconciles the range of hashes covered by `req`, returning false if the reconciliation should stop.
+func (g *txGossip) reconcileRange(req *typesUtil.MempoolReconciliationRequest) bool {
+	anyReq, err := anypb.New(req)
+	if err != nil {
+		log.Println("[WARN] Error creating the mempool reconciliation request: ", err)
+		return false
+	}
+	anyResp, err := g.bus.GetP2PModule().Request(nil, anyReq)
+	if err != nil {
+		log.Println("[WARN] Error reconciling the mempool: ", err)
+		return false
+	}
+	resp := &typesUtil.MempoolReconciliationResponse{}
+	if err := anypb.UnmarshalTo(anyResp, resp, proto.UnmarshalOptions{}); err != nil {
+		log.Println("[WARN] Error decoding the mempool reconciliation response: ", err)
+		return false
+	}
+	g.addTxs(resp.Txs)
+	return true
+}
+
+func (g *txGossip) handleReconciliationRequest(anyReq *anypb.Any) (*anypb.Any, error) {
+	req := &typesUtil.MempoolReconciliationRequest{}
+	if err := anypb.UnmarshalTo(anyReq, req, proto.UnmarshalOptions{}); err != nil {
+		return nil, err
+	}
+	requesterTxHashes := make(map[string]struct{}, len(req.TxHashes))
+	for _, txHash := range req.TxHashes {
+		requesterTxHashes[txHash] = struct{}{}
+	}
+
+	txs := make([][]byte, 0)
+	txsBytes := 0
+	for _, txHash := range g.mempool.TxHashes() {
+		if !isInReconciliationRange(req, txHash) {
+			continue
+		}
+		if _, ok := requesterTxHashes[txHash]; ok {
+			continue
+		}
+		tx, found := g.mempool.GetTransaction(txHash)
+		if !found {
+			continue // The transaction was removed from the mempool in the meantime
+		}
+		if len(txs) >= maxTxsPerResponse || txsBytes+len(tx) > maxTxsBytesPerResponse {
+			break
+		}
+		txs = append(txs, tx)
+		txsBytes += len(tx)
+	}
+	return anypb.New(&typesUtil.MempoolReconciliationResponse{Txs: txs})
+}
+
+// isInReconciliationRange returns whether `txHash` is in the range of hashes reconciled by `req`.
+func isInReconciliationRange(req *typesUtil.MempoolReconciliationRequest, txHash string) bool {
+	if req.AfterTxHash != "" && txHash <= req.AfterTxHash {
+		return false
+	}
+	return req.LastTxHash == "" || txHash <= req.LastTxHash
+}
+
+func (g *txGossip) stopLoop() {
+	close(g.stop)
+}
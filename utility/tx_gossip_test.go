@@ -0,0 +1,188 @@
+package utility
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pokt-network/pocket/shared/crypto"
+	"github.com/pokt-network/pocket/shared/debug"
+	modulesMock "github.com/pokt-network/pocket/shared/modules/mocks"
+	typesUtil "github.com/pokt-network/pocket/utility/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestTxGossip_FetchesMissingAnnouncedTxs(t *testing.T) {
+	announcerAddr, announcer := newTestTxGossip(t)
+	_, receiver := newTestTxGossip(t)
+
+	tx, missingTx := []byte("tx"), []byte("missing tx")
+	require.NoError(t, announcer.handleTx(tx))
+
+	p2pMock := receiver.bus.GetP2PModule().(*modulesMock.MockP2PModule)
+	p2pMock.EXPECT().Request(announcerAddr, gomock.Any()).DoAndReturn(func(_ crypto.Address, anyReq *anypb.Any) (*anypb.Any, error) {
+		return announcer.handleMempoolTxsRequest(anyReq)
+	}).Times(1)
+
+	announcement := newTestAnnouncement(t, announcerAddr, tx, missingTx)
+	require.NoError(t, receiver.handleAnnouncement(announcement))
+	require.Eventually(t, func() bool {
+		return receiver.mempool.Contains(typesUtil.TransactionHash(tx))
+	}, time.Second, 10*time.Millisecond)
+	require.False(t, receiver.mempool.Contains(typesUtil.TransactionHash(missingTx)))
+
+	// The transaction is announced onwards by the receiver
+	published := expectAnnouncements(t, p2pMock, 1)
+	receiver.flushAnnouncements()
+	require.Equal(t, []string{typesUtil.TransactionHash(tx)}, (<-published).TxHashes)
+
+	// Transactions already in the mempool are not fetched again
+	require.Eventually(t, func() bool {
+		receiver.m.Lock()
+		defer receiver.m.Unlock()
+		return len(receiver.fetching) == 0
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, receiver.handleAnnouncement(newTestAnnouncement(t, announcerAddr, tx)))
+}
+
+func TestTxGossip_FetchesFromAnyPeerIfAnnouncerFails(t *testing.T) {
+	_, peer := newTestTxGossip(t)
+	_, receiver := newTestTxGossip(t)
+
+	tx := []byte("tx")
+	require.NoError(t, peer.handleTx(tx))
+
+	// The announcer is not signed, so it may not even be a peer
+	forgedAnnouncer, err := crypto.GenerateAddress()
+	require.NoError(t, err)
+	p2pMock := receiver.bus.GetP2PModule().(*modulesMock.MockP2PModule)
+	gomock.InOrder(
+		p2pMock.EXPECT().Request(forgedAnnouncer, gomock.Any()).Return(nil, fmt.Errorf("unknown peer")).Times(1),
+		p2pMock.EXPECT().Request(nil, gomock.Any()).DoAndReturn(func(_ crypto.Address, anyReq *anypb.Any) (*anypb.Any, error) {
+			return peer.handleMempoolTxsRequest(anyReq)
+		}).Times(1),
+	)
+
+	require.NoError(t, receiver.handleAnnouncement(newTestAnnouncement(t, forgedAnnouncer, tx)))
+	require.Eventually(t, func() bool {
+		return receiver.mempool.Contains(typesUtil.TransactionHash(tx))
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTxGossip_MempoolReconciliation(t *testing.T) {
+	_, peer := newTestTxGossip(t)
+	_, node := newTestTxGossip(t)
+
+	sharedTx, missingTx := []byte("shared tx"), []byte("missing tx")
+	require.NoError(t, peer.handleTx(sharedTx))
+	require.NoError(t, peer.handleTx(missingTx))
+	require.NoError(t, node.handleTx(sharedTx))
+
+	p2pMock := node.bus.GetP2PModule().(*modulesMock.MockP2PModule)
+	p2pMock.EXPECT().Request(nil, gomock.Any()).DoAndReturn(func(_ crypto.Address, anyReq *anypb.Any) (*anypb.Any, error) {
+		anyResp, err := peer.handleReconciliationRequest(anyReq)
+		require.NoError(t, err)
+		resp := &typesUtil.MempoolReconciliationResponse{}
+		require.NoError(t, anypb.UnmarshalTo(anyResp, resp, proto.UnmarshalOptions{}))
+		require.Equal(t, [][]byte{missingTx}, resp.Txs, "only the missing transaction should be sent")
+		return anyResp, nil
+	}).Times(1)
+
+	node.reconcile()
+	require.True(t, node.mempool.Contains(typesUtil.TransactionHash(missingTx)))
+	require.Equal(t, 2, node.mempool.Size())
+}
+
+func TestTxGossip_MempoolReconciliationIsPaged(t *testing.T) {
+	_, peer := newTestTxGossip(t)
+	_, node := newTestTxGossip(t)
+
+	for i := 0; i <= maxTxHashesPerReconciliation; i++ {
+		// Added to the mempools directly so they are not announced
+		tx := []byte(fmt.Sprintf("shared tx %d", i))
+		require.NoError(t, peer.mempool.AddTransaction(tx))
+		require.NoError(t, node.mempool.AddTransaction(tx))
+	}
+	missingTx := []byte("missing tx")
+	require.NoError(t, peer.handleTx(missingTx))
+
+	var numMissingTxs int
+	p2pMock := node.bus.GetP2PModule().(*modulesMock.MockP2PModule)
+	p2pMock.EXPECT().Request(nil, gomock.Any()).DoAndReturn(func(_ crypto.Address, anyReq *anypb.Any) (*anypb.Any, error) {
+		req := &typesUtil.MempoolReconciliationRequest{}
+		require.NoError(t, anypb.UnmarshalTo(anyReq, req, proto.UnmarshalOptions{}))
+		require.LessOrEqual(t, len(req.TxHashes), maxTxHashesPerReconciliation)
+
+		anyResp, err := peer.handleReconciliationRequest(anyReq)
+		require.NoError(t, err)
+		resp := &typesUtil.MempoolReconciliationResponse{}
+		require.NoError(t, anypb.UnmarshalTo(anyResp, resp, proto.UnmarshalOptions{}))
+		numMissingTxs += len(resp.Txs)
+		return anyResp, nil
+	}).Times(2)
+
+	node.reconcile()
+	require.Equal(t, 1, numMissingTxs, "only the missing transaction should be sent, in the page covering its hash")
+	require.True(t, node.mempool.Contains(typesUtil.TransactionHash(missingTx)))
+}
+
+func TestTxGossip_AnnouncementsAreBatched(t *testing.T) {
+	_, g := newTestTxGossip(t)
+	p2pMock := g.bus.GetP2PModule().(*modulesMock.MockP2PModule)
+	published := expectAnnouncements(t, p2pMock, 2)
+
+	for i := 0; i <= maxTxHashesPerAnnouncement; i++ {
+		g.announce(fmt.Sprintf("hash %d", i))
+	}
+	require.Len(t, (<-published).TxHashes, maxTxHashesPerAnnouncement, "a full batch is announced right away")
+
+	g.flushAnnouncements()
+	require.Len(t, (<-published).TxHashes, 1)
+}
+
+// newTestTxGossip returns a tx gossip (which is not started) whose mempool accepts any transaction.
+func newTestTxGossip(t *testing.T) (crypto.Address, *txGossip) {
+	addr, err := crypto.GenerateAddress()
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	p2pMock := modulesMock.NewMockP2PModule(ctrl)
+	p2pMock.EXPECT().GetAddress().Return(addr, nil).AnyTimes()
+	busMock := modulesMock.NewMockBus(ctrl)
+	busMock.EXPECT().GetP2PModule().Return(p2pMock).AnyTimes()
+
+	mempool := typesUtil.NewMempool(1024*1024, 1000)
+	var g *txGossip
+	g = newTxGossip(mempool, func(tx []byte) error {
+		if err := mempool.AddTransaction(tx); err != nil {
+			return err
+		}
+		g.announce(typesUtil.TransactionHash(tx))
+		return nil
+	})
+	g.bus = busMock
+	return addr, g
+}
+
+func newTestAnnouncement(t *testing.T, announcer crypto.Address, txs ...[]byte) *anypb.Any {
+	txHashes := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		txHashes = append(txHashes, typesUtil.TransactionHash(tx))
+	}
+	anyAnnouncement, err := anypb.New(&typesUtil.TxAnnouncement{Announcer: announcer, TxHashes: txHashes})
+	require.NoError(t, err)
+	return anyAnnouncement
+}
+
+func expectAnnouncements(t *testing.T, p2pMock *modulesMock.MockP2PModule, times int) <-chan *typesUtil.TxAnnouncement {
+	published := make(chan *typesUtil.TxAnnouncement, times)
+	p2pMock.EXPECT().Publish(gomock.Any(), debug.PocketTopic_TX_GOSSIP_TOPIC).Do(func(anyAnnouncement *anypb.Any, _ debug.PocketTopic) {
+		announcement := &typesUtil.TxAnnouncement{}
+		require.NoError(t, anypb.UnmarshalTo(anyAnnouncement, announcement, proto.UnmarshalOptions{}))
+		published <- announcement
+	}).Times(times)
+	return published
+}
@@ -161,6 +161,7 @@ const (
 	CodeStakeLessError                    Code = 128
 	CodeGetHeightError                    Code = 129
 	CodeUnknownActorType                  Code = 130
+	CodeReleaseSavePointError             Code = 131
 
 	GetStakedTokensError              = "an error occurred getting the validator staked tokens"
 	SetValidatorStakedTokensError     = "an error occurred setting the validator staked tokens"
@@ -235,6 +236,7 @@ const (
 	TransactionAlreadyCommittedError  = "the transaction is already committed"
 	NewSavePointError                 = "an error occurred creating the save point"
 	RollbackSavePointError            = "an error occurred rolling back to save point"
+	ReleaseSavePointError             = "an error occurred releasing the save point"
 	NewPersistenceContextError        = "an error occurred creating the persistence context"
 	GetAppHashError                   = "an error occurred getting the apphash"
 	ResetContextError                 = "an error occurred resetting the context"
@@ -601,6 +603,10 @@ func ErrRollbackSavePoint(err error) Error {
 	return NewError(CodeRollbackSavePointError, fmt.Sprintf("%s: %s", RollbackSavePointError, err.Error()))
 }
 
+func ErrReleaseSavePoint(err error) Error {
+	return NewError(CodeReleaseSavePointError, fmt.Sprintf("%s: %s", ReleaseSavePointError, err.Error()))
+}
+
 func ErrNewPersistenceContext(err error) Error {
 	return NewError(CodeNewPersistenceContextError, fmt.Sprintf("%s: %s", NewPersistenceContextError, err.Error()))
 }
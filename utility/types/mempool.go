@@ -1,7 +1,6 @@
 package types
 
 import (
-	"bytes"
 	"container/list"
 	"sync"
 
@@ -10,6 +9,8 @@ import (
 
 type Mempool interface {
 	Contains(hash string) bool
+	GetTransaction(hash string) (tx []byte, found bool)
+	TxHashes() []string
 	AddTransaction(tx []byte) Error
 	DeleteTransaction(tx []byte) Error
 
@@ -23,7 +24,7 @@ var _ Mempool = &FIFOMempool{}
 
 type FIFOMempool struct {
 	l                    sync.RWMutex
-	hashMap              map[string]struct{}
+	hashMap              map[string]*list.Element
 	pool                 *list.List
 	size                 int
 	transactionBytes     int
@@ -34,7 +35,7 @@ type FIFOMempool struct {
 func NewMempool(maxTransactionBytes uint64, maxTransactions uint32) Mempool {
 	return &FIFOMempool{
 		l:                    sync.RWMutex{},
-		hashMap:              make(map[string]struct{}),
+		hashMap:              make(map[string]*list.Element),
 		pool:                 list.New(),
 		size:                 0,
 		transactionBytes:     0,
@@ -50,8 +51,7 @@ func (f *FIFOMempool) AddTransaction(tx []byte) Error {
 	if _, ok := f.hashMap[hashString]; ok {
 		return ErrDuplicateTransaction()
 	}
-	f.pool.PushBack(tx)
-	f.hashMap[hashString] = struct{}{}
+	f.hashMap[hashString] = f.pool.PushBack(tx)
 	f.size++
 	f.transactionBytes += len(tx)
 	for uint32(f.size) >= f.maxTransactions || uint64(f.transactionBytes) >= f.maxTransactionsBytes {
@@ -72,19 +72,32 @@ func (f *FIFOMempool) Contains(hash string) bool {
 	return false
 }
 
+func (f *FIFOMempool) GetTransaction(hash string) ([]byte, bool) {
+	f.l.RLock()
+	defer f.l.RUnlock()
+	e, ok := f.hashMap[hash]
+	if !ok {
+		return nil, false
+	}
+	return e.Value.([]byte), true
+}
+
+// TxHashes returns the hashes of the transactions in the mempool, from oldest to newest.
+func (f *FIFOMempool) TxHashes() []string {
+	f.l.RLock()
+	defer f.l.RUnlock()
+	hashes := make([]string, 0, f.size)
+	for e := f.pool.Front(); e != nil; e = e.Next() {
+		hashes = append(hashes, crypto.GetHashStringFromBytes(e.Value.([]byte)))
+	}
+	return hashes
+}
+
 func (f *FIFOMempool) DeleteTransaction(tx []byte) Error {
 	f.l.Lock()
 	defer f.l.Unlock()
-	var toRemove *list.Element
-	for e := f.pool.Front(); e.Next() != nil; {
-		if bytes.Equal(tx, e.Value.([]byte)) {
-			toRemove = e
-			break
-		}
-	}
-	if toRemove != nil {
-		_, err := removeTransaction(f, toRemove)
-		if err != nil {
+	if e, ok := f.hashMap[crypto.GetHashStringFromBytes(tx)]; ok {
+		if _, err := removeTransaction(f, e); err != nil {
 			return err
 		}
 	}
@@ -92,6 +105,8 @@ func (f *FIFOMempool) DeleteTransaction(tx []byte) Error {
 }
 
 func (f *FIFOMempool) PopTransaction() ([]byte, Error) {
+	f.l.Lock()
+	defer f.l.Unlock()
 	tx, err := popTransaction(f)
 	if err != nil {
 		return nil, err
@@ -103,7 +118,7 @@ func (f *FIFOMempool) Clear() {
 	f.l.Lock()
 	defer f.l.Unlock()
 	f.pool = list.New()
-	f.hashMap = make(map[string]struct{})
+	f.hashMap = make(map[string]*list.Element)
 	f.size = 0
 	f.transactionBytes = 0
 }
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/pokt-network/pocket/shared/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMempool_GetTransaction(t *testing.T) {
+	mempool := NewMempool(1000, 10)
+	tx1, tx2 := []byte("tx1"), []byte("tx2")
+	tx1Hash, tx2Hash := crypto.GetHashStringFromBytes(tx1), crypto.GetHashStringFromBytes(tx2)
+
+	require.NoError(t, mempool.AddTransaction(tx1))
+	require.NoError(t, mempool.AddTransaction(tx2))
+
+	tx, found := mempool.GetTransaction(tx2Hash)
+	require.True(t, found)
+	require.Equal(t, tx2, tx)
+
+	popped, err := mempool.PopTransaction()
+	require.NoError(t, err)
+	require.Equal(t, tx1, popped)
+
+	_, found = mempool.GetTransaction(tx1Hash)
+	require.False(t, found)
+	require.False(t, mempool.Contains(tx1Hash))
+	require.True(t, mempool.Contains(tx2Hash))
+}
+
+func TestMempool_DeleteTransaction(t *testing.T) {
+	mempool := NewMempool(1000, 10)
+	tx1, tx2, tx3 := []byte("tx1"), []byte("tx2"), []byte("tx3")
+	for _, tx := range [][]byte{tx1, tx2, tx3} {
+		require.NoError(t, mempool.AddTransaction(tx))
+	}
+
+	require.NoError(t, mempool.DeleteTransaction(tx2))
+	require.False(t, mempool.Contains(crypto.GetHashStringFromBytes(tx2)))
+	require.Equal(t, 2, mempool.Size())
+	require.Equal(t, len(tx1)+len(tx3), mempool.TxsBytes())
+	require.Equal(t, []string{crypto.GetHashStringFromBytes(tx1), crypto.GetHashStringFromBytes(tx3)}, mempool.TxHashes())
+
+	// deleting a transaction which is not in the mempool is a no-op
+	require.NoError(t, mempool.DeleteTransaction(tx2))
+	require.Equal(t, 2, mempool.Size())
+}
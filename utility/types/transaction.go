@@ -2,9 +2,11 @@ package types
 
 import (
 	"bytes"
+	"encoding/hex"
 
 	"github.com/pokt-network/pocket/shared/codec"
 	"github.com/pokt-network/pocket/shared/crypto"
+	"github.com/pokt-network/pocket/shared/indexer"
 )
 
 func TransactionFromBytes(transaction []byte) (*Transaction, Error) {
@@ -111,3 +113,41 @@ func (tx *Transaction) Equals(tx2 *Transaction) bool {
 func TransactionHash(transactionProtoBytes []byte) string {
 	return crypto.GetHashStringFromBytes(transactionProtoBytes)
 }
+
+// TxResult returns the result of applying the transaction, found at `index` within the block at `height`, to be
+// indexed. `txErr` is the error the transaction failed with, if any. The result is built from the bytes of the
+// transaction in the block so it is indexed under the same hash as `TransactionHash`.
+func TxResult(transactionProtoBytes []byte, tx *Transaction, height int64, index int, txErr Error) (*indexer.DefaultTxResult, Error) {
+	msg, err := tx.Message()
+	if err != nil {
+		return nil, err
+	}
+	publicKey, er := crypto.NewPublicKeyFromBytes(tx.Signature.PublicKey)
+	if er != nil {
+		return nil, ErrNewPublicKeyFromBytes(er)
+	}
+	result := &indexer.DefaultTxResult{
+		Tx:            transactionProtoBytes,
+		Height:        height,
+		Index:         int32(index),
+		SignerAddr:    publicKey.Address().String(),
+		RecipientAddr: getMessageRecipient(msg),
+		MessageType:   string(msg.ProtoReflect().Descriptor().Name()),
+	}
+	if txErr != nil {
+		result.ResultCode = int32(txErr.Code())
+		result.Error = txErr.Error()
+	}
+	return result, nil
+}
+
+// getMessageRecipient returns the address of who receives the tokens sent by the message, or an empty string if
+// the message does not send tokens to anyone.
+func getMessageRecipient(msg Message) string {
+	switch msg := msg.(type) {
+	case *MessageSend:
+		return hex.EncodeToString(msg.ToAddress)
+	default:
+		return ""
+	}
+}